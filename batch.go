@@ -0,0 +1,163 @@
+package bn
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn/internal/service"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// ErrBatchNotSent error when reading the result of a batch command before the batch is sent.
+var ErrBatchNotSent = errors.New("batch has not been sent")
+
+// BatchClient interfaces queuing commands into a single JSON-RPC batch request.
+type BatchClient interface {
+	Batch() *Batch
+}
+
+// NewBatchClient returns a client only capable of batching commands to a bitcoin node.
+func NewBatchClient(oo ...BitcoinClientOptFunc) BatchClient {
+	return NewNodeClient(oo...)
+}
+
+// Batch queues commands to be sent to the bitcoin node in a single JSON-RPC batch request.
+// Each queued command returns a BatchResult which is populated once Send returns.
+//
+//	b := c.Batch()
+//	hashes := make([]*bn.BatchResult[string], 0, 1000)
+//	for h := 0; h < 1000; h++ {
+//		hashes = append(hashes, b.BlockHash(h))
+//	}
+//	if err := b.Send(ctx); err != nil {}
+//	hash, err := hashes[0].Result()
+type Batch struct {
+	c     *client
+	calls []*service.Call
+}
+
+// BatchResult the typed result of a single command in a batch.
+type BatchResult[T any] struct {
+	call  *service.Call
+	value T
+}
+
+// Result returns the value and error the node replied with for this command.
+func (r *BatchResult[T]) Result() (T, error) {
+	return r.value, r.call.Err
+}
+
+// Batch returns a new, empty batch.
+func (c *client) Batch() *Batch {
+	return &Batch{c: c}
+}
+
+// Len returns the number of commands queued in the batch.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Send the queued commands to the node. A returned error means the batch as a whole failed,
+// in which case it is also set as the error of every result.
+func (b *Batch) Send(ctx context.Context) error {
+	for _, call := range b.calls {
+		call.Err = nil
+	}
+
+	if err := service.DoBatch(ctx, b.c.rpc, b.calls...); err != nil {
+		for _, call := range b.calls {
+			call.Err = err
+		}
+		return err
+	}
+
+	return nil
+}
+
+// BestBlockHash queues a request for the hash of the best block in the longest blockchain.
+func (b *Batch) BestBlockHash() *BatchResult[string] {
+	r := &BatchResult[string]{}
+	return queue(b, r, &r.value, "getbestblockhash")
+}
+
+// BlockCount queues a request for the number of blocks in the longest blockchain.
+func (b *Batch) BlockCount() *BatchResult[uint32] {
+	r := &BatchResult[uint32]{}
+	return queue(b, r, &r.value, "getblockcount")
+}
+
+// BlockHash queues a request for the hash of the block at a given height.
+func (b *Batch) BlockHash(height int) *BatchResult[string] {
+	r := &BatchResult[string]{}
+	return queue(b, r, &r.value, "getblockhash", height)
+}
+
+// BlockHeader queues a request for the block header of a given block hash.
+func (b *Batch) BlockHeader(hash string) *BatchResult[*models.BlockHeader] {
+	r := &BatchResult[*models.BlockHeader]{value: &models.BlockHeader{BlockHeader: &bc.BlockHeader{}}}
+	return queue(b, r, r.value, "getblockheader", hash, true)
+}
+
+// BlockHeaderHex queues a request for the raw hex block header of a given block hash.
+func (b *Batch) BlockHeaderHex(hash string) *BatchResult[string] {
+	r := &BatchResult[string]{}
+	return queue(b, r, &r.value, "getblockheader", hash, false)
+}
+
+// BlockHex queues a request for the raw hex representation of a block given its hash.
+func (b *Batch) BlockHex(hash string) *BatchResult[string] {
+	r := &BatchResult[string]{}
+	return queue(b, r, &r.value, "getblock", hash, models.VerbosityRawBlock)
+}
+
+// Block queues a request for the block data of a given block hash.
+func (b *Batch) Block(hash string) *BatchResult[*models.Block] {
+	r := &BatchResult[*models.Block]{value: &models.Block{BlockHeader: models.BlockHeader{BlockHeader: &bc.BlockHeader{}}}}
+	return queue(b, r, r.value, "getblock", hash, models.VerbosityDecodeTransactions)
+}
+
+// BlockStats queues a request for the statistics of a block given its hash.
+func (b *Batch) BlockStats(hash string, fields ...string) *BatchResult[*models.BlockStats] {
+	r := &BatchResult[*models.BlockStats]{value: &models.BlockStats{}}
+	return queue(b, r, r.value, "getblockstats", hash, fields)
+}
+
+// BlockStatsByHeight queues a request for the statistics of a block given its height.
+func (b *Batch) BlockStatsByHeight(height int, fields ...string) *BatchResult[*models.BlockStats] {
+	r := &BatchResult[*models.BlockStats]{value: &models.BlockStats{}}
+	return queue(b, r, r.value, "getblockstatsbyheight", height, fields)
+}
+
+// MempoolEntry queues a request for the mempool entry of a transaction.
+func (b *Batch) MempoolEntry(txID string) *BatchResult[*models.MempoolEntry] {
+	r := &BatchResult[*models.MempoolEntry]{value: &models.MempoolEntry{}}
+	return queue(b, r, r.value, "getmempoolentry", txID)
+}
+
+// RawTransaction queues a request for a transaction by its ID.
+func (b *Batch) RawTransaction(txID string) *BatchResult[*bt.Tx] {
+	r := &BatchResult[*bt.Tx]{value: &bt.Tx{}}
+	return queue(b, r, r.value, "getrawtransaction", txID, true)
+}
+
+// Output queues a request for the output details of a transaction ID and output index.
+func (b *Batch) Output(txID string, n int, opts *models.OptsOutput) *BatchResult[*models.Output] {
+	r := &BatchResult[*models.Output]{value: &models.Output{Output: &bt.Output{}}}
+	return queue(b, r, r.value, "gettxout", b.c.argsFor(opts, txID, n)...)
+}
+
+// queue appends a call to the batch, decoding its reply into out.
+func queue[T any](b *Batch, r *BatchResult[T], out interface{}, method string, args ...interface{}) *BatchResult[T] {
+	r.call = &service.Call{
+		Method: method,
+		Args:   args,
+		Out:    out,
+		Err:    ErrBatchNotSent,
+	}
+	b.calls = append(b.calls, r.call)
+
+	return r
+}
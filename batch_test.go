@@ -0,0 +1,60 @@
+package bn_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/internal/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// TestBatchClientBatch tests the Batch builder of the BatchClient.
+func TestBatchClientBatch(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := &models.Error{Code: -5, Message: "Block not found"}
+	c := bn.NewBatchClient(bn.WithCustomRPC(&mocks.MockRPC{
+		DoFunc: func(_ context.Context, method string, out interface{}, args ...interface{}) error {
+			switch method {
+			case "getblockhash":
+				*out.(*string) = map[int]string{1: "hash1", 2: "hash2"}[args[0].(int)]
+			case "getblockcount":
+				*out.(*uint32) = 700000
+			case "getmempoolentry":
+				return errNotFound
+			}
+			return nil
+		},
+	}))
+
+	b := c.Batch()
+	hash1 := b.BlockHash(1)
+	hash2 := b.BlockHash(2)
+	count := b.BlockCount()
+	entry := b.MempoolEntry("abc")
+	assert.Equal(t, 4, b.Len())
+
+	_, err := hash1.Result()
+	require.ErrorIs(t, err, bn.ErrBatchNotSent)
+
+	require.NoError(t, b.Send(context.TODO()))
+
+	h, err := hash1.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "hash1", h)
+
+	h, err = hash2.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "hash2", h)
+
+	n, err := count.Result()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(700000), n)
+
+	_, err = entry.Result()
+	assert.ErrorIs(t, err, errNotFound)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/bsv-blockchain/go-bc"
 	"github.com/bsv-blockchain/go-bt/v2"
 
+	"github.com/bsv-blockchain/go-bn/internal/service"
 	"github.com/bsv-blockchain/go-bn/models"
 )
 
@@ -14,6 +15,8 @@ type BlockChainClient interface {
 	BestBlockHash(ctx context.Context) (string, error)
 	BlockHex(ctx context.Context, hash string) (string, error)
 	BlockHexByHeight(ctx context.Context, height int) (string, error)
+	BlockStream(ctx context.Context, hash string) (*models.BlockStream, error)
+	BlockStreamByHeight(ctx context.Context, height int) (*models.BlockStream, error)
 	BlockDecodeHeader(ctx context.Context, hash string) (*models.BlockDecodeHeader, error)
 	BlockDecodeHeaderByHeight(ctx context.Context, height int) (*models.BlockDecodeHeader, error)
 	Block(ctx context.Context, hash string) (*models.Block, error)
@@ -74,6 +77,27 @@ func (c *client) BlockHexByHeight(ctx context.Context, height int) (string, erro
 	return resp, c.rpc.Do(ctx, "getblockbyheight", &resp, height, models.VerbosityRawBlock)
 }
 
+// BlockStream returns the serialized block for a given block hash, decoded incrementally as
+// the response arrives so that blocks too large to hold in memory can be read. The stream
+// must be closed. It is bounded by ctx rather than the client timeout.
+func (c *client) BlockStream(ctx context.Context, hash string) (*models.BlockStream, error) {
+	rc, err := service.DoStream(ctx, c.rpc, "getblock", hash, models.VerbosityRawBlock)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBlockStream(rc)
+}
+
+// BlockStreamByHeight returns the serialized block for a given block height, decoded
+// incrementally as the response arrives. The stream must be closed.
+func (c *client) BlockStreamByHeight(ctx context.Context, height int) (*models.BlockStream, error) {
+	rc, err := service.DoStream(ctx, c.rpc, "getblockbyheight", height, models.VerbosityRawBlock)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBlockStream(rc)
+}
+
 // BlockDecodeHeader returns the decoded block header for a given block hash.
 func (c *client) BlockDecodeHeader(ctx context.Context, hash string) (*models.BlockDecodeHeader, error) {
 	resp := models.BlockDecodeHeader{BlockHeader: models.BlockHeader{BlockHeader: &bc.BlockHeader{}}}
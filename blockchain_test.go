@@ -0,0 +1,92 @@
+package bn_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+func TestBlockChainClientBlockStream(t *testing.T) {
+	t.Parallel()
+
+	const (
+		header = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+		tx1    = "0200000001fbb877c83aaf682f74611628b0088254c8094fff9cf6328ed969c587112b8fc90000000000feffffff025e2e1a1e010000001976a91401becd83278806a62cd87bed129faa72af38a0d588ac00e1f505000000001976a91467e701e630adaee761583a894b53d4356028ca0b88ac00000000"
+		tx2    = "0200000001c9059cca32a90834a9ea6e989446edb4282e91bba486f4512477052214b185df0000000048473044022056e7348677c69dbcba776fbe0c270116c2a3eaf0bead0c1ccdbd9c083b73a08e022062da00341e54a28bb83b28dfd772c9504f5aace3452e762dc30dff249a378c0a41feffffff0240101024010000001976a914316230517501a16e2837465ec28c157fa61cabec88ac00e1f505000000001976a914beb20631d5271a6e150231e625bccff55a58cbea88ac70000000"
+	)
+
+	tests := map[string]struct {
+		block     string
+		expTxs    []string
+		expErr    bool
+		stopEarly bool
+	}{
+		"successful query": {
+			block:  header + "02" + tx1 + tx2,
+			expTxs: []string{tx1, tx2},
+		},
+		"iteration can stop early": {
+			block:     header + "02" + tx1 + tx2,
+			expTxs:    []string{tx1},
+			stopEarly: true,
+		},
+		"truncated block": {
+			block:  header + "02" + tx1 + tx2[:40],
+			expTxs: []string{tx1},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req models.Request
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "getblock", req.Method)
+				assert.Equal(t, []interface{}{"abc", string(models.VerbosityRawBlock)}, req.Params)
+
+				_, _ = w.Write([]byte(`{"result":"` + test.block + `","error":null,"id":"go-bn"}`))
+			}))
+			defer svr.Close()
+
+			c := bn.NewBlockChainClient(bn.WithHost(svr.URL))
+
+			blk, err := c.BlockStream(context.TODO(), "abc")
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, blk.Close())
+			}()
+
+			assert.Equal(t, header, hex.EncodeToString(blk.Header.Bytes()))
+			assert.Equal(t, uint64(2), blk.TxCount)
+
+			var txs []string
+			var iterErr error
+			for tx, err := range blk.Txs() {
+				if err != nil {
+					iterErr = err
+					break
+				}
+				txs = append(txs, tx.String())
+				if test.stopEarly {
+					break
+				}
+			}
+			assert.Equal(t, test.expTxs, txs)
+			if test.expErr {
+				assert.Error(t, iterErr)
+			} else {
+				assert.NoError(t, iterErr)
+			}
+		})
+	}
+}
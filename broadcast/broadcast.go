@@ -0,0 +1,262 @@
+// Package broadcast submits transactions to several bitcoin nodes at once, succeeding
+// when a quorum of them accept.
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Client a bn.TransactionClient which sends SendRawTransaction and SendRawTransactions
+// to every node in parallel. All other methods are served by the first node.
+type Client interface {
+	bn.TransactionClient
+	Broadcast(ctx context.Context, tx *bt.Tx, opts *models.OptsSendRawTransaction) (*Result, error)
+	BroadcastMany(ctx context.Context, params ...models.ParamsSendRawTransactions) (*ManyResult, error)
+}
+
+type client struct {
+	nodes []bn.TransactionClient
+	cfg   *clientCfg
+}
+
+// NewClient returns a client broadcasting to the provided nodes, configured via the
+// provided opt funcs. ErrNoNodes is returned when no nodes are provided, and ErrInvalidQuorum
+// when the quorum could never be reached.
+func NewClient(nodes []bn.TransactionClient, oo ...ClientOptFunc) (Client, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+
+	cfg := &clientCfg{
+		quorum: len(nodes)/2 + 1,
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+	if cfg.quorum < 1 || cfg.quorum > len(nodes) {
+		return nil, fmt.Errorf("%w: %d of %d nodes", ErrInvalidQuorum, cfg.quorum, len(nodes))
+	}
+
+	return &client{
+		nodes: nodes,
+		cfg:   cfg,
+	}, nil
+}
+
+// Broadcast sends the transaction to every node, returning the result from each. An *Error
+// is returned when fewer nodes than the quorum accept it. Nodes replying that the transaction
+// is already known, or already in the block chain, count as accepting.
+func (c *client) Broadcast(ctx context.Context, tx *bt.Tx,
+	opts *models.OptsSendRawTransaction,
+) (*Result, error) {
+	r := &Result{
+		TxID:  tx.TxID(),
+		Nodes: make([]NodeResult, len(c.nodes)),
+	}
+
+	var wg sync.WaitGroup
+	for i, n := range c.nodes {
+		wg.Go(func() {
+			txID, err := n.SendRawTransaction(ctx, tx, opts)
+			r.Nodes[i] = NodeResult{Node: i, TxID: txID, Err: err}
+		})
+	}
+	wg.Wait()
+
+	return r, c.settle(ctx, r)
+}
+
+// BroadcastMany sends the transactions to every node, returning the merged response along
+// with the result of each transaction from each node. An *Error is returned only when fewer
+// nodes than the quorum answer at all, transactions failing to reach quorum otherwise being
+// listed as invalid or evicted in the merged response.
+func (c *client) BroadcastMany(ctx context.Context,
+	params ...models.ParamsSendRawTransactions,
+) (*ManyResult, error) {
+	txIDs := make([]string, len(params))
+	for i, p := range params {
+		tx, err := bt.NewTxFromString(p.Hex)
+		if err != nil {
+			return nil, err
+		}
+		txIDs[i] = tx.TxID()
+	}
+
+	resps := make([]*models.SendRawTransactionsResponse, len(c.nodes))
+	errs := make([]error, len(c.nodes))
+
+	var wg sync.WaitGroup
+	for i, n := range c.nodes {
+		wg.Go(func() {
+			resps[i], errs[i] = n.SendRawTransactions(ctx, params...)
+		})
+	}
+	wg.Wait()
+
+	answered := &Result{Nodes: make([]NodeResult, len(c.nodes))}
+	for i := range c.nodes {
+		answered.Nodes[i] = NodeResult{Node: i, Err: errs[i]}
+	}
+	if answered.Accepted() < c.cfg.quorum {
+		return nil, &Error{Result: answered}
+	}
+
+	mr := &ManyResult{
+		Response: &models.SendRawTransactionsResponse{},
+		Txs:      make([]*Result, len(txIDs)),
+	}
+	for i, txID := range txIDs {
+		r := &Result{
+			TxID:  txID,
+			Nodes: make([]NodeResult, len(c.nodes)),
+		}
+		for j, resp := range resps {
+			r.Nodes[j] = NodeResult{Node: j, TxID: txID, Err: errs[j]}
+			if errs[j] == nil {
+				r.Nodes[j].Err = txErr(resp, txID)
+			}
+		}
+		mr.Txs[i] = r
+
+		if err := c.settle(ctx, r); err == nil {
+			continue
+		}
+		merge(mr.Response, resps, r)
+	}
+
+	seen := map[string]bool{}
+	for i, resp := range resps {
+		if errs[i] != nil {
+			continue
+		}
+		for _, txID := range resp.Known {
+			if !seen[txID] {
+				seen[txID] = true
+				mr.Response.Known = append(mr.Response.Known, txID)
+			}
+		}
+		for _, u := range resp.Unconfirmed {
+			if !seen[u.TxID] {
+				seen[u.TxID] = true
+				mr.Response.Unconfirmed = append(mr.Response.Unconfirmed, u)
+			}
+		}
+	}
+
+	return mr, nil
+}
+
+// SendRawTransaction broadcasts the transaction to every node, returning its ID once a
+// quorum accept.
+func (c *client) SendRawTransaction(ctx context.Context, tx *bt.Tx,
+	opts *models.OptsSendRawTransaction,
+) (string, error) {
+	r, err := c.Broadcast(ctx, tx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return r.TxID, nil
+}
+
+// SendRawTransactions broadcasts the transactions to every node, returning the merged response.
+func (c *client) SendRawTransactions(ctx context.Context,
+	params ...models.ParamsSendRawTransactions,
+) (*models.SendRawTransactionsResponse, error) {
+	mr, err := c.BroadcastMany(ctx, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr.Response, nil
+}
+
+// AddToConfiscationTransactionWhitelist adds confiscation transactions to the whitelist of the first node.
+func (c *client) AddToConfiscationTransactionWhitelist(ctx context.Context,
+	funds []models.ConfiscationTransactionDetails,
+) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
+	return c.nodes[0].AddToConfiscationTransactionWhitelist(ctx, funds)
+}
+
+// AddToConsensusBlacklist adds funds to the consensus blacklist of the first node.
+func (c *client) AddToConsensusBlacklist(ctx context.Context,
+	funds []models.Fund,
+) (*models.AddToConsensusBlacklistResponse, error) {
+	return c.nodes[0].AddToConsensusBlacklist(ctx, funds)
+}
+
+// CreateRawTransaction creates a raw transaction on the first node.
+func (c *client) CreateRawTransaction(ctx context.Context, utxos bt.UTXOs,
+	params models.ParamsCreateRawTransaction,
+) (*bt.Tx, error) {
+	return c.nodes[0].CreateRawTransaction(ctx, utxos, params)
+}
+
+// FundRawTransaction funds a raw transaction on the first node.
+func (c *client) FundRawTransaction(ctx context.Context, tx *bt.Tx,
+	opts *models.OptsFundRawTransaction,
+) (*models.FundRawTransaction, error) {
+	return c.nodes[0].FundRawTransaction(ctx, tx, opts)
+}
+
+// RawTransaction retrieves a raw transaction by its ID from the first node.
+func (c *client) RawTransaction(ctx context.Context, txID string) (*bt.Tx, error) {
+	return c.nodes[0].RawTransaction(ctx, txID)
+}
+
+// SignRawTransaction signs a raw transaction on the first node.
+func (c *client) SignRawTransaction(ctx context.Context, tx *bt.Tx,
+	opts *models.OptsSignRawTransaction,
+) (*models.SignedRawTransaction, error) {
+	return c.nodes[0].SignRawTransaction(ctx, tx, opts)
+}
+
+// settle reports conflicting results and checks the transaction reached quorum.
+func (c *client) settle(ctx context.Context, r *Result) error {
+	if c.cfg.onConflict != nil && r.Conflicting() {
+		c.cfg.onConflict(ctx, r)
+	}
+	if r.Accepted() < c.cfg.quorum {
+		return &Error{Result: r}
+	}
+
+	return nil
+}
+
+// txErr returns the error a node reported for the transaction within its response, nil if accepted.
+func txErr(resp *models.SendRawTransactionsResponse, txID string) error {
+	for _, inv := range resp.Invalid {
+		if inv.TxID == txID {
+			return inv.Err()
+		}
+	}
+	for _, evicted := range resp.Evicted {
+		if evicted == txID {
+			return ErrEvicted
+		}
+	}
+
+	return nil
+}
+
+// merge lists a transaction which failed to reach quorum in the merged response, taking
+// the reason for rejection from the first node to reject it.
+func merge(merged *models.SendRawTransactionsResponse, resps []*models.SendRawTransactionsResponse, r *Result) {
+	for _, n := range r.Rejected() {
+		for _, inv := range resps[n.Node].Invalid {
+			if inv.TxID == r.TxID {
+				merged.Invalid = append(merged.Invalid, inv)
+				return
+			}
+		}
+	}
+
+	merged.Evicted = append(merged.Evicted, r.TxID)
+}
@@ -0,0 +1,263 @@
+package broadcast_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/broadcast"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+const (
+	txHex        = "0200000001fbb877c83aaf682f74611628b0088254c8094fff9cf6328ed969c587112b8fc90000000000feffffff025e2e1a1e010000001976a91401becd83278806a62cd87bed129faa72af38a0d588ac00e1f505000000001976a91467e701e630adaee761583a894b53d4356028ca0b88ac00000000"
+	anotherTxHex = "0200000001c9059cca32a90834a9ea6e989446edb4282e91bba486f4512477052214b185df0000000048473044022056e7348677c69dbcba776fbe0c270116c2a3eaf0bead0c1ccdbd9c083b73a08e022062da00341e54a28bb83b28dfd772c9504f5aace3452e762dc30dff249a378c0a41feffffff0240101024010000001976a914316230517501a16e2837465ec28c157fa61cabec88ac00e1f505000000001976a914beb20631d5271a6e150231e625bccff55a58cbea88ac70000000"
+)
+
+func TestClient_Broadcast(t *testing.T) {
+	t.Parallel()
+
+	tx, err := bt.NewTxFromString(txHex)
+	require.NoError(t, err)
+
+	missingInputs := &models.Error{Code: -25, Message: "Missing inputs"}
+	tests := map[string]struct {
+		errs         []error
+		opts         []broadcast.ClientOptFunc
+		expAccepted  int
+		expConflicts int
+		expErr       []error
+	}{
+		"all accept": {
+			errs:        []error{nil, nil, nil},
+			expAccepted: 3,
+		},
+		"already known counts as accepted": {
+			errs: []error{
+				&models.Error{Code: -26, Message: "257: txn-already-known"},
+				&models.Error{Code: -27, Message: "Transaction already in block chain"},
+				nil,
+			},
+			expAccepted: 3,
+		},
+		"majority accept with conflicting rejection": {
+			errs:         []error{nil, missingInputs, nil},
+			expAccepted:  2,
+			expConflicts: 1,
+		},
+		"unreachable node is not a conflict": {
+			errs:        []error{nil, syscall.ECONNREFUSED, nil},
+			expAccepted: 2,
+		},
+		"quorum not reached": {
+			errs:         []error{missingInputs, missingInputs, nil},
+			expAccepted:  1,
+			expConflicts: 1,
+			expErr:       []error{broadcast.ErrQuorumNotReached, models.ErrMissingInputs},
+		},
+		"custom quorum": {
+			errs:        []error{nil, syscall.ECONNREFUSED, nil},
+			opts:        []broadcast.ClientOptFunc{broadcast.WithQuorum(3)},
+			expAccepted: 2,
+			expErr:      []error{broadcast.ErrQuorumNotReached, syscall.ECONNREFUSED},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := make([]bn.TransactionClient, len(test.errs))
+			for i, err := range test.errs {
+				nodes[i] = &mocks.TransactionClientMock{
+					SendRawTransactionFunc: func(context.Context, *bt.Tx, *models.OptsSendRawTransaction) (string, error) {
+						if err != nil {
+							return "", err
+						}
+						return tx.TxID(), nil
+					},
+				}
+			}
+
+			var conflicts int
+			opts := append([]broadcast.ClientOptFunc{
+				broadcast.WithConflictHandler(func(_ context.Context, r *broadcast.Result) {
+					conflicts++
+					assert.Equal(t, tx.TxID(), r.TxID)
+				}),
+			}, test.opts...)
+			c, err := broadcast.NewClient(nodes, opts...)
+			require.NoError(t, err)
+
+			r, err := c.Broadcast(context.TODO(), tx, nil)
+			require.NotNil(t, r)
+			assert.Equal(t, test.expAccepted, r.Accepted())
+			assert.Equal(t, test.expConflicts, conflicts)
+			if test.expErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				for _, exp := range test.expErr {
+					assert.ErrorIs(t, err, exp)
+				}
+			}
+
+			txID, err := c.SendRawTransaction(context.TODO(), tx, nil)
+			if test.expErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tx.TxID(), txID)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_BroadcastMany(t *testing.T) {
+	t.Parallel()
+
+	tx, err := bt.NewTxFromString(txHex)
+	require.NoError(t, err)
+	anotherTx, err := bt.NewTxFromString(anotherTxHex)
+	require.NoError(t, err)
+
+	response := func(s string) *models.SendRawTransactionsResponse {
+		var resp models.SendRawTransactionsResponse
+		require.NoError(t, json.Unmarshal([]byte(s), &resp))
+		return &resp
+	}
+	accepted := response(`{}`)
+	conflict := response(`{"invalid":[{"txid":"` + anotherTx.TxID() + `","reject_code":258,"reject_reason":"txn-mempool-conflict"}]}`)
+	alreadyKnown := response(`{"known":["` + tx.TxID() + `"]}`)
+
+	tests := map[string]struct {
+		resps        []*models.SendRawTransactionsResponse
+		errs         []error
+		expResponse  *models.SendRawTransactionsResponse
+		expAccepted  []int
+		expConflicts int
+		expErr       error
+	}{
+		"all accept": {
+			resps:       []*models.SendRawTransactionsResponse{alreadyKnown, accepted, accepted},
+			errs:        []error{nil, nil, nil},
+			expResponse: response(`{"known":["` + tx.TxID() + `"]}`),
+			expAccepted: []int{3, 3},
+		},
+		"rejection below quorum is a conflict": {
+			resps:        []*models.SendRawTransactionsResponse{accepted, conflict, accepted},
+			errs:         []error{nil, nil, nil},
+			expResponse:  response(`{}`),
+			expAccepted:  []int{3, 2},
+			expConflicts: 1,
+		},
+		"rejection above quorum is invalid": {
+			resps:        []*models.SendRawTransactionsResponse{conflict, conflict, accepted},
+			errs:         []error{nil, nil, nil},
+			expResponse:  conflict,
+			expAccepted:  []int{3, 1},
+			expConflicts: 1,
+		},
+		"too few nodes answer": {
+			resps:  []*models.SendRawTransactionsResponse{nil, nil, accepted},
+			errs:   []error{syscall.ECONNREFUSED, errors.New("timeout"), nil},
+			expErr: broadcast.ErrQuorumNotReached,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := make([]bn.TransactionClient, len(test.resps))
+			for i, resp := range test.resps {
+				nodes[i] = &mocks.TransactionClientMock{
+					SendRawTransactionsFunc: func(context.Context, ...models.ParamsSendRawTransactions) (*models.SendRawTransactionsResponse, error) {
+						return resp, test.errs[i]
+					},
+				}
+			}
+
+			var conflicts int
+			c, err := broadcast.NewClient(nodes, broadcast.WithConflictHandler(func(_ context.Context, r *broadcast.Result) {
+				conflicts++
+				assert.Equal(t, anotherTx.TxID(), r.TxID)
+			}))
+			require.NoError(t, err)
+
+			r, err := c.BroadcastMany(context.TODO(),
+				models.ParamsSendRawTransactions{Hex: txHex},
+				models.ParamsSendRawTransactions{Hex: anotherTxHex},
+			)
+			if test.expErr != nil {
+				require.ErrorIs(t, err, test.expErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expResponse, r.Response)
+			accepted := make([]int, len(r.Txs))
+			for i, tx := range r.Txs {
+				accepted[i] = tx.Accepted()
+			}
+			assert.Equal(t, test.expAccepted, accepted)
+			assert.Equal(t, test.expConflicts, conflicts)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	nodes := []bn.TransactionClient{&mocks.TransactionClientMock{}, &mocks.TransactionClientMock{}}
+	tests := map[string]struct {
+		nodes  []bn.TransactionClient
+		opts   []broadcast.ClientOptFunc
+		expErr error
+	}{
+		"default quorum": {
+			nodes: nodes,
+		},
+		"quorum of every node": {
+			nodes: nodes,
+			opts:  []broadcast.ClientOptFunc{broadcast.WithQuorum(2)},
+		},
+		"no nodes": {
+			expErr: broadcast.ErrNoNodes,
+		},
+		"quorum above node count": {
+			nodes:  nodes,
+			opts:   []broadcast.ClientOptFunc{broadcast.WithQuorum(3)},
+			expErr: broadcast.ErrInvalidQuorum,
+		},
+		"zero quorum": {
+			nodes:  nodes,
+			opts:   []broadcast.ClientOptFunc{broadcast.WithQuorum(0)},
+			expErr: broadcast.ErrInvalidQuorum,
+		},
+		"negative quorum": {
+			nodes:  nodes,
+			opts:   []broadcast.ClientOptFunc{broadcast.WithQuorum(-1)},
+			expErr: broadcast.ErrInvalidQuorum,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := broadcast.NewClient(test.nodes, test.opts...)
+			if test.expErr != nil {
+				require.ErrorIs(t, err, test.expErr)
+				assert.Nil(t, c)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, c)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package broadcast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Standard errors.
+var (
+	ErrNoNodes          = errors.New("no nodes to broadcast to")
+	ErrInvalidQuorum    = errors.New("quorum must be between 1 and the number of nodes")
+	ErrQuorumNotReached = errors.New("broadcast quorum not reached")
+	ErrEvicted          = errors.New("transaction evicted from mempool")
+)
+
+// Error returned when fewer nodes than the quorum accept a transaction, holding the
+// result from each node. The error of each node is wrapped, so the reason for rejection
+// can be checked with errors.Is:
+//
+//	if errors.Is(err, models.ErrMissingInputs) {
+//		// At least one node is missing a parent.
+//	}
+type Error struct {
+	Result *Result
+}
+
+// Error returns the reason the broadcast failed on each node.
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(ErrQuorumNotReached.Error())
+	if e.Result.TxID != "" {
+		sb.WriteString(" for tx " + e.Result.TxID)
+	}
+	_, _ = fmt.Fprintf(&sb, ": %d of %d nodes accepted", e.Result.Accepted(), len(e.Result.Nodes))
+	for _, n := range e.Result.Nodes {
+		if n.Err != nil && !n.Accepted() {
+			_, _ = fmt.Fprintf(&sb, "; node %d: %s", n.Node, n.Err)
+		}
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns ErrQuorumNotReached and the error of each node.
+func (e *Error) Unwrap() []error {
+	errs := []error{ErrQuorumNotReached}
+	for _, n := range e.Result.Nodes {
+		if n.Err != nil {
+			errs = append(errs, n.Err)
+		}
+	}
+
+	return errs
+}
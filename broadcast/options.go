@@ -0,0 +1,25 @@
+package broadcast
+
+type clientCfg struct {
+	quorum     int
+	onConflict ConflictFunc
+}
+
+// ClientOptFunc option func.
+type ClientOptFunc func(c *clientCfg)
+
+// WithQuorum set the number of nodes which must accept a transaction for the broadcast to
+// succeed, between 1 and the number of nodes. Defaults to a majority of the nodes.
+func WithQuorum(n int) ClientOptFunc {
+	return func(c *clientCfg) {
+		c.quorum = n
+	}
+}
+
+// WithConflictHandler set a handler called for each transaction accepted by some nodes and
+// rejected by others, whether or not the broadcast reached quorum.
+func WithConflictHandler(fn ConflictFunc) ClientOptFunc {
+	return func(c *clientCfg) {
+		c.onConflict = fn
+	}
+}
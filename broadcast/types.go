@@ -0,0 +1,75 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// ConflictFunc handles a transaction accepted by some nodes and rejected by others.
+type ConflictFunc func(ctx context.Context, r *Result)
+
+// NodeResult the outcome of submitting a transaction to a single node. Node is the
+// index of the node within those the client was built with.
+type NodeResult struct {
+	Node int
+	TxID string
+	Err  error
+}
+
+// Accepted reports whether the node accepted the transaction, or already knew of it.
+func (r NodeResult) Accepted() bool {
+	return r.Err == nil || errors.Is(r.Err, models.ErrTxAlreadyKnown) || errors.Is(r.Err, models.ErrTxAlreadyInChain)
+}
+
+// Rejected reports whether the node rejected the transaction, as opposed to failing
+// to answer.
+func (r NodeResult) Rejected() bool {
+	var rpcErr *models.Error
+	return !r.Accepted() && (errors.As(r.Err, &rpcErr) || errors.Is(r.Err, ErrEvicted))
+}
+
+// Result the outcome of submitting a transaction to every node.
+type Result struct {
+	TxID  string
+	Nodes []NodeResult
+}
+
+// Accepted returns the number of nodes which accepted the transaction.
+func (r *Result) Accepted() int {
+	var n int
+	for _, node := range r.Nodes {
+		if node.Accepted() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Rejected returns the results of the nodes which rejected the transaction.
+func (r *Result) Rejected() []NodeResult {
+	var rejected []NodeResult
+	for _, node := range r.Nodes {
+		if node.Rejected() {
+			rejected = append(rejected, node)
+		}
+	}
+
+	return rejected
+}
+
+// Conflicting reports whether some nodes accepted the transaction while others rejected
+// it, such as one node reporting missing inputs while another accepts.
+func (r *Result) Conflicting() bool {
+	return r.Accepted() > 0 && len(r.Rejected()) > 0
+}
+
+// ManyResult the outcome of submitting several transactions to every node. Response merges
+// the responses of each node, listing a transaction as invalid or evicted only when fewer
+// nodes than the quorum accepted it.
+type ManyResult struct {
+	Response *models.SendRawTransactionsResponse
+	Txs      []*Result
+}
@@ -0,0 +1,51 @@
+package bn
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// CacheClient interfaces inspecting and invalidating the response cache of a client built WithCache.
+type CacheClient interface {
+	CacheStats() models.CacheStats
+	InvalidateCache(scopes ...models.CacheScope)
+}
+
+// CacheStats returns the hit, miss and eviction counts of the response cache. Zero
+// stats are returned when caching is not enabled.
+func (c *client) CacheStats() models.CacheStats {
+	if c.cache == nil {
+		return models.CacheStats{}
+	}
+
+	return c.cache.Stats()
+}
+
+// InvalidateCache drops all cached responses within the given scopes. Invalidating
+// models.CacheScopeTip also invalidates models.CacheScopeMempool.
+func (c *client) InvalidateCache(scopes ...models.CacheScope) {
+	if c.cache == nil {
+		return
+	}
+
+	c.cache.Invalidate(scopes...)
+}
+
+// SubscribeCacheInvalidation subscribes to the `hashblock` and `removedfrommempoolblock` topics
+// of z, invalidating tip and mempool dependent responses cached by c as they arrive. This keeps
+// results such as ChainInfo, BestBlockHash, RawMempool and MempoolEntry from going stale past a
+// block boundary. The topics are shared via zmq.Share, so may also be subscribed to by others.
+func SubscribeCacheInvalidation(z zmq.NodeMQ, c CacheClient) error {
+	z = zmq.Share(z)
+	if err := z.SubscribeHashBlock(func(context.Context, string) {
+		c.InvalidateCache(models.CacheScopeTip)
+	}); err != nil {
+		return err
+	}
+
+	return z.SubscribeRemovedFromMempoolBlock(func(context.Context, *zmq.MempoolDiscard) {
+		c.InvalidateCache(models.CacheScopeMempool)
+	})
+}
@@ -0,0 +1,148 @@
+package bn_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// stubNodeMQ captures the handlers subscribed to a zmq.NodeMQ.
+type stubNodeMQ struct {
+	zmq.NodeMQ
+
+	hashBlockFn zmq.HashFunc
+	removedFn   zmq.DiscardFunc
+}
+
+func (s *stubNodeMQ) SubscribeHashBlock(fn zmq.HashFunc) error {
+	s.hashBlockFn = fn
+	return nil
+}
+
+func (s *stubNodeMQ) SubscribeRemovedFromMempoolBlock(fn zmq.DiscardFunc) error {
+	s.removedFn = fn
+	return nil
+}
+
+// TestSubscribeCacheInvalidation tests cached responses are invalidated by zmq notifications.
+func TestSubscribeCacheInvalidation(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		calls[req.Method]++
+		mu.Unlock()
+
+		var result interface{}
+		switch req.Method {
+		case "getbestblockhash":
+			result = "000000000000000001cd535a5b3ad0fb3ec22d153e845508666818ab29eb27af"
+		case "getmempoolentry":
+			result = models.MempoolEntry{Size: 250}
+		case "getrawmempool":
+			result = []string{}
+		}
+
+		bb, err := json.Marshal(models.Response{Result: result})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer svr.Close()
+
+	c := bn.NewNodeClient(bn.WithHost(svr.URL), bn.WithCache())
+	z := &stubNodeMQ{}
+	require.NoError(t, bn.SubscribeCacheInvalidation(z, c))
+
+	query := func() {
+		_, err := c.BestBlockHash(context.TODO())
+		require.NoError(t, err)
+		_, err = c.MempoolEntry(context.TODO(), "abc")
+		require.NoError(t, err)
+	}
+
+	query()
+	query()
+	assert.Equal(t, map[string]int{"getbestblockhash": 1, "getmempoolentry": 1}, calls)
+
+	z.removedFn(context.TODO(), &zmq.MempoolDiscard{TxID: "abc"})
+	query()
+	assert.Equal(t, map[string]int{"getbestblockhash": 1, "getmempoolentry": 2}, calls)
+
+	z.hashBlockFn(context.TODO(), "000000000000000002d8a4faad6d8c026526bf1a8a2abe2074a42e71f95ebb64")
+	query()
+	assert.Equal(t, map[string]int{"getbestblockhash": 2, "getmempoolentry": 3}, calls)
+
+	stats := c.CacheStats()
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(5), stats.Misses)
+}
+
+// TestCache_BlockHeader tests cached headers report the confirmations of the block after a new
+// block arrives, as trackers rely on them to count confirmations and spot reorgs.
+func TestCache_BlockHeader(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	confirmations, calls := 1, 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "getblockheader", req.Method)
+
+		mu.Lock()
+		calls++
+		bb, err := json.Marshal(models.Response{Result: map[string]interface{}{
+			"hash":              "0000000000000000031c8df6a6bd8ce8bbc0ca4b2b2f3a5e6cb0e8fda1e0b8a1",
+			"confirmations":     confirmations,
+			"height":            700000,
+			"merkleroot":        "7a0e8f4dbf6bdfa2a1c4c1e7f8b73b1b9b4c2c0d9e8f7a6b5c4d3e2f1a0b9c8d",
+			"previousblockhash": "00000000000000000a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071",
+		}})
+		mu.Unlock()
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer svr.Close()
+
+	c := bn.NewNodeClient(bn.WithHost(svr.URL), bn.WithCache())
+	z := &stubNodeMQ{}
+	require.NoError(t, bn.SubscribeCacheInvalidation(z, c))
+
+	confs := func() uint64 {
+		h, err := c.BlockHeader(context.TODO(), "0000000000000000031c8df6a6bd8ce8bbc0ca4b2b2f3a5e6cb0e8fda1e0b8a1")
+		require.NoError(t, err)
+		return h.Confirmations
+	}
+
+	assert.Equal(t, uint64(1), confs())
+	assert.Equal(t, uint64(1), confs())
+
+	mu.Lock()
+	confirmations = 2
+	mu.Unlock()
+	z.hashBlockFn(context.TODO(), "000000000000000002d8a4faad6d8c026526bf1a8a2abe2074a42e71f95ebb64")
+	assert.Equal(t, uint64(2), confs())
+
+	// The block is disconnected in a reorg.
+	mu.Lock()
+	confirmations = -1
+	mu.Unlock()
+	z.hashBlockFn(context.TODO(), "00000000000000000a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071")
+	assert.Equal(t, uint64(0), confs())
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,240 @@
+// Package chain follows the best chain of a bitcoin node, emitting blocks as they are
+// connected and disconnected.
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// Follower tracks the best chain of a node, emitting an Event for each block connected to
+// or disconnected from it.
+type Follower interface {
+	Sync(ctx context.Context) error
+	Run(ctx context.Context) error
+	Subscribe(z zmq.NodeMQ) error
+	OnHashBlock(ctx context.Context, hash string)
+	Tip() *Checkpoint
+}
+
+type follower struct {
+	client bn.BlockChainClient
+	fn     EventFunc
+	cfg    *followerCfg
+	poller *poll.Poller
+
+	syncMu sync.Mutex
+	loaded bool
+
+	mu  sync.Mutex
+	tip *Checkpoint
+}
+
+// NewFollower returns a follower of the best chain of the node behind client, passing each
+// event to fn. It is configured via the provided opt funcs.
+func NewFollower(client bn.BlockChainClient, fn EventFunc, oo ...FollowerOptFunc) Follower {
+	cfg := &followerCfg{
+		pollInterval: DefaultPollInterval,
+		errorFn:      func(context.Context, error) {},
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+	if cfg.checkpointer == nil {
+		cfg.checkpointer = NewMemoryCheckpointer()
+	}
+
+	return &follower{
+		client: client,
+		fn:     fn,
+		cfg:    cfg,
+		poller: poll.New(),
+	}
+}
+
+// Sync brings the follower up to the active chain tip of the node, resuming from the
+// checkpoint. When the last block processed is no longer on the best chain, blocks are
+// disconnected, walking back via HashPrevBlock, until the fork point is reached. The
+// checkpoint is saved after each event is handled.
+func (f *follower) Sync(ctx context.Context) error {
+	return poll.Cause(f.sync(ctx))
+}
+
+// Run syncs the follower each time a block is announced via OnHashBlock, or else every poll
+// interval, until ctx is done. Errors talking to the node are passed to the error handler
+// and retried, while errors returned by the EventFunc or Checkpointer stop it.
+func (f *follower) Run(ctx context.Context) error {
+	return f.poller.Run(ctx, f.cfg.pollInterval, f.sync, f.cfg.errorFn)
+}
+
+// Subscribe has OnHashBlock called for each `hashblock` notification, so new blocks are
+// emitted as soon as the node announces them rather than on the next poll. The topic is
+// shared via zmq.Share, so may also be subscribed to by others.
+func (f *follower) Subscribe(z zmq.NodeMQ) error {
+	return zmq.Share(z).SubscribeHashBlock(f.OnHashBlock)
+}
+
+// OnHashBlock wakes Run to emit the announced block, along with any blocks disconnected by
+// the reorg it causes.
+func (f *follower) OnHashBlock(context.Context, string) {
+	f.poller.Wake()
+}
+
+// Tip returns the last block processed, nil if there is none.
+func (f *follower) Tip() *Checkpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.tip == nil {
+		return nil
+	}
+	cp := *f.tip
+	return &cp
+}
+
+func (f *follower) sync(ctx context.Context) error {
+	f.syncMu.Lock()
+	defer f.syncMu.Unlock()
+
+	if !f.loaded {
+		cp, err := f.cfg.checkpointer.Load(ctx)
+		if err != nil {
+			return err
+		}
+		f.setTip(cp)
+		f.loaded = true
+	}
+
+	best, err := f.activeTip(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if f.tip != nil && f.tip.Hash == best.Hash {
+			return nil
+		}
+
+		if f.tip != nil {
+			onChain, err := f.onChain(ctx, f.tip, best)
+			if err != nil {
+				return err
+			}
+			if !onChain {
+				if err = f.disconnect(ctx); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		height := f.cfg.startHeight
+		if f.tip != nil {
+			height = f.tip.Height + 1
+		}
+		if height > best.Height {
+			return nil
+		}
+
+		hash, err := f.client.BlockHash(ctx, int(height))
+		if err != nil {
+			return err
+		}
+		hdr, err := f.client.BlockHeader(ctx, hash)
+		if err != nil {
+			return err
+		}
+		// The chain reorganised since the tip was checked, so check it again.
+		if f.tip != nil && prevHash(hdr) != f.tip.Hash {
+			if best, err = f.activeTip(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = f.emit(ctx, &Event{Type: BlockConnected, Hash: hash, Height: height, Header: hdr},
+			&Checkpoint{Height: height, Hash: hash}); err != nil {
+			return err
+		}
+	}
+}
+
+// activeTip returns the tip of the best chain.
+func (f *follower) activeTip(ctx context.Context) (*models.ChainTip, error) {
+	tips, err := f.client.ChainTips(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tip := range tips {
+		if tip.Status == "active" {
+			return tip, nil
+		}
+	}
+
+	return nil, ErrNoActiveTip
+}
+
+// onChain reports whether the block is part of the best chain.
+func (f *follower) onChain(ctx context.Context, cp *Checkpoint, best *models.ChainTip) (bool, error) {
+	if cp.Height > best.Height {
+		return false, nil
+	}
+
+	hash, err := f.client.BlockHash(ctx, int(cp.Height))
+	if errors.Is(err, models.ErrBlockNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return hash == cp.Hash, nil
+}
+
+// disconnect the tip, moving it back to its parent. Once the block at the start height is
+// disconnected there is no tip.
+func (f *follower) disconnect(ctx context.Context) error {
+	hdr, err := f.client.BlockHeader(ctx, f.tip.Hash)
+	if err != nil {
+		return err
+	}
+
+	var cp *Checkpoint
+	if f.tip.Height > f.cfg.startHeight {
+		cp = &Checkpoint{Height: f.tip.Height - 1, Hash: prevHash(hdr)}
+	}
+
+	return f.emit(ctx, &Event{Type: BlockDisconnected, Hash: f.tip.Hash, Height: f.tip.Height, Header: hdr}, cp)
+}
+
+// emit the event, then save and move to the checkpoint.
+func (f *follower) emit(ctx context.Context, e *Event, cp *Checkpoint) error {
+	// Errors handling the event or saving the checkpoint stop Run.
+	if err := f.fn(ctx, e); err != nil {
+		return poll.Fatal(err)
+	}
+	if err := f.cfg.checkpointer.Save(ctx, cp); err != nil {
+		return poll.Fatal(err)
+	}
+
+	f.setTip(cp)
+	return nil
+}
+
+func (f *follower) setTip(cp *Checkpoint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tip = cp
+}
+
+// prevHash returns the hash of the parent of the block.
+func prevHash(hdr *models.BlockHeader) string {
+	return hex.EncodeToString(hdr.HashPrevBlock)
+}
@@ -0,0 +1,204 @@
+package chain_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/chain"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// testChain a block tree with a best chain, served via a mock client.
+type testChain struct {
+	mu   sync.Mutex
+	prev map[string]string
+	best []string
+}
+
+func hash(name string) string {
+	return fmt.Sprintf("%064x", name)
+}
+
+// extend the best chain from height with the named blocks.
+func (c *testChain) extend(height int, names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.prev == nil {
+		c.prev = map[string]string{}
+	}
+	c.best = c.best[:height]
+	for _, name := range names {
+		h := hash(name)
+		c.prev[h] = ""
+		if len(c.best) > 0 {
+			c.prev[h] = c.best[len(c.best)-1]
+		}
+		c.best = append(c.best, h)
+	}
+}
+
+func (c *testChain) client() *mocks.BlockChainClientMock {
+	return &mocks.BlockChainClientMock{
+		ChainTipsFunc: func(context.Context) ([]*models.ChainTip, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return []*models.ChainTip{
+				{Height: 100, Hash: hash("stale"), Status: "valid-fork"},
+				{Height: uint32(len(c.best) - 1), Hash: c.best[len(c.best)-1], Status: "active"},
+			}, nil
+		},
+		BlockHashFunc: func(_ context.Context, height int) (string, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if height >= len(c.best) {
+				return "", &models.Error{Code: -8, Message: "Block height out of range"}
+			}
+			return c.best[height], nil
+		},
+		BlockHeaderFunc: func(_ context.Context, h string) (*models.BlockHeader, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			prev, err := hex.DecodeString(c.prev[h])
+			if err != nil {
+				return nil, err
+			}
+			return &models.BlockHeader{BlockHeader: &bc.BlockHeader{HashPrevBlock: prev}, Hash: h}, nil
+		},
+	}
+}
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) record(_ context.Context, e *chain.Event) error {
+	r.events = append(r.events, fmt.Sprintf("%s %d %s", e.Type, e.Height, e.Hash))
+	return nil
+}
+
+func (r *recorder) take() []string {
+	ee := r.events
+	r.events = nil
+	return ee
+}
+
+func ev(t chain.EventType, height int, name string) string {
+	return fmt.Sprintf("%s %d %s", t, height, hash(name))
+}
+
+func TestFollower_Sync(t *testing.T) {
+	t.Parallel()
+
+	c := &testChain{}
+	c.extend(0, "a0", "a1", "a2", "a3")
+
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	r := &recorder{}
+	f := chain.NewFollower(c.client(), r.record,
+		chain.WithStartHeight(1),
+		chain.WithCheckpointer(chain.NewFileCheckpointer(path)),
+	)
+
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Equal(t, []string{
+		ev(chain.BlockConnected, 1, "a1"),
+		ev(chain.BlockConnected, 2, "a2"),
+		ev(chain.BlockConnected, 3, "a3"),
+	}, r.take())
+	assert.Equal(t, &chain.Checkpoint{Height: 3, Hash: hash("a3")}, f.Tip())
+
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Empty(t, r.take())
+
+	// Reorg back to the fork point at a1.
+	c.extend(2, "b2", "b3", "b4")
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Equal(t, []string{
+		ev(chain.BlockDisconnected, 3, "a3"),
+		ev(chain.BlockDisconnected, 2, "a2"),
+		ev(chain.BlockConnected, 2, "b2"),
+		ev(chain.BlockConnected, 3, "b3"),
+		ev(chain.BlockConnected, 4, "b4"),
+	}, r.take())
+
+	// Resume from the checkpoint after a restart.
+	c.extend(5, "b5")
+	f = chain.NewFollower(c.client(), r.record,
+		chain.WithStartHeight(1),
+		chain.WithCheckpointer(chain.NewFileCheckpointer(path)),
+	)
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Equal(t, []string{ev(chain.BlockConnected, 5, "b5")}, r.take())
+
+	// Reorg to a shorter chain forking below the start height.
+	c.extend(1, "c1", "c2")
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Equal(t, []string{
+		ev(chain.BlockDisconnected, 5, "b5"),
+		ev(chain.BlockDisconnected, 4, "b4"),
+		ev(chain.BlockDisconnected, 3, "b3"),
+		ev(chain.BlockDisconnected, 2, "b2"),
+		ev(chain.BlockDisconnected, 1, "a1"),
+		ev(chain.BlockConnected, 1, "c1"),
+		ev(chain.BlockConnected, 2, "c2"),
+	}, r.take())
+}
+
+func TestFollower_Sync_HandlerError(t *testing.T) {
+	t.Parallel()
+
+	c := &testChain{}
+	c.extend(0, "a0", "a1")
+
+	errHandler := errors.New("handler failed")
+	fail := true
+	var events []string
+	f := chain.NewFollower(c.client(), func(_ context.Context, e *chain.Event) error {
+		if fail {
+			return errHandler
+		}
+		events = append(events, fmt.Sprintf("%s %d %s", e.Type, e.Height, e.Hash))
+		return nil
+	})
+
+	require.ErrorIs(t, f.Sync(context.TODO()), errHandler)
+	assert.Nil(t, f.Tip())
+
+	fail = false
+	require.NoError(t, f.Sync(context.TODO()))
+	assert.Equal(t, []string{
+		ev(chain.BlockConnected, 0, "a0"),
+		ev(chain.BlockConnected, 1, "a1"),
+	}, events)
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	t.Parallel()
+
+	cp := chain.NewFileCheckpointer(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	loaded, err := cp.Load(context.TODO())
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+
+	require.NoError(t, cp.Save(context.TODO(), &chain.Checkpoint{Height: 10, Hash: hash("a10")}))
+	loaded, err = cp.Load(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, &chain.Checkpoint{Height: 10, Hash: hash("a10")}, loaded)
+
+	require.NoError(t, cp.Save(context.TODO(), nil))
+	loaded, err = cp.Load(context.TODO())
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+}
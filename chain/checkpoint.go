@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+type memoryCheckpointer struct {
+	mu sync.Mutex
+	cp *Checkpoint
+}
+
+// NewMemoryCheckpointer returns a Checkpointer holding the checkpoint in memory, which is
+// lost on restart.
+func NewMemoryCheckpointer() Checkpointer {
+	return &memoryCheckpointer{}
+}
+
+// Load returns the checkpoint.
+func (m *memoryCheckpointer) Load(context.Context) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cp == nil {
+		return nil, nil
+	}
+	cp := *m.cp
+	return &cp, nil
+}
+
+// Save stores the checkpoint.
+func (m *memoryCheckpointer) Save(_ context.Context, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cp = nil
+	if cp != nil {
+		c := *cp
+		m.cp = &c
+	}
+	return nil
+}
+
+type fileCheckpointer struct {
+	path string
+}
+
+// NewFileCheckpointer returns a Checkpointer storing the checkpoint as JSON in the file at
+// path. The file is replaced atomically on each save, so a crash never leaves it truncated.
+func NewFileCheckpointer(path string) Checkpointer {
+	return &fileCheckpointer{path: path}
+}
+
+// Load reads the checkpoint from the file, returning nil if it does not exist.
+func (f *fileCheckpointer) Load(context.Context) (*Checkpoint, error) {
+	bb, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err = json.Unmarshal(bb, &cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint to the file, removing it when cp is nil.
+func (f *fileCheckpointer) Save(_ context.Context, cp *Checkpoint) error {
+	if cp == nil {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	bb, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	if err = os.WriteFile(tmp, bb, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
@@ -0,0 +1,8 @@
+package chain
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrNoActiveTip = errors.New("node reported no active chain tip")
+)
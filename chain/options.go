@@ -0,0 +1,54 @@
+package chain
+
+import (
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+)
+
+// DefaultPollInterval the default interval at which the follower polls the node for new
+// blocks, between `hashblock` notifications.
+const DefaultPollInterval = poll.DefaultInterval
+
+type followerCfg struct {
+	startHeight  uint32
+	checkpointer Checkpointer
+	pollInterval time.Duration
+	errorFn      ErrorFunc
+}
+
+// FollowerOptFunc option func.
+type FollowerOptFunc func(c *followerCfg)
+
+// WithStartHeight set the height of the first block to emit when there is no checkpoint.
+// Blocks below it are never emitted, even by a reorg reaching below it. Defaults to the
+// genesis block.
+func WithStartHeight(height uint32) FollowerOptFunc {
+	return func(c *followerCfg) {
+		c.startHeight = height
+	}
+}
+
+// WithCheckpointer set the Checkpointer used to resume from the last block processed.
+// Defaults to one held in memory.
+func WithCheckpointer(cp Checkpointer) FollowerOptFunc {
+	return func(c *followerCfg) {
+		c.checkpointer = cp
+	}
+}
+
+// WithPollInterval set the interval at which Run polls the node for new blocks when no
+// `hashblock` notification is received. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) FollowerOptFunc {
+	return func(c *followerCfg) {
+		c.pollInterval = d
+	}
+}
+
+// WithErrorHandler set a handler for errors talking to the node while running, which are
+// retried on the next poll. They are discarded by default.
+func WithErrorHandler(fn ErrorFunc) FollowerOptFunc {
+	return func(c *followerCfg) {
+		c.errorFn = fn
+	}
+}
@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// EventType the type of a chain event.
+type EventType int
+
+// Event types.
+const (
+	// BlockConnected a block was added to the tip of the best chain.
+	BlockConnected EventType = iota + 1
+	// BlockDisconnected a block was removed from the tip of the best chain by a reorg.
+	BlockDisconnected
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case BlockConnected:
+		return "BlockConnected"
+	case BlockDisconnected:
+		return "BlockDisconnected"
+	}
+	return "unknown"
+}
+
+// Event a change to the best chain. Events are emitted in order, so a reorg is seen as the
+// blocks of the old branch disconnected from the tip down to the fork point, followed by the
+// blocks of the new branch connected from the fork point up.
+type Event struct {
+	Type   EventType
+	Hash   string
+	Height uint32
+	Header *models.BlockHeader
+}
+
+// EventFunc a func in which chain events are passed to. Returning an error stops the follower
+// without checkpointing the event, so it is emitted again on restart.
+type EventFunc func(ctx context.Context, e *Event) error
+
+// ErrorFunc a func in which errors are passed to.
+type ErrorFunc func(ctx context.Context, err error)
+
+// Checkpoint the last block processed by the follower.
+type Checkpoint struct {
+	Height uint32 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// Checkpointer persists the checkpoint of the follower, so it can resume after a restart.
+// Load returns nil when there is no checkpoint, and Save is passed nil when every block
+// processed has been disconnected.
+type Checkpointer interface {
+	Load(ctx context.Context) (*Checkpoint, error)
+	Save(ctx context.Context, cp *Checkpoint) error
+}
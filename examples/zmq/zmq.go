@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/bsv-blockchain/go-bc"
 	"github.com/bsv-blockchain/go-bt/v2"
@@ -19,14 +22,14 @@ func main() {
 	z := zmq.NewNodeMQ(
 		zmq.WithContext(ctx),
 		zmq.WithHost("tcp://localhost:28332"),
-		zmq.WithRaw(),
+		zmq.WithReconnect(time.Second, time.Minute),
 		zmq.WithErrorHandler(func(_ context.Context, err error) {
 			log.Println("error found", err)
 		}),
 	)
 
-	if err := z.Subscribe(zmq.TopicInvalidTx, func(_ context.Context, bb [][]byte) {
-		log.Println("invalid tx", hex.EncodeToString(bb[1]))
+	if err := z.SubscribeInvalidTx(func(_ context.Context, tx *zmq.InvalidTx) {
+		log.Println("invalid tx", tx.TxID, tx.RejectionReason, "collided with", len(tx.CollidedWith))
 	}); err != nil {
 		panic(err)
 	}
@@ -59,5 +62,20 @@ func main() {
 		panic(err)
 	}
 
-	log.Fatal(z.Connect())
+	// Drain in-flight handlers on SIGTERM before exiting.
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := z.Close(ctx); err != nil {
+			log.Println("error closing", err)
+		}
+	}()
+
+	if err := z.Connect(); err != nil {
+		log.Fatal(err)
+	}
 }
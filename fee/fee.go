@@ -0,0 +1,146 @@
+// Package fee estimates fee rates from the relay policy of a bitcoin node and the fees paid
+// by the blocks it has recently connected.
+package fee
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+const (
+	bytesPerKB     = 1000
+	satoshisPerBSV = 1e8
+)
+
+// statsFields the block statistics the rates are worked out from.
+var statsFields = []string{"height", "totalfee", "total_size"}
+
+// Estimator recommends fee rates.
+type Estimator interface {
+	Estimate(ctx context.Context) (*Estimate, error)
+}
+
+type estimator struct {
+	client Client
+	cfg    *estimatorCfg
+}
+
+// NewEstimator returns an estimator drawing on the node behind client, configured via the
+// provided opt funcs.
+func NewEstimator(client Client, oo ...EstimatorOptFunc) Estimator {
+	cfg := &estimatorCfg{
+		blocks:     DefaultBlocks,
+		percentile: DefaultPercentile,
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+	cfg.percentile = min(max(cfg.percentile, 0), 100)
+
+	return &estimator{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Estimate returns the configured percentile of the average fee rates paid by the recent
+// blocks, raised to the minimum relay fee of the node. Blocks holding only a coinbase are
+// skipped, and if none pay fees the relay fee is recommended.
+func (e *estimator) Estimate(ctx context.Context) (*Estimate, error) {
+	info, err := e.client.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	relay := uint64(math.Round(info.RelayFee * satoshisPerBSV))
+	height := uint32(info.Blocks) //nolint:gosec // G115: block heights fit in 32 bits
+
+	stats, err := e.blockStats(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]uint64, 0, len(stats))
+	for _, s := range stats {
+		if s.TotalSize == 0 {
+			continue
+		}
+		rates = append(rates, rate(s))
+	}
+
+	est := &Estimate{
+		RelayFee: relay,
+		Height:   height,
+		Blocks:   len(rates),
+	}
+	est.Standard = max(relay, percentile(rates, e.cfg.percentile))
+	est.Data = est.Standard
+
+	return est, nil
+}
+
+// blockStats fetches the statistics of the blocks in the window up to the tip, skipping the
+// genesis block. They are batched when the client supports it.
+func (e *estimator) blockStats(ctx context.Context, tip uint32) ([]*models.BlockStats, error) {
+	from := uint32(1)
+	if n := uint32(max(e.cfg.blocks, 0)); tip >= n { //nolint:gosec // G115: clamped non-negative
+		from = max(tip-n+1, 1)
+	}
+	if tip < from {
+		return nil, nil
+	}
+
+	stats := make([]*models.BlockStats, 0, tip-from+1)
+	if c, ok := e.client.(bn.BatchClient); ok {
+		batch := c.Batch()
+		rr := make([]*bn.BatchResult[*models.BlockStats], 0, tip-from+1)
+		for h := from; h <= tip; h++ {
+			rr = append(rr, batch.BlockStatsByHeight(int(h), statsFields...))
+		}
+		if err := batch.Send(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rr {
+			s, err := r.Result()
+			if err != nil {
+				return nil, err
+			}
+			stats = append(stats, s)
+		}
+		return stats, nil
+	}
+
+	for h := from; h <= tip; h++ {
+		s, err := e.client.BlockStatsByHeight(ctx, int(h), statsFields...)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// rate returns the average fee rate paid by the transactions of the block in satoshis per
+// kilobyte, rounded up. It is worked out from the totals, the total fee being in satoshis, as
+// the rates the node reports are truncated to whole satoshis per byte.
+func rate(s *models.BlockStats) uint64 {
+	fee := uint64(math.Round(s.TotalFee))
+	return (fee*bytesPerKB + s.TotalSize - 1) / s.TotalSize
+}
+
+// percentile returns the nearest rank percentile of the rates, 0 if there are none.
+func percentile(rates []uint64, p float64) uint64 {
+	if len(rates) == 0 {
+		return 0
+	}
+
+	rates = slices.Clone(rates)
+	slices.Sort(rates)
+	i := int(math.Ceil(p / 100 * float64(len(rates))))
+	return rates[max(i-1, 0)]
+}
@@ -0,0 +1,131 @@
+package fee_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/fee"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+type client struct {
+	*mocks.ControlClientMock
+	*mocks.BlockChainClientMock
+}
+
+// node returns a client at the tip of the blocks, each given as its total fee in satoshis
+// and total size in bytes, from height 1.
+func node(relayFee float64, blocks [][2]uint64) *client {
+	return &client{
+		ControlClientMock: &mocks.ControlClientMock{
+			InfoFunc: func(context.Context) (*models.Info, error) {
+				return &models.Info{RelayFee: relayFee, Blocks: uint64(len(blocks))}, nil
+			},
+		},
+		BlockChainClientMock: &mocks.BlockChainClientMock{
+			BlockStatsByHeightFunc: func(_ context.Context, height int, _ ...string) (*models.BlockStats, error) {
+				if height < 1 || height > len(blocks) {
+					return nil, errors.New("block height out of range")
+				}
+				// The node reports the total fee as a whole number of satoshis.
+				b := blocks[height-1]
+				var s models.BlockStats
+				err := json.Unmarshal(fmt.Appendf(nil, `{"height":%d,"totalfee":%d,"total_size":%d}`,
+					height, b[0], b[1]), &s)
+				return &s, err
+			},
+		},
+	}
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	t.Parallel()
+
+	blocks := [][2]uint64{
+		{900000, 1000000},
+		{0, 0},
+		{100, 1000},
+		{250, 1000},
+		{0, 0},
+		{501, 1000},
+		{1000, 1000},
+		{101, 2000},
+	}
+
+	tests := map[string]struct {
+		relayFee float64
+		blocks   [][2]uint64
+		oo       []fee.EstimatorOptFunc
+		exp      fee.Estimate
+	}{
+		"median of the default window": {
+			relayFee: 0.00000050,
+			blocks:   blocks,
+			exp:      fee.Estimate{Standard: 250, Data: 250, RelayFee: 50, Height: 8, Blocks: 5},
+		},
+		"high percentile": {
+			relayFee: 0.00000050,
+			blocks:   blocks,
+			oo:       []fee.EstimatorOptFunc{fee.WithPercentile(90)},
+			exp:      fee.Estimate{Standard: 1000, Data: 1000, RelayFee: 50, Height: 8, Blocks: 5},
+		},
+		"lowest rate rounded up": {
+			relayFee: 0.00000050,
+			blocks:   blocks,
+			oo:       []fee.EstimatorOptFunc{fee.WithPercentile(0), fee.WithBlocks(3)},
+			exp:      fee.Estimate{Standard: 51, Data: 51, RelayFee: 50, Height: 8, Blocks: 3},
+		},
+		"window longer than the chain": {
+			relayFee: 0.00000050,
+			blocks:   blocks,
+			oo:       []fee.EstimatorOptFunc{fee.WithBlocks(100)},
+			exp:      fee.Estimate{Standard: 250, Data: 250, RelayFee: 50, Height: 8, Blocks: 6},
+		},
+		"raised to the relay fee": {
+			relayFee: 0.00000500,
+			blocks:   blocks,
+			exp:      fee.Estimate{Standard: 500, Data: 500, RelayFee: 500, Height: 8, Blocks: 5},
+		},
+		"no fees paid falls back to the relay fee": {
+			relayFee: 0.00000001,
+			blocks:   [][2]uint64{{0, 0}, {0, 0}},
+			exp:      fee.Estimate{Standard: 1, Data: 1, RelayFee: 1, Height: 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			est, err := fee.NewEstimator(node(test.relayFee, test.blocks), test.oo...).Estimate(context.TODO())
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, *est)
+		})
+	}
+}
+
+func TestEstimate_FeeQuote(t *testing.T) {
+	t.Parallel()
+
+	est := &fee.Estimate{Standard: 250, Data: 100, RelayFee: 50}
+	fq := est.FeeQuote()
+
+	std, err := fq.Fee(bt.FeeTypeStandard)
+	require.NoError(t, err)
+	assert.Equal(t, bt.FeeUnit{Satoshis: 250, Bytes: 1000}, std.MiningFee)
+	assert.Equal(t, bt.FeeUnit{Satoshis: 50, Bytes: 1000}, std.RelayFee)
+
+	data, err := fq.Fee(bt.FeeTypeData)
+	require.NoError(t, err)
+	assert.Equal(t, bt.FeeUnit{Satoshis: 100, Bytes: 1000}, data.MiningFee)
+
+	assert.InDelta(t, 0.0000025, est.FeeRate(), 1e-12)
+}
@@ -0,0 +1,32 @@
+package fee
+
+// DefaultBlocks the default number of recent blocks fee rates are sampled from.
+const DefaultBlocks = 6
+
+// DefaultPercentile the default percentile of the sampled fee rates recommended.
+const DefaultPercentile = 50
+
+type estimatorCfg struct {
+	blocks     int
+	percentile float64
+}
+
+// EstimatorOptFunc option func.
+type EstimatorOptFunc func(c *estimatorCfg)
+
+// WithBlocks set the number of blocks up to the tip fee rates are sampled from. Defaults to
+// DefaultBlocks.
+func WithBlocks(n int) EstimatorOptFunc {
+	return func(c *estimatorCfg) {
+		c.blocks = n
+	}
+}
+
+// WithPercentile set the percentile, from 0 to 100, of the fee rates paid by the sampled
+// blocks which is recommended. Higher percentiles outbid more of the recent transactions.
+// Defaults to DefaultPercentile.
+func WithPercentile(p float64) EstimatorOptFunc {
+	return func(c *estimatorCfg) {
+		c.percentile = p
+	}
+}
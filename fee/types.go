@@ -0,0 +1,51 @@
+package fee
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn"
+)
+
+// Client the node commands the estimator uses to read its relay policy and recent blocks.
+type Client interface {
+	bn.ControlClient
+	bn.BlockChainClient
+}
+
+// Estimate recommended fee rates in satoshis per kilobyte.
+type Estimate struct {
+	// Standard the rate recommended for standard transaction bytes.
+	Standard uint64
+	// Data the rate recommended for data carrier bytes. The node charges every byte at the
+	// same rate, so this matches Standard.
+	Data uint64
+	// RelayFee the minimum relay fee of the node, the floor of both rates.
+	RelayFee uint64
+	// Height the height of the tip of the node when the estimate was made.
+	Height uint32
+	// Blocks the number of blocks paying fees the estimate was drawn from. It is 0 when the
+	// rates fall back to the relay fee.
+	Blocks int
+}
+
+// FeeQuote returns the estimate as a fee quote, to fund transactions with go-bt.
+func (e *Estimate) FeeQuote() *bt.FeeQuote {
+	relay := bt.FeeUnit{Satoshis: int(e.RelayFee), Bytes: bytesPerKB} //nolint:gosec // G115: fee rates are far below max int
+	return bt.NewFeeQuote().
+		AddQuote(bt.FeeTypeStandard, &bt.Fee{
+			FeeType:   bt.FeeTypeStandard,
+			MiningFee: bt.FeeUnit{Satoshis: int(e.Standard), Bytes: bytesPerKB}, //nolint:gosec // G115: as above
+			RelayFee:  relay,
+		}).
+		AddQuote(bt.FeeTypeData, &bt.Fee{
+			FeeType:   bt.FeeTypeData,
+			MiningFee: bt.FeeUnit{Satoshis: int(e.Data), Bytes: bytesPerKB}, //nolint:gosec // G115: as above
+			RelayFee:  relay,
+		})
+}
+
+// FeeRate returns the standard rate in BSV per kilobyte, the unit of the `feeRate` option
+// of `fundrawtransaction`.
+func (e *Estimate) FeeRate() float64 {
+	return float64(e.Standard) / satoshisPerBSV
+}
@@ -0,0 +1,17 @@
+package headers
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrHeaderNotFound      = errors.New("header not found")
+	ErrUnknownChain        = errors.New("no params for chain")
+	ErrCheckpointMismatch  = errors.New("header does not match checkpoint")
+	ErrPrevMismatch        = errors.New("header does not follow its parent")
+	ErrProofOfWork         = errors.New("header hash does not meet its target")
+	ErrBadBits             = errors.New("header bits do not match the difficulty rules")
+	ErrForkBelowCheckpoint = errors.New("best chain of node forks below the checkpoint")
+	ErrInsufficientWork    = errors.New("best chain of node has less work than the stored chain")
+	ErrCorrupt             = errors.New("header file is corrupt")
+	ErrMalformedHeader     = errors.New("header is not 80 bytes")
+)
@@ -0,0 +1,98 @@
+package headers
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// fileHeaderSize the size of the height of the checkpoint, which the header file starts with.
+const fileHeaderSize = 4
+
+// readFile reads the headers from the file, validating them from the checkpoint. It returns
+// nil when there is no file. A header left partially written by a crash is ignored.
+func (s *store) readFile(cp *Checkpoint, p *Params) ([]entry, error) {
+	if s.cfg.path == "" {
+		return nil, nil
+	}
+
+	bb, err := os.ReadFile(s.cfg.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bb) < fileHeaderSize+headerSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrCorrupt, len(bb))
+	}
+	if base := binary.LittleEndian.Uint32(bb); base != cp.Height {
+		return nil, fmt.Errorf("%w: file starts at height %d, not %d", ErrCheckpointMismatch, base, cp.Height)
+	}
+
+	bb = bb[fileHeaderSize:]
+	v := &view{base: cp.Height, branch: make([]entry, 0, len(bb)/headerSize)}
+	e, err := anchor([headerSize]byte(bb), cp, p)
+	if err != nil {
+		return nil, err
+	}
+	v.branch = append(v.branch, e)
+
+	for i := headerSize; i+headerSize <= len(bb); i += headerSize {
+		e, err := v.connect([headerSize]byte(bb[i:]), p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCorrupt, err)
+		}
+		v.branch = append(v.branch, e)
+	}
+
+	return v.branch, nil
+}
+
+// createFile writes a new file holding the checkpoint header, replacing it atomically.
+func (s *store) createFile(cp *Checkpoint, e *entry) error {
+	if s.cfg.path == "" {
+		return nil
+	}
+
+	bb := make([]byte, fileHeaderSize, fileHeaderSize+headerSize)
+	binary.LittleEndian.PutUint32(bb, cp.Height)
+	bb = append(bb, e.raw[:]...)
+
+	tmp := s.cfg.path + ".tmp"
+	if err := os.WriteFile(tmp, bb, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.cfg.path)
+}
+
+// writeFile replaces the headers in the file above the fork height with those of the branch.
+func (s *store) writeFile(fork uint32, branch []entry) error {
+	if s.cfg.path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(s.cfg.path, os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	off := int64(fileHeaderSize) + int64(fork+1-s.base)*headerSize
+	if err = f.Truncate(off); err != nil {
+		return err
+	}
+
+	bb := make([]byte, 0, len(branch)*headerSize)
+	for i := range branch {
+		bb = append(bb, branch[i].raw[:]...)
+	}
+	if _, err = f.WriteAt(bb, off); err != nil {
+		return err
+	}
+	return f.Sync()
+}
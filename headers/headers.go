@@ -0,0 +1,419 @@
+// Package headers keeps a header-only copy of the best chain of a bitcoin node, validating the
+// proof of work, difficulty and linkage of every header itself, so that merkle proofs can be
+// checked without trusting the node.
+package headers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// fetchBatchSize the number of headers fetched from the node at once.
+const fetchBatchSize = 1000
+
+// Store holds the validated headers of the best chain known to a node. It implements
+// bc.BlockHeaderChain, so can back the verifiers of the spv package.
+type Store interface {
+	Sync(ctx context.Context) error
+	Run(ctx context.Context) error
+	Subscribe(z zmq.NodeMQ) error
+	OnHashBlock(ctx context.Context, hash string)
+	Tip() *Header
+	HeaderByHeight(height uint32) (*Header, error)
+	HeaderByHash(hash string) (*Header, error)
+	BlockHeader(ctx context.Context, hash string) (*bc.BlockHeader, error)
+	IsValidRootForHeight(ctx context.Context, root string, height uint32) (bool, error)
+}
+
+type store struct {
+	client bn.BlockChainClient
+	cfg    *storeCfg
+	poller *poll.Poller
+
+	syncMu sync.Mutex
+	loaded bool
+	params *Params
+
+	mu    sync.RWMutex
+	base  uint32
+	chain []entry
+	index map[chainhash.Hash]uint32
+}
+
+// NewStore returns a store of the headers of the best chain of the node behind client. It is
+// configured via the provided opt funcs.
+func NewStore(client bn.BlockChainClient, oo ...StoreOptFunc) Store {
+	cfg := &storeCfg{
+		pollInterval: DefaultPollInterval,
+		errorFn:      func(context.Context, error) {},
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+
+	return &store{
+		client: client,
+		cfg:    cfg,
+		poller: poll.New(),
+		index:  make(map[chainhash.Hash]uint32),
+	}
+}
+
+// Sync brings the store up to the best chain of the node, loading or bootstrapping it from
+// the checkpoint on first use. A branch of the node replaces stored headers only once it has
+// more work, and every header it adds is validated, so a node serving an invalid chain cannot
+// change the store.
+func (s *store) Sync(ctx context.Context) error {
+	return poll.Cause(s.sync(ctx))
+}
+
+// Run syncs the store each time a block is announced via OnHashBlock, or else every poll
+// interval, until ctx is done. Errors syncing with the node, including invalid headers, are
+// passed to the error handler and retried, while errors writing the header file stop it.
+func (s *store) Run(ctx context.Context) error {
+	return s.poller.Run(ctx, s.cfg.pollInterval, s.sync, s.cfg.errorFn)
+}
+
+// Subscribe has OnHashBlock called for each `hashblock` notification, keeping the tip of the
+// store current without waiting on the poll interval. The topic is shared via zmq.Share, so
+// may also be subscribed to by others, such as a chain.Follower.
+func (s *store) Subscribe(z zmq.NodeMQ) error {
+	return zmq.Share(z).SubscribeHashBlock(s.OnHashBlock)
+}
+
+// OnHashBlock wakes Run to fetch and validate the header of the announced block. The hash
+// announced is not trusted, the header being taken from the best chain of the node.
+func (s *store) OnHashBlock(context.Context, string) {
+	s.poller.Wake()
+}
+
+// Tip returns the header at the tip of the best chain, nil before the first sync.
+func (s *store) Tip() *Header {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.chain) == 0 {
+		return nil
+	}
+	return s.header(s.tip())
+}
+
+// HeaderByHeight returns the header at the height in the best chain.
+func (s *store) HeaderByHeight(height uint32) (*Header, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.chain) == 0 || height < s.base || height > s.tip() {
+		return nil, fmt.Errorf("%w: height %d", ErrHeaderNotFound, height)
+	}
+	return s.header(height), nil
+}
+
+// HeaderByHash returns the header with the hash, if it is in the best chain.
+func (s *store) HeaderByHash(hash string) (*Header, error) {
+	h, err := chainhash.NewHashFromStr(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	height, ok := s.index[*h]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrHeaderNotFound, hash)
+	}
+	return s.header(height), nil
+}
+
+// BlockHeader returns the header with the hash, if it is in the best chain.
+func (s *store) BlockHeader(_ context.Context, hash string) (*bc.BlockHeader, error) {
+	hdr, err := s.HeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return hdr.BlockHeader, nil
+}
+
+// IsValidRootForHeight reports whether the merkle root is that of the block at the height in
+// the best chain. Heights outside of the store are never valid.
+func (s *store) IsValidRootForHeight(_ context.Context, root string, height uint32) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.chain) == 0 || height < s.base || height > s.tip() {
+		return false, nil
+	}
+	e := s.chain[height-s.base]
+	return e.merkleRoot().String() == root, nil
+}
+
+func (s *store) sync(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	if !s.loaded {
+		if err := s.load(ctx); err != nil {
+			return err
+		}
+		s.loaded = true
+	}
+
+	best, err := s.client.BlockCount(ctx)
+	if err != nil {
+		return err
+	}
+	if best < s.base {
+		return nil
+	}
+
+	fork, err := s.forkPoint(ctx, best)
+	if err != nil {
+		return err
+	}
+
+	v := &view{base: s.base, chain: s.chain[:fork-s.base+1]}
+	work := s.chain[len(s.chain)-1].chainWork()
+	for from := fork + 1; from <= best; {
+		to := min(best, from+fetchBatchSize-1)
+		raws, err := s.fetch(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		for _, raw := range raws {
+			e, err := v.connect(raw, s.params)
+			if err != nil {
+				return err
+			}
+			v.branch = append(v.branch, e)
+		}
+		from = to + 1
+
+		// Extending the tip always adds work, so headers are kept as they are fetched, while
+		// a competing branch waits until it overtakes the stored one.
+		if v.branch[len(v.branch)-1].chainWork().Cmp(work) > 0 {
+			if err = s.adopt(v); err != nil {
+				return err
+			}
+			v = &view{base: s.base, chain: s.chain}
+			work = s.chain[len(s.chain)-1].chainWork()
+		}
+	}
+	if len(v.branch) > 0 {
+		return fmt.Errorf("%w: branch from height %d to %d", ErrInsufficientWork, fork+1, best)
+	}
+
+	return nil
+}
+
+// load the headers from file, or else bootstrap them from the checkpoint.
+func (s *store) load(ctx context.Context) error {
+	p := s.cfg.params
+	if p == nil {
+		info, err := s.client.ChainInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if p, err = ParamsForChain(info.Chain); err != nil {
+			return err
+		}
+	}
+
+	cp := s.cfg.checkpoint
+	if cp == nil {
+		cp = &Checkpoint{Hash: p.GenesisHash}
+	}
+
+	chain, err := s.readFile(cp, p)
+	if err != nil {
+		return err
+	}
+	if chain == nil {
+		hash, err := s.client.BlockHash(ctx, int(cp.Height))
+		if err != nil {
+			return err
+		}
+		if hash != cp.Hash {
+			return fmt.Errorf("%w: node has block %s at height %d, expected %s", ErrCheckpointMismatch,
+				hash, cp.Height, cp.Hash)
+		}
+
+		h, err := s.client.BlockHeaderHex(ctx, cp.Hash)
+		if err != nil {
+			return err
+		}
+		raw, err := decodeHeader(h)
+		if err != nil {
+			return err
+		}
+		e, err := anchor(raw, cp, p)
+		if err != nil {
+			return err
+		}
+		if err = s.createFile(cp, &e); err != nil {
+			return err
+		}
+		chain = []entry{e}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.params = p
+	s.base = cp.Height
+	s.chain = chain
+	for i := range chain {
+		s.index[chain[i].hash] = cp.Height + uint32(i)
+	}
+	return nil
+}
+
+// forkPoint returns the height of the last stored header also in the best chain of the node.
+func (s *store) forkPoint(ctx context.Context, best uint32) (uint32, error) {
+	for h := min(best, s.tip()); ; h-- {
+		hash, err := s.client.BlockHash(ctx, int(h))
+		if err != nil {
+			return 0, err
+		}
+		if hash == s.chain[h-s.base].hash.String() {
+			return h, nil
+		}
+		if h == s.base {
+			return 0, ErrForkBelowCheckpoint
+		}
+	}
+}
+
+// adopt the branch of the view as the best chain.
+func (s *store) adopt(v *view) error {
+	fork := v.base + uint32(len(v.chain)) - 1
+	if err := s.writeFile(fork, v.branch); err != nil {
+		return poll.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.chain[len(v.chain):] {
+		delete(s.index, e.hash)
+	}
+	s.chain = append(s.chain[:len(v.chain)], v.branch...)
+	for i, e := range v.branch {
+		s.index[e.hash] = fork + 1 + uint32(i)
+	}
+	return nil
+}
+
+// fetch the headers of the best chain of the node between the heights, inclusive. They are
+// batched when the client supports it.
+func (s *store) fetch(ctx context.Context, from, to uint32) ([][headerSize]byte, error) {
+	raws := make([][headerSize]byte, 0, to-from+1)
+
+	if c, ok := s.client.(bn.BatchClient); ok {
+		b := c.Batch()
+		hashes := make([]*bn.BatchResult[string], 0, cap(raws))
+		for h := from; h <= to; h++ {
+			hashes = append(hashes, b.BlockHash(int(h)))
+		}
+		if err := b.Send(ctx); err != nil {
+			return nil, err
+		}
+
+		b = c.Batch()
+		hexes := make([]*bn.BatchResult[string], 0, cap(raws))
+		for _, r := range hashes {
+			hash, err := r.Result()
+			if err != nil {
+				return nil, err
+			}
+			hexes = append(hexes, b.BlockHeaderHex(hash))
+		}
+		if err := b.Send(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range hexes {
+			h, err := r.Result()
+			if err != nil {
+				return nil, err
+			}
+			raw, err := decodeHeader(h)
+			if err != nil {
+				return nil, err
+			}
+			raws = append(raws, raw)
+		}
+		return raws, nil
+	}
+
+	for h := from; h <= to; h++ {
+		hash, err := s.client.BlockHash(ctx, int(h))
+		if err != nil {
+			return nil, err
+		}
+		hdr, err := s.client.BlockHeaderHex(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := decodeHeader(hdr)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
+
+// tip returns the height of the last stored header.
+func (s *store) tip() uint32 {
+	return s.base + uint32(len(s.chain)) - 1
+}
+
+func (s *store) header(height uint32) *Header {
+	e := &s.chain[height-s.base]
+	bh, _ := bc.NewBlockHeaderFromBytes(e.raw[:])
+	return &Header{
+		BlockHeader: bh,
+		Hash:        e.hash.String(),
+		Height:      height,
+	}
+}
+
+// anchor returns the entry of the checkpoint, which is trusted once it has the expected hash
+// and meets its target.
+func anchor(raw [headerSize]byte, cp *Checkpoint, p *Params) (entry, error) {
+	e := newEntry(raw)
+	if e.hash.String() != cp.Hash {
+		return e, fmt.Errorf("%w: block %s at height %d, expected %s", ErrCheckpointMismatch,
+			e.hash, cp.Height, cp.Hash)
+	}
+	if err := checkProofOfWork(&e, p); err != nil {
+		return e, err
+	}
+
+	blockProof(e.bits()).FillBytes(e.work[:])
+	return e, nil
+}
+
+func decodeHeader(s string) ([headerSize]byte, error) {
+	var raw [headerSize]byte
+	bb, err := hex.DecodeString(s)
+	if err != nil {
+		return raw, err
+	}
+	if len(bb) != headerSize {
+		return raw, fmt.Errorf("%w: %d bytes", ErrMalformedHeader, len(bb))
+	}
+
+	copy(raw[:], bb)
+	return raw, nil
+}
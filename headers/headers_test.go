@@ -0,0 +1,329 @@
+package headers_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/headers"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+const easyBits = 0x207fffff
+
+// node a fake node serving a chain of headers mined at the easiest difficulty.
+type node struct {
+	mu     sync.Mutex
+	chain  [][]byte
+	params *headers.Params
+}
+
+func newNode(t *testing.T, n int) *node {
+	t.Helper()
+
+	nd := &node{}
+	nd.chain = [][]byte{mine(t, make([]byte, 32), 0, 1296688602, easyBits)}
+	p := *headers.RegTest
+	p.GenesisHash = hash(nd.chain[0])
+	nd.params = &p
+	nd.extend(t, n, 0)
+
+	return nd
+}
+
+// mine returns a header meeting the target of bits.
+func mine(t *testing.T, prev []byte, seed, ts, bits uint32) []byte {
+	t.Helper()
+
+	raw := make([]byte, 80)
+	binary.LittleEndian.PutUint32(raw, 1)
+	copy(raw[4:], prev)
+	root := chainhash.DoubleHashH(binary.LittleEndian.AppendUint32(nil, seed))
+	copy(raw[36:], root[:])
+	binary.LittleEndian.PutUint32(raw[68:], ts)
+	binary.LittleEndian.PutUint32(raw[72:], bits)
+
+	size := bits >> 24
+	target := new(big.Int).Lsh(big.NewInt(int64(bits&0x007fffff)), uint(8*(size-3)))
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(raw[76:], nonce)
+		h := chainhash.DoubleHashH(raw)
+		slices.Reverse(h[:])
+		if new(big.Int).SetBytes(h[:]).Cmp(target) <= 0 {
+			return raw
+		}
+	}
+}
+
+func hash(raw []byte) string {
+	return chainhash.DoubleHashH(raw).String()
+}
+
+func root(raw []byte) string {
+	return chainhash.Hash(raw[36:68]).String()
+}
+
+// extend the chain by n blocks, mined with the seed so forks differ.
+func (nd *node) extend(t *testing.T, n int, seed uint32) {
+	t.Helper()
+
+	nd.mu.Lock()
+	defer nd.mu.Unlock()
+
+	for range n {
+		prev := nd.chain[len(nd.chain)-1]
+		h := chainhash.DoubleHashH(prev)
+		ts := binary.LittleEndian.Uint32(prev[68:]) + 600
+		nd.chain = append(nd.chain, mine(t, h[:], seed<<16|uint32(len(nd.chain)), ts, easyBits))
+	}
+}
+
+// reorg replaces the chain above the height with n new blocks.
+func (nd *node) reorg(t *testing.T, height, n int) {
+	t.Helper()
+
+	nd.mu.Lock()
+	nd.chain = nd.chain[:height+1]
+	nd.mu.Unlock()
+
+	nd.extend(t, n, 1)
+}
+
+func (nd *node) set(height int, raw []byte) {
+	nd.mu.Lock()
+	defer nd.mu.Unlock()
+
+	nd.chain = append(nd.chain[:height], raw)
+}
+
+func (nd *node) client() *mocks.BlockChainClientMock {
+	return &mocks.BlockChainClientMock{
+		ChainInfoFunc: func(context.Context) (*models.ChainInfo, error) {
+			return &models.ChainInfo{Chain: "regtest"}, nil
+		},
+		BlockCountFunc: func(context.Context) (uint32, error) {
+			nd.mu.Lock()
+			defer nd.mu.Unlock()
+			return uint32(len(nd.chain) - 1), nil
+		},
+		BlockHashFunc: func(_ context.Context, height int) (string, error) {
+			nd.mu.Lock()
+			defer nd.mu.Unlock()
+			if height >= len(nd.chain) {
+				return "", models.ErrBlockNotFound
+			}
+			return hash(nd.chain[height]), nil
+		},
+		BlockHeaderHexFunc: func(_ context.Context, h string) (string, error) {
+			nd.mu.Lock()
+			defer nd.mu.Unlock()
+			for _, raw := range nd.chain {
+				if hash(raw) == h {
+					return hex.EncodeToString(raw), nil
+				}
+			}
+			return "", models.ErrBlockNotFound
+		},
+	}
+}
+
+func TestStore_Sync(t *testing.T) {
+	t.Parallel()
+
+	nd := newNode(t, 20)
+	path := filepath.Join(t.TempDir(), "headers")
+	s := headers.NewStore(nd.client(), headers.WithParams(nd.params), headers.WithFile(path))
+
+	require.Nil(t, s.Tip())
+	require.NoError(t, s.Sync(context.TODO()))
+
+	tip := s.Tip()
+	require.NotNil(t, tip)
+	assert.Equal(t, uint32(20), tip.Height)
+	assert.Equal(t, hash(nd.chain[20]), tip.Hash)
+
+	hdr, err := s.HeaderByHeight(7)
+	require.NoError(t, err)
+	assert.Equal(t, hash(nd.chain[7]), hdr.Hash)
+	assert.Equal(t, hex.EncodeToString(nd.chain[7]), hdr.String())
+
+	hdr, err = s.HeaderByHash(hash(nd.chain[9]))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(9), hdr.Height)
+
+	bh, err := s.BlockHeader(context.TODO(), hash(nd.chain[9]))
+	require.NoError(t, err)
+	assert.Equal(t, root(nd.chain[9]), bh.HashMerkleRootStr())
+
+	_, err = s.HeaderByHeight(21)
+	require.ErrorIs(t, err, headers.ErrHeaderNotFound)
+
+	ok, err := s.IsValidRootForHeight(context.TODO(), root(nd.chain[12]), 12)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = s.IsValidRootForHeight(context.TODO(), root(nd.chain[12]), 13)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// New blocks are added on the next sync.
+	nd.extend(t, 5, 0)
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, uint32(25), s.Tip().Height)
+
+	// Headers are loaded from file on restart, checking only the node still has them.
+	client := nd.client()
+	s = headers.NewStore(client, headers.WithParams(nd.params), headers.WithFile(path))
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, hash(nd.chain[25]), s.Tip().Hash)
+	assert.Empty(t, client.BlockHeaderHexCalls())
+	assert.Empty(t, client.ChainInfoCalls())
+}
+
+func TestStore_Sync_Reorg(t *testing.T) {
+	t.Parallel()
+
+	nd := newNode(t, 10)
+	path := filepath.Join(t.TempDir(), "headers")
+	s := headers.NewStore(nd.client(), headers.WithParams(nd.params), headers.WithFile(path))
+	require.NoError(t, s.Sync(context.TODO()))
+	old := slices.Clone(nd.chain)
+
+	// A shorter branch with less work is refused.
+	nd.reorg(t, 6, 2)
+	require.ErrorIs(t, s.Sync(context.TODO()), headers.ErrInsufficientWork)
+	assert.Equal(t, hash(old[10]), s.Tip().Hash)
+
+	// A longer branch replaces the stored one.
+	nd.extend(t, 3, 1)
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, uint32(11), s.Tip().Height)
+	assert.Equal(t, hash(nd.chain[11]), s.Tip().Hash)
+
+	_, err := s.HeaderByHash(hash(old[8]))
+	require.ErrorIs(t, err, headers.ErrHeaderNotFound)
+	ok, err := s.IsValidRootForHeight(context.TODO(), root(old[8]), 8)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = s.IsValidRootForHeight(context.TODO(), root(nd.chain[8]), 8)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// The file holds the new branch.
+	s = headers.NewStore(nd.client(), headers.WithParams(nd.params), headers.WithFile(path))
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, hash(nd.chain[11]), s.Tip().Hash)
+}
+
+func TestStore_Sync_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		header func(t *testing.T, prev []byte) []byte
+		err    error
+	}{
+		"wrong parent": {
+			header: func(t *testing.T, prev []byte) []byte {
+				return mine(t, make([]byte, 32), 99, binary.LittleEndian.Uint32(prev[68:])+600, easyBits)
+			},
+			err: headers.ErrPrevMismatch,
+		},
+		"wrong bits": {
+			header: func(t *testing.T, prev []byte) []byte {
+				h := chainhash.DoubleHashH(prev)
+				return mine(t, h[:], 99, binary.LittleEndian.Uint32(prev[68:])+600, 0x2000ffff)
+			},
+			err: headers.ErrBadBits,
+		},
+		"insufficient proof of work": {
+			header: func(t *testing.T, prev []byte) []byte {
+				h := chainhash.DoubleHashH(prev)
+				raw := mine(t, h[:], 99, binary.LittleEndian.Uint32(prev[68:])+600, easyBits)
+				binary.LittleEndian.PutUint32(raw[72:], 0x1d00ffff)
+				return raw
+			},
+			err: headers.ErrProofOfWork,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			nd := newNode(t, 5)
+			s := headers.NewStore(nd.client(), headers.WithParams(nd.params))
+			require.NoError(t, s.Sync(context.TODO()))
+
+			nd.set(6, test.header(t, nd.chain[5]))
+			require.ErrorIs(t, s.Sync(context.TODO()), test.err)
+			assert.Equal(t, uint32(5), s.Tip().Height)
+		})
+	}
+}
+
+func TestStore_Sync_Checkpoint(t *testing.T) {
+	t.Parallel()
+
+	nd := newNode(t, 200)
+
+	client := nd.client()
+	client.ChainInfoFunc = func(context.Context) (*models.ChainInfo, error) {
+		return &models.ChainInfo{Chain: "unknown"}, nil
+	}
+	s := headers.NewStore(client, headers.WithCheckpoint(30, hash(nd.chain[30])))
+	require.ErrorIs(t, s.Sync(context.TODO()), headers.ErrUnknownChain)
+
+	// The difficulty adjustment algorithm applies once a full window is stored.
+	p := *nd.params
+	p.NoRetargeting, p.AllowMinDifficulty = false, false
+	s = headers.NewStore(client, headers.WithParams(&p), headers.WithCheckpoint(30, hash(nd.chain[30])))
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, uint32(200), s.Tip().Height)
+
+	_, err := s.HeaderByHeight(29)
+	require.ErrorIs(t, err, headers.ErrHeaderNotFound)
+	hdr, err := s.HeaderByHeight(30)
+	require.NoError(t, err)
+	assert.Equal(t, hash(nd.chain[30]), hdr.Hash)
+
+	// A checkpoint the node disagrees with is refused.
+	s = headers.NewStore(client, headers.WithParams(&p), headers.WithCheckpoint(30, hash(nd.chain[31])))
+	require.ErrorIs(t, s.Sync(context.TODO()), headers.ErrCheckpointMismatch)
+}
+
+func TestStore_Sync_MainNet(t *testing.T) {
+	t.Parallel()
+
+	chain := []string{
+		"0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c",
+		"010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299",
+		"010000004860eb18bf1b1620e37e9490fc8a427514416fd75159ab86688e9a8300000000d5fdcc541e25de1c7a5addedf24858b8bb665c9f36ef744ee42c316022c90f9bb0bc6649ffff001d08d2bd61",
+	}
+	nd := &node{}
+	for _, h := range chain {
+		raw, err := hex.DecodeString(h)
+		require.NoError(t, err)
+		nd.chain = append(nd.chain, raw)
+	}
+	client := nd.client()
+	client.ChainInfoFunc = func(context.Context) (*models.ChainInfo, error) {
+		return &models.ChainInfo{Chain: "main"}, nil
+	}
+
+	s := headers.NewStore(client)
+	require.NoError(t, s.Sync(context.TODO()))
+	assert.Equal(t, "000000006a625f06636b8bb6ac7b960a8d03705d1ace08b1a19da3fdcc99ddbd", s.Tip().Hash)
+
+	ok, err := s.IsValidRootForHeight(context.TODO(),
+		"0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098", 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,62 @@
+package headers
+
+import (
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+)
+
+// DefaultPollInterval the default interval at which the store polls the node for new blocks,
+// between `hashblock` notifications.
+const DefaultPollInterval = poll.DefaultInterval
+
+type storeCfg struct {
+	params       *Params
+	checkpoint   *Checkpoint
+	path         string
+	pollInterval time.Duration
+	errorFn      ErrorFunc
+}
+
+// StoreOptFunc option func.
+type StoreOptFunc func(c *storeCfg)
+
+// WithParams set the params of the chain headers are validated against. Defaults to those of
+// the chain reported by the node.
+func WithParams(p *Params) StoreOptFunc {
+	return func(c *storeCfg) {
+		c.params = p
+	}
+}
+
+// WithCheckpoint set the block the store is bootstrapped from, trusting the chain up to it.
+// The node must have the block at the height. Defaults to the genesis block.
+func WithCheckpoint(height uint32, hash string) StoreOptFunc {
+	return func(c *storeCfg) {
+		c.checkpoint = &Checkpoint{Height: height, Hash: hash}
+	}
+}
+
+// WithFile set the path of the file headers are persisted to, so they are loaded on restart
+// rather than fetched from the node. Headers are held only in memory by default.
+func WithFile(path string) StoreOptFunc {
+	return func(c *storeCfg) {
+		c.path = path
+	}
+}
+
+// WithPollInterval set the interval at which Run polls the node for new blocks when no
+// `hashblock` notification is received. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) StoreOptFunc {
+	return func(c *storeCfg) {
+		c.pollInterval = d
+	}
+}
+
+// WithErrorHandler set a handler for errors syncing with the node while running, which are
+// retried on the next poll. They are discarded by default.
+func WithErrorHandler(fn ErrorFunc) StoreOptFunc {
+	return func(c *storeCfg) {
+		c.errorFn = fn
+	}
+}
@@ -0,0 +1,80 @@
+package headers
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Params the consensus rules of a chain which headers are validated against.
+type Params struct {
+	// Name the chain name reported by `getblockchaininfo`.
+	Name string
+	// GenesisHash the hash of the genesis block, the default checkpoint.
+	GenesisHash string
+	// PowLimit the highest target, so lowest difficulty, a header may have.
+	PowLimit *big.Int
+	// TargetSpacing the time targeted between blocks.
+	TargetSpacing time.Duration
+	// TargetTimespan the time targeted for each legacy difficulty adjustment interval.
+	TargetTimespan time.Duration
+	// DAAHeight the height from which the difficulty adjustment algorithm retargets on
+	// every block, after the block at this height.
+	DAAHeight uint32
+	// AllowMinDifficulty whether a block more than twice TargetSpacing after its parent may
+	// have the lowest difficulty.
+	AllowMinDifficulty bool
+	// NoRetargeting whether the difficulty never changes.
+	NoRetargeting bool
+}
+
+// Chain params.
+var (
+	MainNet = &Params{
+		Name:           "main",
+		GenesisHash:    "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+		PowLimit:       powLimit("00000000ffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		TargetSpacing:  10 * time.Minute,
+		TargetTimespan: 14 * 24 * time.Hour,
+		DAAHeight:      504031,
+	}
+	TestNet = &Params{
+		Name:               "test",
+		GenesisHash:        "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+		PowLimit:           powLimit("00000000ffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		TargetSpacing:      10 * time.Minute,
+		TargetTimespan:     14 * 24 * time.Hour,
+		DAAHeight:          1188697,
+		AllowMinDifficulty: true,
+	}
+	RegTest = &Params{
+		Name:               "regtest",
+		GenesisHash:        "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
+		PowLimit:           powLimit("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		TargetSpacing:      10 * time.Minute,
+		TargetTimespan:     14 * 24 * time.Hour,
+		AllowMinDifficulty: true,
+		NoRetargeting:      true,
+	}
+)
+
+// ParamsForChain returns the params of the chain with the name reported by the node.
+func ParamsForChain(name string) (*Params, error) {
+	for _, p := range []*Params{MainNet, TestNet, RegTest} {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownChain, name)
+}
+
+// interval the number of blocks in a legacy difficulty adjustment interval.
+func (p *Params) interval() uint32 {
+	return uint32(p.TargetTimespan / p.TargetSpacing)
+}
+
+func powLimit(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
@@ -0,0 +1,326 @@
+package headers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// headerSize the size of a serialised block header.
+const headerSize = 80
+
+// daaWindow the number of blocks the difficulty adjustment algorithm averages work over.
+const daaWindow = 144
+
+var oneLsh256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// entry a validated header, with the work of the chain up to and including it counted from
+// the checkpoint.
+type entry struct {
+	raw  [headerSize]byte
+	hash chainhash.Hash
+	work [32]byte
+}
+
+func newEntry(raw [headerSize]byte) entry {
+	return entry{raw: raw, hash: chainhash.DoubleHashH(raw[:])}
+}
+
+func (e *entry) prevHash() chainhash.Hash {
+	return chainhash.Hash(e.raw[4:36])
+}
+
+func (e *entry) merkleRoot() chainhash.Hash {
+	return chainhash.Hash(e.raw[36:68])
+}
+
+func (e *entry) time() uint32 {
+	return binary.LittleEndian.Uint32(e.raw[68:72])
+}
+
+func (e *entry) bits() uint32 {
+	return binary.LittleEndian.Uint32(e.raw[72:76])
+}
+
+func (e *entry) chainWork() *big.Int {
+	return new(big.Int).SetBytes(e.work[:])
+}
+
+// view the chain of stored entries up to the fork point, followed by a branch being
+// validated.
+type view struct {
+	base   uint32
+	chain  []entry
+	branch []entry
+}
+
+func (v *view) len() int {
+	return len(v.chain) + len(v.branch)
+}
+
+// tip returns the height of the last entry.
+func (v *view) tip() uint32 {
+	return v.base + uint32(v.len()) - 1
+}
+
+// at returns the entry at the height, nil if it is not in view.
+func (v *view) at(height uint32) *entry {
+	if height < v.base || height > v.tip() {
+		return nil
+	}
+
+	i := int(height - v.base)
+	if i < len(v.chain) {
+		return &v.chain[i]
+	}
+	return &v.branch[i-len(v.chain)]
+}
+
+// connect validates the header as the child of the tip, returning its entry. The difficulty of
+// headers which depend on blocks before the checkpoint is trusted.
+func (v *view) connect(raw [headerSize]byte, p *Params) (entry, error) {
+	height := v.tip() + 1
+	prev := v.at(height - 1)
+
+	e := newEntry(raw)
+	if e.prevHash() != prev.hash {
+		return e, fmt.Errorf("%w: block %s at height %d has parent %s, not %s", ErrPrevMismatch,
+			e.hash, height, e.prevHash(), prev.hash)
+	}
+	if err := checkProofOfWork(&e, p); err != nil {
+		return e, fmt.Errorf("%w: block %s at height %d", err, e.hash, height)
+	}
+	if bits, ok := v.nextBits(e.time(), p); ok && bits != e.bits() {
+		return e, fmt.Errorf("%w: block %s at height %d has bits %08x, expected %08x", ErrBadBits,
+			e.hash, height, e.bits(), bits)
+	}
+
+	new(big.Int).Add(prev.chainWork(), blockProof(e.bits())).FillBytes(e.work[:])
+	return e, nil
+}
+
+// nextBits returns the bits required of a header with the given time following the tip, and
+// whether enough of the chain is in view to work them out.
+func (v *view) nextBits(t uint32, p *Params) (uint32, bool) {
+	prevHeight := v.tip()
+	prev := v.at(prevHeight)
+
+	if p.NoRetargeting {
+		return prev.bits(), true
+	}
+	if prevHeight >= p.DAAHeight {
+		return v.nextDAABits(t, p)
+	}
+	return v.nextLegacyBits(t, p)
+}
+
+// nextDAABits returns the bits required by the difficulty adjustment algorithm, which targets
+// the work done over the last daaWindow blocks.
+func (v *view) nextDAABits(t uint32, p *Params) (uint32, bool) {
+	prevHeight := v.tip()
+	prev := v.at(prevHeight)
+	spacing := int64(p.TargetSpacing / time.Second)
+
+	if p.AllowMinDifficulty && int64(t) > int64(prev.time())+2*spacing {
+		return toCompact(p.PowLimit), true
+	}
+	if prevHeight < daaWindow+2 {
+		return 0, false
+	}
+
+	last := v.suitable(prevHeight)
+	first := v.suitable(prevHeight - daaWindow)
+	if last == nil || first == nil {
+		return 0, false
+	}
+
+	work := new(big.Int).Sub(last.chainWork(), first.chainWork())
+	work.Mul(work, big.NewInt(spacing))
+
+	timespan := int64(last.time()) - int64(first.time())
+	timespan = min(max(timespan, daaWindow/2*spacing), 2*daaWindow*spacing)
+	work.Div(work, big.NewInt(timespan))
+
+	target := new(big.Int).Sub(oneLsh256, work)
+	target.Div(target, work)
+	if target.Cmp(p.PowLimit) > 0 {
+		target = p.PowLimit
+	}
+	return toCompact(target), true
+}
+
+// nextLegacyBits returns the bits required by the rules before the difficulty adjustment
+// algorithm, retargeting every interval with emergency reductions in between.
+func (v *view) nextLegacyBits(t uint32, p *Params) (uint32, bool) {
+	prevHeight := v.tip()
+	prev := v.at(prevHeight)
+	interval := p.interval()
+	limit := toCompact(p.PowLimit)
+
+	if (prevHeight+1)%interval == 0 {
+		first := v.at(prevHeight + 1 - interval)
+		if first == nil {
+			return 0, false
+		}
+
+		timespan := int64(p.TargetTimespan / time.Second)
+		actual := min(max(int64(prev.time())-int64(first.time()), timespan/4), timespan*4)
+
+		target := fromCompact(prev.bits())
+		target.Mul(target, big.NewInt(actual))
+		target.Div(target, big.NewInt(timespan))
+		if target.Cmp(p.PowLimit) > 0 {
+			target = p.PowLimit
+		}
+		return toCompact(target), true
+	}
+
+	if p.AllowMinDifficulty {
+		if int64(t) > int64(prev.time())+2*int64(p.TargetSpacing/time.Second) {
+			return limit, true
+		}
+
+		// Otherwise the bits of the last block not mined under the min difficulty rule.
+		h, e := prevHeight, prev
+		for h > 0 && h%interval != 0 && e.bits() == limit {
+			h--
+			if e = v.at(h); e == nil {
+				return 0, false
+			}
+		}
+		return e.bits(), true
+	}
+
+	bits := prev.bits()
+	if bits == limit || prevHeight < 6 {
+		return bits, true
+	}
+
+	// The emergency difficulty adjustment, when the last 6 blocks took over 12 hours.
+	mtp, ok := v.medianTimePast(prevHeight)
+	if !ok {
+		return 0, false
+	}
+	mtp6, ok := v.medianTimePast(prevHeight - 6)
+	if !ok {
+		return 0, false
+	}
+	if mtp-mtp6 < int64(12*time.Hour/time.Second) {
+		return bits, true
+	}
+
+	target := fromCompact(bits)
+	target.Add(target, new(big.Int).Rsh(target, 2))
+	if target.Cmp(p.PowLimit) > 0 {
+		target = p.PowLimit
+	}
+	return toCompact(target), true
+}
+
+// suitable returns the entry with the median time of those at and the two below the height.
+func (v *view) suitable(height uint32) *entry {
+	ee := [3]*entry{v.at(height - 2), v.at(height - 1), v.at(height)}
+	if ee[0] == nil || ee[1] == nil || ee[2] == nil {
+		return nil
+	}
+
+	// The sorting network of the node, so ties pick the same entry.
+	if ee[0].time() > ee[2].time() {
+		ee[0], ee[2] = ee[2], ee[0]
+	}
+	if ee[0].time() > ee[1].time() {
+		ee[0], ee[1] = ee[1], ee[0]
+	}
+	if ee[1].time() > ee[2].time() {
+		ee[1], ee[2] = ee[2], ee[1]
+	}
+	return ee[1]
+}
+
+// medianTimePast returns the median time of the 11 entries up to the height.
+func (v *view) medianTimePast(height uint32) (int64, bool) {
+	tt := make([]int64, 0, 11)
+	for h := int64(height); h >= 0 && h > int64(height)-11; h-- {
+		e := v.at(uint32(h))
+		if e == nil {
+			return 0, false
+		}
+		tt = append(tt, int64(e.time()))
+	}
+
+	slices.Sort(tt)
+	return tt[len(tt)/2], true
+}
+
+// checkProofOfWork checks the hash of the header meets the target set by its bits.
+func checkProofOfWork(e *entry, p *Params) error {
+	target, ok := decodeCompact(e.bits())
+	if !ok || target.Sign() == 0 || target.Cmp(p.PowLimit) > 0 {
+		return fmt.Errorf("%w: bits %08x out of range", ErrProofOfWork, e.bits())
+	}
+
+	// The hash is little endian.
+	hash := e.hash
+	slices.Reverse(hash[:])
+	if new(big.Int).SetBytes(hash[:]).Cmp(target) > 0 {
+		return ErrProofOfWork
+	}
+	return nil
+}
+
+// blockProof returns the expected number of hashes needed to meet the target of the bits.
+func blockProof(bits uint32) *big.Int {
+	target, ok := decodeCompact(bits)
+	if !ok || target.Sign() == 0 {
+		return new(big.Int)
+	}
+
+	return target.Div(oneLsh256, target.Add(target, big.NewInt(1)))
+}
+
+// fromCompact returns the target encoded by the bits.
+func fromCompact(bits uint32) *big.Int {
+	target, _ := decodeCompact(bits)
+	return target
+}
+
+// decodeCompact returns the target encoded by the bits, and false if it is negative or
+// overflows 256 bits.
+func decodeCompact(bits uint32) (*big.Int, bool) {
+	size := bits >> 24
+	word := bits & 0x007fffff
+
+	var target *big.Int
+	if size <= 3 {
+		target = big.NewInt(int64(word >> (8 * (3 - size))))
+	} else {
+		target = new(big.Int).Lsh(big.NewInt(int64(word)), uint(8*(size-3)))
+	}
+
+	negative := word != 0 && bits&0x00800000 != 0
+	overflow := word != 0 && (size > 34 || (word > 0xff && size > 33) || (word > 0xffff && size > 32))
+	return target, !negative && !overflow
+}
+
+// toCompact encodes the target as bits.
+func toCompact(target *big.Int) uint32 {
+	size := uint32((target.BitLen() + 7) / 8)
+
+	var compact uint32
+	if size <= 3 {
+		compact = uint32(target.Uint64() << (8 * (3 - size)))
+	} else {
+		compact = uint32(new(big.Int).Rsh(target, uint(8*(size-3))).Uint64())
+	}
+
+	// The sign bit is set, so shift the mantissa into the next byte.
+	if compact&0x00800000 != 0 {
+		compact >>= 8
+		size++
+	}
+	return compact | size<<24
+}
@@ -0,0 +1,23 @@
+package headers
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bc"
+)
+
+// ErrorFunc handles an error syncing with the node while running.
+type ErrorFunc func(ctx context.Context, err error)
+
+// Header a validated block header in the best chain.
+type Header struct {
+	*bc.BlockHeader
+	Hash   string
+	Height uint32
+}
+
+// Checkpoint a block trusted to be in the best chain, from which the store is bootstrapped.
+type Checkpoint struct {
+	Height uint32
+	Hash   string
+}
@@ -1,8 +1,33 @@
 package config
 
+import "time"
+
 // RPC config.
 type RPC struct {
 	Host     string
 	Username string
 	Password string
 }
+
+// Retry config.
+type Retry struct {
+	// MaxAttempts the total number of attempts made for a request, including the first.
+	MaxAttempts int
+	// BaseDelay the delay before the first retry, doubling on each further retry.
+	BaseDelay time.Duration
+	// MaxDelay the upper bound of the delay between retries.
+	MaxDelay time.Duration
+	// NonIdempotent allow retrying methods which are not safe to repeat, such as `sendrawtransaction`.
+	NonIdempotent bool
+}
+
+// Pool config.
+type Pool struct {
+	// LeastLatency route requests to the node with the lowest latency rather than round-robin.
+	LeastLatency bool
+	// MaxBlockLag the number of blocks a node may lag the best chain tip of the pool
+	// before it is considered unhealthy. Zero disables the check.
+	MaxBlockLag uint32
+	// HealthCheckInterval the interval between health checks of the nodes.
+	HealthCheckInterval time.Duration
+}
@@ -0,0 +1,88 @@
+// Package poll repeats a sync with the node each time it is woken by a notification, or else
+// on an interval.
+package poll
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultInterval the default interval between syncs when nothing wakes the poller.
+const DefaultInterval = 30 * time.Second
+
+// Poller runs a sync each time it is woken, or else every interval.
+type Poller struct {
+	wake chan struct{}
+}
+
+// New returns a poller.
+func New() *Poller {
+	return &Poller{wake: make(chan struct{}, 1)}
+}
+
+// Wake makes Run sync without waiting out the interval. Wakes arriving during a sync are
+// coalesced into one further sync.
+func (p *Poller) Wake() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run calls fn straight away, then each time the poller is woken or the interval elapses,
+// until ctx is done. An error made with Fatal stops Run and is returned as it was before
+// wrapping, while any other is passed to errorFn, unless ctx is done, and retried.
+func (p *Poller) Run(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error,
+	errorFn func(ctx context.Context, err error),
+) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		err := fn(ctx)
+		var fe *fatalError
+		switch {
+		case errors.As(err, &fe):
+			return fe.err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			errorFn(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.wake:
+		case <-t.C:
+		}
+	}
+}
+
+// Fatal wraps err so that it stops Run rather than being retried.
+func Fatal(err error) error {
+	return &fatalError{err: err}
+}
+
+// Cause returns the error wrapped by Fatal, or err itself if it was not.
+func Cause(err error) error {
+	var fe *fatalError
+	if errors.As(err, &fe) {
+		return fe.err
+	}
+	return err
+}
+
+// fatalError an error which stops Run.
+type fatalError struct {
+	err error
+}
+
+func (e *fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fatalError) Unwrap() error {
+	return e.err
+}
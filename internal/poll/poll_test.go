@@ -0,0 +1,83 @@
+package poll_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+)
+
+var errOhNo = errors.New("oh no")
+
+func TestPoller_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("woken", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p := poll.New()
+		synced := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- p.Run(ctx, time.Hour, func(context.Context) error {
+				synced <- struct{}{}
+				return nil
+			}, func(context.Context, error) {})
+		}()
+
+		<-synced
+		p.Wake()
+		<-synced
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		err := poll.New().Run(context.TODO(), time.Millisecond, func(context.Context) error {
+			if calls.Add(1) == 3 {
+				return poll.Fatal(errOhNo)
+			}
+			return nil
+		}, func(context.Context, error) {})
+		require.ErrorIs(t, err, errOhNo)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("errors retried", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		var errs []error
+		err := poll.New().Run(context.TODO(), time.Millisecond, func(context.Context) error {
+			if calls.Add(1) < 3 {
+				return errOhNo
+			}
+			return poll.Fatal(context.DeadlineExceeded)
+		}, func(_ context.Context, err error) {
+			errs = append(errs, err)
+		})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, []error{errOhNo, errOhNo}, errs)
+	})
+}
+
+func TestCause(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, errOhNo, poll.Cause(poll.Fatal(errOhNo)))
+	assert.Equal(t, errOhNo, poll.Cause(errOhNo))
+	assert.NoError(t, poll.Cause(nil))
+}
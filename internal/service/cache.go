@@ -1,59 +1,344 @@
 package service
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
-	"reflect"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
 )
 
-type cache struct {
-	rpc   RPC
-	cache map[string]interface{}
+// DefaultCacheSize the default maximum number of responses held by the cache.
+const DefaultCacheSize = 4096
+
+// shortTTL the lifetime of responses that depend on the chain tip or the mempool.
+const shortTTL = 5 * time.Second
+
+// defaultCachePolicies the policies applied to each method unless overridden. Methods without
+// a policy are never cached, as they may have side effects.
+//
+// Only responses which never change are held indefinitely. Blocks, headers and transactions
+// are requested verbose, carrying their confirmations, next block hash and containing block,
+// all of which change as blocks arrive or are disconnected, so even those of confirmed blocks
+// and transactions are held only until the tip changes.
+var defaultCachePolicies = map[string]models.CachePolicy{
+	// Immutable once known.
+	"createmultisig":  {},
+	"getblockstats":   {},
+	"validateaddress": {},
+
+	// Dependent on the chain tip. Merkle proofs looked up by txid alone name the block found
+	// on the best chain.
+	"getbestblockhash":      {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblock":              {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockbyheight":      {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockchaininfo":     {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockcount":         {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockhash":          {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockheader":        {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getblockstatsbyheight": {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getchaintips":          {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getchaintxstats":       {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getdifficulty":         {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getinfo":               {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getmerkleproof2":       {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getmininginfo":         {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getnetworkhashps":      {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"getrawtransaction":     {TTL: shortTTL, Scope: models.CacheScopeTip},
+	"gettxoutsetinfo":       {TTL: shortTTL, Scope: models.CacheScopeTip},
+
+	// Dependent on the mempool.
+	"getmempoolancestors":   {TTL: shortTTL, Scope: models.CacheScopeMempool},
+	"getmempooldescendants": {TTL: shortTTL, Scope: models.CacheScopeMempool},
+	"getmempoolentry":       {TTL: shortTTL, Scope: models.CacheScopeMempool},
+	"getrawmempool":         {TTL: shortTTL, Scope: models.CacheScopeMempool},
+	"getrawnonfinalmempool": {TTL: shortTTL, Scope: models.CacheScopeMempool},
+	"gettxout":              {TTL: shortTTL, Scope: models.CacheScopeMempool},
+}
+
+// CacheOptFunc option func for configuring a Cache.
+type CacheOptFunc func(c *Cache)
+
+// WithCacheSize set the maximum number of responses held, least recently used
+// responses are evicted first.
+func WithCacheSize(n int) CacheOptFunc {
+	return func(c *Cache) {
+		c.size = n
+	}
+}
+
+// WithCachePolicy set the caching policy for a method.
+func WithCachePolicy(method string, p models.CachePolicy) CacheOptFunc {
+	return func(c *Cache) {
+		c.policies[method] = p
+	}
+}
+
+// Cache a concurrency safe, size bounded and TTL aware response cache around an RPC service.
+type Cache struct {
+	rpc      RPC
+	size     int
+	policies map[string]models.CachePolicy
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	stats   models.CacheStats
+}
+
+type cacheEntry struct {
+	key     string
+	value   json.RawMessage
+	scope   models.CacheScope
+	expires time.Time
 }
 
 // NewCache returns a cache wrapper around an RPC service.
-func NewCache(rpc RPC) RPC {
-	return &cache{
-		rpc:   rpc,
-		cache: make(map[string]interface{}),
+func NewCache(rpc RPC, oo ...CacheOptFunc) *Cache {
+	c := &Cache{
+		rpc:      rpc,
+		size:     DefaultCacheSize,
+		policies: make(map[string]models.CachePolicy, len(defaultCachePolicies)),
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	for k, v := range defaultCachePolicies {
+		c.policies[k] = v
 	}
+	for _, o := range oo {
+		o(c)
+	}
+
+	return c
 }
 
 // Do an RPC request with cache enabled.
-func (c *cache) Do(ctx context.Context, method string, out interface{}, args ...interface{}) error {
-	return c.do(ctx, request{method: method, args: args}, out)
+func (c *Cache) Do(ctx context.Context, method string, out interface{}, args ...interface{}) error {
+	call := &Call{Method: method, Args: args, Out: out}
+	if c.get(call) {
+		return call.Err
+	}
+
+	p := c.prepare(call)
+	if err := c.rpc.Do(ctx, method, call.Out, args...); err != nil {
+		return err
+	}
+	c.put(call, p)
+
+	return nil
 }
 
-// do executes the RPC request, checking the cache first.
-func (c *cache) do(ctx context.Context, r request, out interface{}) error {
-	if v, ok := c.cache[r.Key()]; ok && out != nil {
-		return c.write(v, out)
+// DoBatch performs a batch request, serving cached elements and sending the remainder
+// on to the underlying RPC in a single batch.
+func (c *Cache) DoBatch(ctx context.Context, calls ...*Call) error {
+	misses := make([]*Call, 0, len(calls))
+	recorders := make([]*recorder, 0, len(calls))
+	for _, call := range calls {
+		if c.get(call) {
+			continue
+		}
+		misses = append(misses, call)
+		recorders = append(recorders, c.prepare(call))
 	}
-	if err := c.rpc.Do(ctx, r.method, out, r.args...); err != nil {
+
+	if err := DoBatch(ctx, c.rpc, misses...); err != nil {
+		for i, call := range misses {
+			if recorders[i] != nil {
+				call.Out = recorders[i].out
+			}
+		}
 		return err
 	}
 
-	c.cache[r.Key()] = out
+	for i, call := range misses {
+		if call.Err == nil {
+			c.put(call, recorders[i])
+			continue
+		}
+		if recorders[i] != nil {
+			call.Out = recorders[i].out
+		}
+	}
+
 	return nil
 }
 
-// write writes the source value to the destination value.
-func (c *cache) write(dest, src interface{}) error {
-	drv := reflect.ValueOf(dest)
-	if drv.Kind() != reflect.Pointer || drv.IsNil() {
-		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(dest)}
+// DoStream performs a streaming request against the underlying RPC. Streamed results are
+// never cached.
+func (c *Cache) DoStream(ctx context.Context, method string, args ...interface{}) (io.ReadCloser, error) {
+	return DoStream(ctx, c.rpc, method, args...)
+}
+
+// Stats returns the cache statistics.
+func (c *Cache) Stats() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Invalidate drops all cached responses within the given scopes. Invalidating
+// CacheScopeTip also invalidates CacheScopeMempool, as a new block changes the mempool.
+func (c *Cache) Invalidate(scopes ...models.CacheScope) {
+	drop := make(map[models.CacheScope]bool, len(scopes)+1)
+	for _, scope := range scopes {
+		drop[scope] = true
+		if scope == models.CacheScopeTip {
+			drop[models.CacheScopeMempool] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*cacheEntry); e.scope != "" && drop[e.scope] {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+// get writes a cached response for the call into its Out, reporting whether one was found.
+func (c *Cache) get(call *Call) bool {
+	p, ok := c.policies[call.Method]
+	if !ok || p.NoCache || call.Out == nil {
+		return false
 	}
 
-	for drv.Kind() == reflect.Pointer {
-		drv = reflect.Indirect(drv)
+	key := request{method: call.Method, args: call.Args}.Key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if ok {
+		e := el.Value.(*cacheEntry)
+		if e.expires.IsZero() || time.Now().Before(e.expires) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			call.Err = decodeResult(e.value, call.Out)
+			return true
+		}
+		c.remove(el)
+	}
+
+	c.stats.Misses++
+	return false
+}
+
+// prepare wraps the Out of cacheable calls, so that the response of the node can be held
+// as received.
+func (c *Cache) prepare(call *Call) *recorder {
+	if p, ok := c.policies[call.Method]; !ok || p.NoCache || call.Out == nil {
+		return nil
+	}
+
+	r := &recorder{out: call.Out, target: call.Out}
+	if v, ok := call.Out.(interface {
+		NodeJSON() interface{}
+	}); ok {
+		r.target = v.NodeJSON()
+	}
+	call.Out = r
+	return r
+}
+
+// put stores the response of a successful call according to its method's policy.
+func (c *Cache) put(call *Call, r *recorder) {
+	if r == nil {
+		return
+	}
+	call.Out = r.out
+	if r.raw == nil {
+		return
+	}
+
+	p := c.policies[call.Method]
+	e := &cacheEntry{
+		key:   request{method: call.Method, args: call.Args}.Key(),
+		value: r.raw,
+		scope: p.Scope,
+	}
+	if p.TTL > 0 {
+		e.expires = time.Now().Add(p.TTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[e.key] = c.lru.PushFront(e)
+	for c.size > 0 && c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove an element from the cache. Must be called with the lock held.
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// decodeResult decodes a cached response into out as it was first decoded, passing it through
+// the NodeJSON and PostProcess hooks of out where implemented. Each hit is decoded afresh, so
+// changes made by the caller to its response are not reflected in the cache.
+func decodeResult(raw json.RawMessage, out interface{}) error {
+	if v, ok := out.(interface {
+		NodeJSON() interface{}
+	}); ok {
+		out = v.NodeJSON()
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return err
+	}
+
+	if v, ok := out.(interface {
+		PostProcess() error
+	}); ok {
+		return v.PostProcess()
 	}
 
-	srv := reflect.ValueOf(src)
-	for srv.Kind() == reflect.Pointer {
-		srv = reflect.Indirect(srv)
+	return nil
+}
+
+// recorder wraps a response target, holding the response as received.
+type recorder struct {
+	out    interface{}
+	target interface{}
+	raw    json.RawMessage
+}
+
+// UnmarshalJSON unmarshal the response into the wrapped target, holding a copy of it.
+func (r *recorder) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, r.target); err != nil {
+		return err
 	}
+	r.raw = append(json.RawMessage(nil), b...)
 
-	drv.Set(srv)
+	return nil
+}
+
+// PostProcess the wrapped target.
+func (r *recorder) PostProcess() error {
+	if v, ok := r.target.(interface {
+		PostProcess() error
+	}); ok {
+		return v.PostProcess()
+	}
 
 	return nil
 }
@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/internal/config"
+	"github.com/bsv-blockchain/go-bn/internal/mocks"
+	"github.com/bsv-blockchain/go-bn/internal/service"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+func TestCache_Do(t *testing.T) {
+	t.Parallel()
+
+	type invocation struct {
+		method string
+		args   []interface{}
+		sleep  time.Duration
+	}
+	tests := map[string]struct {
+		opts        []service.CacheOptFunc
+		invocations []invocation
+		expCalls    int32
+		expStats    models.CacheStats
+	}{
+		"immutable responses are cached": {
+			invocations: []invocation{
+				{method: "getblockstats", args: []interface{}{"abc"}},
+				{method: "getblockstats", args: []interface{}{"abc"}},
+				{method: "getblockstats", args: []interface{}{"def"}},
+			},
+			expCalls: 2,
+			expStats: models.CacheStats{Hits: 1, Misses: 2, Entries: 2},
+		},
+		"methods with side effects are never cached": {
+			invocations: []invocation{
+				{method: "sendrawtransaction", args: []interface{}{"00"}},
+				{method: "sendrawtransaction", args: []interface{}{"00"}},
+				{method: "stop"},
+				{method: "stop"},
+			},
+			expCalls: 4,
+			expStats: models.CacheStats{},
+		},
+		"expired responses are refetched": {
+			opts: []service.CacheOptFunc{
+				service.WithCachePolicy("getbestblockhash", models.CachePolicy{TTL: 10 * time.Millisecond}),
+			},
+			invocations: []invocation{
+				{method: "getbestblockhash"},
+				{method: "getbestblockhash"},
+				{method: "getbestblockhash", sleep: 20 * time.Millisecond},
+			},
+			expCalls: 2,
+			expStats: models.CacheStats{Hits: 1, Misses: 2, Entries: 1},
+		},
+		"least recently used responses are evicted": {
+			opts: []service.CacheOptFunc{service.WithCacheSize(2)},
+			invocations: []invocation{
+				{method: "getblock", args: []interface{}{"a"}},
+				{method: "getblock", args: []interface{}{"b"}},
+				{method: "getblock", args: []interface{}{"a"}},
+				{method: "getblock", args: []interface{}{"c"}},
+				{method: "getblock", args: []interface{}{"a"}},
+				{method: "getblock", args: []interface{}{"b"}},
+			},
+			expCalls: 4,
+			expStats: models.CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2},
+		},
+		"policy can disable caching of a method": {
+			opts: []service.CacheOptFunc{
+				service.WithCachePolicy("getblock", models.CachePolicy{NoCache: true}),
+			},
+			invocations: []invocation{
+				{method: "getblock", args: []interface{}{"abc"}},
+				{method: "getblock", args: []interface{}{"abc"}},
+			},
+			expCalls: 2,
+			expStats: models.CacheStats{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var timesCalled int32
+			c := service.NewCache(&mocks.MockRPC{
+				DoFunc: func(_ context.Context, method string, out interface{}, _ ...interface{}) error {
+					atomic.AddInt32(&timesCalled, 1)
+					return json.Unmarshal([]byte(`"`+method+`"`), out)
+				},
+			}, test.opts...)
+
+			for _, inv := range test.invocations {
+				time.Sleep(inv.sleep)
+				var resp string
+				require.NoError(t, c.Do(context.TODO(), inv.method, &resp, inv.args...))
+				assert.Equal(t, inv.method, resp)
+			}
+
+			assert.Equal(t, test.expCalls, timesCalled)
+			assert.Equal(t, test.expStats, c.Stats())
+		})
+	}
+}
+
+func TestCache_Do_Transaction(t *testing.T) {
+	t.Parallel()
+
+	var timesCalled int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&timesCalled, 1)
+
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		bb, err := json.Marshal(models.Response{
+			Result: map[string]interface{}{
+				"txid":          req.Params[0],
+				"confirmations": n,
+			},
+		})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer svr.Close()
+
+	c := service.NewCache(service.NewRPC(&config.RPC{
+		Host: svr.URL,
+	}, &http.Client{}))
+
+	type tx struct {
+		TxID          string `json:"txid"`
+		Confirmations int    `json:"confirmations"`
+	}
+	confs := func() int {
+		var resp tx
+		require.NoError(t, c.Do(context.TODO(), "getrawtransaction", &resp, "abc", true))
+		assert.Equal(t, "abc", resp.TxID)
+		return resp.Confirmations
+	}
+
+	// Confirmed transactions are held until the tip changes, as their confirmations do.
+	assert.Equal(t, 1, confs())
+	assert.Equal(t, 1, confs())
+	c.Invalidate(models.CacheScopeTip)
+	assert.Equal(t, 2, confs())
+	assert.Equal(t, int32(2), timesCalled)
+}
+
+func TestCache_Do_Copies(t *testing.T) {
+	t.Parallel()
+
+	c := service.NewCache(&mocks.MockRPC{
+		DoFunc: func(_ context.Context, _ string, out interface{}, _ ...interface{}) error {
+			return json.Unmarshal([]byte(`{"header":{"bits":"1d00ffff"},"tx":["a","b"]}`), out)
+		},
+	})
+
+	type block struct {
+		Header *struct {
+			Bits string `json:"bits"`
+		} `json:"header"`
+		Tx []string `json:"tx"`
+	}
+
+	var first block
+	require.NoError(t, c.Do(context.TODO(), "getblock", &first, "abc"))
+	first.Header.Bits = "changed"
+	first.Tx[0] = "changed"
+
+	var second block
+	require.NoError(t, c.Do(context.TODO(), "getblock", &second, "abc"))
+	assert.Equal(t, "1d00ffff", second.Header.Bits)
+	assert.Equal(t, []string{"a", "b"}, second.Tx)
+	assert.Equal(t, uint64(1), c.Stats().Hits)
+}
+
+func TestCache_Do_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	c := service.NewCache(&mocks.MockRPC{
+		DoFunc: func(_ context.Context, _ string, out interface{}, args ...interface{}) error {
+			return json.Unmarshal([]byte(`"`+args[0].(string)+`"`), out)
+		},
+	}, service.WithCacheSize(8))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := string(rune('a' + j%16))
+				var resp string
+				assert.NoError(t, c.Do(context.TODO(), "getblock", &resp, key))
+				assert.Equal(t, key, resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(5000), stats.Hits+stats.Misses)
+	assert.LessOrEqual(t, stats.Entries, 8)
+}
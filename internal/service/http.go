@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/singleflight"
@@ -20,8 +21,15 @@ const (
 	JSONRpc = "1.0"
 )
 
-// ErrRPCQuery error when rpc query fails.
-var ErrRPCQuery = errors.New("failed to perform rpc query")
+// Standard errors.
+var (
+	// ErrRPCQuery error when rpc query fails.
+	ErrRPCQuery = errors.New("failed to perform rpc query")
+	// ErrBatchReplyMissing error when a batch response holds no reply for a request.
+	ErrBatchReplyMissing = errors.New("no reply for batch request")
+	// ErrResultNotHex error when a streamed result is not a hex string.
+	ErrResultNotHex = errors.New("result is not a hex string")
+)
 
 type rpc struct {
 	c   *http.Client
@@ -45,47 +53,173 @@ func (h *rpc) Do(ctx context.Context, method string, out interface{}, args ...in
 
 func (h *rpc) do(ctx context.Context, r request, out interface{}) error {
 	data, err, _ := h.g.Do(r.Key(), func() (interface{}, error) {
-		data, err := json.Marshal(&models.Request{
+		return h.post(ctx, &models.Request{
 			ID:      ID,
 			JSONRpc: JSONRpc,
 			Method:  r.method,
 			Params:  r.args,
 		})
-		if err != nil {
-			return nil, err
+	})
+	if err != nil {
+		return err
+	}
+
+	return decode(data.([]byte), out)
+}
+
+// DoBatch performs the calls as a single JSON-RPC batch request. A returned error
+// means the batch as a whole failed, errors for individual elements are set on each call.
+func (h *rpc) DoBatch(ctx context.Context, calls ...*Call) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	reqs := make([]*models.Request, len(calls))
+	for i, call := range calls {
+		reqs[i] = &models.Request{
+			ID:      batchID(i),
+			JSONRpc: JSONRpc,
+			Method:  call.Method,
+			Params:  call.Args,
 		}
+	}
 
-		req, err := http.NewRequestWithContext(
-			ctx,
-			http.MethodPost,
-			h.cfg.Host,
-			bytes.NewReader(data),
-		)
-		if err != nil {
-			return nil, err
+	data, err := h.post(ctx, reqs)
+	if err != nil {
+		return err
+	}
+
+	var replies []json.RawMessage
+	if err = json.Unmarshal(data, &replies); err != nil {
+		// The node replies with a single response object when rejecting the batch outright.
+		var reply models.Response
+		if json.Unmarshal(data, &reply) == nil && reply.Error != nil {
+			return reply.Error
 		}
-		req.SetBasicAuth(h.cfg.Username, h.cfg.Password)
-		req.Header.Add("Content-Type", "text/plain")
+		return err
+	}
 
-		resp, err := h.c.Do(req)
-		if err != nil {
-			return nil, err
+	byID := make(map[string]json.RawMessage, len(replies))
+	for _, reply := range replies {
+		var r struct {
+			ID string `json:"id"`
 		}
+		if err = json.Unmarshal(reply, &r); err != nil {
+			return err
+		}
+		byID[r.ID] = reply
+	}
+
+	for i, call := range calls {
+		reply, ok := byID[batchID(i)]
+		if !ok {
+			call.Err = ErrBatchReplyMissing
+			continue
+		}
+		call.Err = decode(reply, call.Out)
+	}
+
+	return nil
+}
+
+// DoStream performs an RPC request whose result is a hex string, returning a reader which
+// decodes it as the response body arrives. The client timeout does not apply, as reading a
+// large result may take a long time, so the request is bounded by ctx alone.
+func (h *rpc) DoStream(ctx context.Context, method string, args ...interface{}) (io.ReadCloser, error) {
+	c := *h.c
+	c.Timeout = 0
+
+	resp, err := h.send(ctx, &c, &models.Request{
+		ID:      ID,
+		JSONRpc: JSONRpc,
+		Method:  method,
+		Params:  args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
 		defer func() {
 			_ = resp.Body.Close()
 		}()
 
-		bb, err := io.ReadAll(resp.Body)
+		bb, err := read(resp)
 		if err != nil {
 			return nil, err
 		}
+		if err = decode(bb, nil); err != nil {
+			return nil, err
+		}
+		return nil, ErrRPCQuery
+	}
 
-		return bb, nil
-	})
+	r, err := streamResult(resp.Body)
 	if err != nil {
-		return err
+		_ = resp.Body.Close()
+		return nil, err
 	}
 
+	return r, nil
+}
+
+// post sends the payload to the node and returns the raw response body.
+func (h *rpc) post(ctx context.Context, payload interface{}) ([]byte, error) {
+	resp, err := h.send(ctx, h.c, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return read(resp)
+}
+
+// send posts the payload to the node, returning the response with its body unread.
+func (h *rpc) send(ctx context.Context, c *http.Client, payload interface{}) (*http.Response, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		h.cfg.Host,
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(h.cfg.Username, h.cfg.Password)
+	req.Header.Add("Content-Type", "text/plain")
+
+	return c.Do(req)
+}
+
+// read the body of the response.
+func read(resp *http.Response) ([]byte, error) {
+	bb, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The node replies to RPC errors with an error status and a JSON-RPC response, which is
+	// decoded as usual. Anything else is a transport level failure.
+	if resp.StatusCode >= http.StatusBadRequest && !json.Valid(bb) {
+		return nil, &models.HTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       string(bytes.TrimSpace(bb)),
+		}
+	}
+
+	return bb, nil
+}
+
+// decode a single response, passing the result through the NodeJSON and PostProcess
+// hooks of out where implemented.
+func decode(data []byte, out interface{}) error {
 	if v, ok := out.(interface {
 		NodeJSON() interface{}
 	}); ok {
@@ -95,7 +229,7 @@ func (h *rpc) do(ctx context.Context, r request, out interface{}) error {
 	reply := models.Response{
 		Result: out,
 	}
-	if err = json.NewDecoder(bytes.NewBuffer(data.([]byte))).Decode(&reply); err != nil {
+	if err := json.NewDecoder(bytes.NewBuffer(data)).Decode(&reply); err != nil {
 		return err
 	}
 
@@ -111,3 +245,8 @@ func (h *rpc) do(ctx context.Context, r request, out interface{}) error {
 
 	return nil
 }
+
+// batchID the request id of the i-th element of a batch.
+func batchID(i int) string {
+	return ID + "-" + strconv.Itoa(i)
+}
@@ -1,8 +1,11 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -117,3 +120,198 @@ func TestRPC_Do_SingleFlight(t *testing.T) {
 		})
 	}
 }
+
+func TestRPC_DoBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		calls      []*service.Call
+		reply      func(reqs []models.Request) interface{}
+		expResults []interface{}
+		expErrs    []error
+		expErr     error
+	}{
+		"successful batch": {
+			calls: []*service.Call{
+				{Method: "getblockhash", Args: []interface{}{1}, Out: new(string)},
+				{Method: "getblockhash", Args: []interface{}{2}, Out: new(string)},
+			},
+			reply: func(reqs []models.Request) interface{} {
+				return []map[string]interface{}{
+					{"id": reqs[1].ID, "result": "hash2", "error": nil},
+					{"id": reqs[0].ID, "result": "hash1", "error": nil},
+				}
+			},
+			expResults: []interface{}{"hash1", "hash2"},
+			expErrs:    []error{nil, nil},
+		},
+		"element errors are set per call": {
+			calls: []*service.Call{
+				{Method: "getblockhash", Args: []interface{}{1}, Out: new(string)},
+				{Method: "getblockhash", Args: []interface{}{99999999}, Out: new(string)},
+			},
+			reply: func(reqs []models.Request) interface{} {
+				return []map[string]interface{}{
+					{"id": reqs[0].ID, "result": "hash1", "error": nil},
+					{"id": reqs[1].ID, "result": nil, "error": map[string]interface{}{
+						"code": -8, "message": "Block height out of range",
+					}},
+				}
+			},
+			expResults: []interface{}{"hash1", ""},
+			expErrs:    []error{nil, &models.Error{Code: -8, Message: "Block height out of range"}},
+		},
+		"missing replies are reported": {
+			calls: []*service.Call{
+				{Method: "getblockhash", Args: []interface{}{1}, Out: new(string)},
+				{Method: "getblockhash", Args: []interface{}{2}, Out: new(string)},
+			},
+			reply: func(reqs []models.Request) interface{} {
+				return []map[string]interface{}{
+					{"id": reqs[0].ID, "result": "hash1", "error": nil},
+				}
+			},
+			expResults: []interface{}{"hash1", ""},
+			expErrs:    []error{nil, service.ErrBatchReplyMissing},
+		},
+		"batch rejected outright": {
+			calls: []*service.Call{
+				{Method: "getblockhash", Args: []interface{}{1}, Out: new(string)},
+			},
+			reply: func([]models.Request) interface{} {
+				return map[string]interface{}{
+					"result": nil,
+					"error":  map[string]interface{}{"code": -32700, "message": "Parse error"},
+				}
+			},
+			expErr: &models.Error{Code: -32700, Message: "Parse error"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var timesCalled int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&timesCalled, 1)
+
+				var reqs []models.Request
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+				assert.Len(t, reqs, len(test.calls))
+
+				bb, err := json.Marshal(test.reply(reqs))
+				assert.NoError(t, err)
+				_, _ = w.Write(bb)
+			}))
+			defer svr.Close()
+
+			c := service.NewRPC(&config.RPC{
+				Host: svr.URL,
+			}, &http.Client{})
+
+			err := service.DoBatch(context.TODO(), c, test.calls...)
+			assert.Equal(t, int32(1), timesCalled)
+			if test.expErr != nil {
+				require.Error(t, err)
+				require.EqualError(t, err, test.expErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			for i, call := range test.calls {
+				assert.Equal(t, test.expErrs[i], call.Err)
+				assert.Equal(t, test.expResults[i], *call.Out.(*string))
+			}
+		})
+	}
+}
+
+func TestRPC_DoStream(t *testing.T) {
+	t.Parallel()
+
+	large := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 100000)
+	tests := map[string]struct {
+		status int
+		body   string
+		exp    []byte
+		expErr error
+	}{
+		"successful query": {
+			body: `{"result":"0a0b0c","error":null,"id":"go-bn"}`,
+			exp:  []byte{0x0a, 0x0b, 0x0c},
+		},
+		"result larger than the buffer": {
+			body: `{"result": "` + hex.EncodeToString(large) + `", "error": null, "id": "go-bn"}`,
+			exp:  large,
+		},
+		"error status": {
+			status: http.StatusInternalServerError,
+			body:   `{"result":null,"error":{"code":-5,"message":"Block not found"},"id":"go-bn"}`,
+			expErr: &models.Error{Code: -5, Message: "Block not found"},
+		},
+		"error following null result": {
+			body:   `{"result":null,"error":{"code":-5,"message":"Block not found"},"id":"go-bn"}`,
+			expErr: &models.Error{Code: -5, Message: "Block not found"},
+		},
+		"error preceding result": {
+			body:   `{"error":{"code":-5,"message":"Block not found"},"result":null,"id":"go-bn"}`,
+			expErr: &models.Error{Code: -5, Message: "Block not found"},
+		},
+		"result not a string": {
+			body:   `{"result":5,"error":null,"id":"go-bn"}`,
+			expErr: service.ErrResultNotHex,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+				}
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer svr.Close()
+
+			r := service.NewRPC(&config.RPC{Host: svr.URL}, &http.Client{})
+
+			rc, err := r.(service.StreamRPC).DoStream(context.TODO(), "getblock", "abc", 0)
+			if test.expErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, test.expErr, err)
+				return
+			}
+			require.NoError(t, err)
+			defer func() {
+				assert.NoError(t, rc.Close())
+			}()
+
+			bb, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, bb)
+		})
+	}
+}
+
+func TestRPC_DoStream_IgnoresClientTimeout(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"0a0b`))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`0c","error":null,"id":"go-bn"}`))
+	}))
+	defer svr.Close()
+
+	r := service.NewRPC(&config.RPC{Host: svr.URL}, &http.Client{Timeout: 50 * time.Millisecond})
+
+	rc, err := r.(service.StreamRPC).DoStream(context.TODO(), "getblock", "abc", 0)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, rc.Close())
+	}()
+
+	bb, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0a, 0x0b, 0x0c}, bb)
+}
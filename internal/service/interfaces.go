@@ -1,8 +1,11 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
+	"io"
 )
 
 // RPC interface with a rpc server.
@@ -10,6 +13,63 @@ type RPC interface {
 	Do(ctx context.Context, method string, out interface{}, args ...interface{}) error
 }
 
+// BatchRPC interface with a rpc server capable of JSON-RPC batch requests.
+type BatchRPC interface {
+	DoBatch(ctx context.Context, calls ...*Call) error
+}
+
+// StreamRPC interface with a rpc server capable of streaming hex string results, such as
+// `getblock` with verbosity 0, without holding them in memory.
+type StreamRPC interface {
+	DoStream(ctx context.Context, method string, args ...interface{}) (io.ReadCloser, error)
+}
+
+// Call a single element of a batch request. Out is populated with the result and
+// Err with the error returned by the node for this element.
+type Call struct {
+	Method string
+	Args   []interface{}
+	Out    interface{}
+	Err    error
+}
+
+// DoBatch executes the calls as a single batch request when the RPC supports it,
+// otherwise falls back to performing each call in turn.
+func DoBatch(ctx context.Context, r RPC, calls ...*Call) error {
+	if b, ok := r.(BatchRPC); ok {
+		return b.DoBatch(ctx, calls...)
+	}
+
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		call.Err = r.Do(ctx, call.Method, call.Out, call.Args...)
+	}
+
+	return nil
+}
+
+// DoStream performs a request whose result is a hex string, returning a reader over the
+// decoded bytes which must be closed. When the RPC cannot stream, the result is read
+// into memory in full.
+func DoStream(ctx context.Context, r RPC, method string, args ...interface{}) (io.ReadCloser, error) {
+	if s, ok := r.(StreamRPC); ok {
+		return s.DoStream(ctx, method, args...)
+	}
+
+	var resp string
+	if err := r.Do(ctx, method, &resp, args...); err != nil {
+		return nil, err
+	}
+	bb, err := hex.DecodeString(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(bb)), nil
+}
+
 type request struct {
 	method string
 	args   []interface{}
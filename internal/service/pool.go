@@ -0,0 +1,291 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/internal/config"
+)
+
+// DefaultHealthCheckInterval the default interval between health checks of the nodes in a pool.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// healthCheckTimeout the time a node has to answer a health check.
+const healthCheckTimeout = 10 * time.Second
+
+// walletMethods methods operating on the wallet of a node. As wallets are not shared
+// between nodes, these are routed to the same node for as long as it stays healthy.
+var walletMethods = map[string]bool{
+	"abandontransaction":     true,
+	"addmultisigaddress":     true,
+	"backupwallet":           true,
+	"dumpprivkey":            true,
+	"dumpwallet":             true,
+	"encryptwallet":          true,
+	"getaccount":             true,
+	"getaccountaddress":      true,
+	"getaddressesbyaccount":  true,
+	"getbalance":             true,
+	"getnewaddress":          true,
+	"getrawchangeaddress":    true,
+	"getreceivedbyaddress":   true,
+	"gettransaction":         true,
+	"getunconfirmedbalance":  true,
+	"getwalletinfo":          true,
+	"importaddress":          true,
+	"importmulti":            true,
+	"importprivkey":          true,
+	"importprunedfunds":      true,
+	"importpubkey":           true,
+	"importwallet":           true,
+	"keypoolrefill":          true,
+	"listaccounts":           true,
+	"listlockunspent":        true,
+	"listreceivedbyaccount":  true,
+	"listreceivedbyaddress":  true,
+	"listsinceblock":         true,
+	"listtransactions":       true,
+	"listunspent":            true,
+	"listwallets":            true,
+	"lockunspent":            true,
+	"move":                   true,
+	"removeprunedfunds":      true,
+	"sendfrom":               true,
+	"sendmany":               true,
+	"sendtoaddress":          true,
+	"setaccount":             true,
+	"settxfee":               true,
+	"signmessage":            true,
+	"walletlock":             true,
+	"walletpassphrase":       true,
+	"walletpassphrasechange": true,
+}
+
+type poolNode struct {
+	rpc     RPC
+	healthy atomic.Bool
+	latency atomic.Int64
+}
+
+type pool struct {
+	nodes  []*poolNode
+	cfg    *config.Pool
+	next   atomic.Uint64
+	sticky atomic.Pointer[poolNode]
+
+	initial sync.Once
+	ready   chan struct{}
+
+	mu       sync.Mutex
+	checked  time.Time
+	checking bool
+}
+
+// NewPool returns an RPC service spreading requests over several nodes.
+//
+// Nodes are health checked with `getblockcount` every cfg.HealthCheckInterval, requests
+// waiting on the initial check. A node is unhealthy when it fails to answer, or
+// lags the best height in the pool by more than cfg.MaxBlockLag blocks. Requests go to
+// healthy nodes round-robin, or by least latency, falling back to unhealthy nodes only
+// when no healthy node is left. Wallet methods stick to a single node.
+//
+// A request failing transiently on one node marks it unhealthy and is repeated on the next.
+// Methods which are unsafe to repeat, such as `sendrawtransaction`, only fail over when the
+// connection was refused, as the failed node cannot have processed them.
+func NewPool(nodes []RPC, cfg *config.Pool) RPC {
+	p := &pool{
+		nodes: make([]*poolNode, len(nodes)),
+		cfg:   cfg,
+		ready: make(chan struct{}),
+	}
+	for i, rpc := range nodes {
+		p.nodes[i] = &poolNode{rpc: rpc}
+		p.nodes[i].healthy.Store(true)
+	}
+
+	return p
+}
+
+// Do an RPC request against a node of the pool.
+func (p *pool) Do(ctx context.Context, method string, out interface{}, args ...interface{}) error {
+	return p.route(ctx, walletMethods[method], !nonIdempotent[method], func(n *poolNode) error {
+		return n.rpc.Do(ctx, method, out, args...)
+	})
+}
+
+// DoBatch performs the calls as a batch against a single node of the pool.
+func (p *pool) DoBatch(ctx context.Context, calls ...*Call) error {
+	wallet, idempotent := false, true
+	for _, call := range calls {
+		wallet = wallet || walletMethods[call.Method]
+		idempotent = idempotent && !nonIdempotent[call.Method]
+	}
+
+	return p.route(ctx, wallet, idempotent, func(n *poolNode) error {
+		return DoBatch(ctx, n.rpc, calls...)
+	})
+}
+
+// DoStream performs a streaming request against a node of the pool.
+func (p *pool) DoStream(ctx context.Context, method string, args ...interface{}) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := p.route(ctx, walletMethods[method], !nonIdempotent[method], func(n *poolNode) error {
+		var err error
+		rc, err = DoStream(ctx, n.rpc, method, args...)
+		return err
+	})
+
+	return rc, err
+}
+
+// route calls fn against each candidate node in turn, until one does not fail over.
+func (p *pool) route(ctx context.Context, wallet, idempotent bool, fn func(n *poolNode) error) error {
+	p.checkHealth(ctx)
+
+	var err error
+	for _, n := range p.candidates(wallet) {
+		err = fn(n)
+		if err != nil && ctx.Err() == nil && failover(err, idempotent) {
+			n.healthy.Store(false)
+			continue
+		}
+
+		if wallet {
+			p.sticky.Store(n)
+		}
+		return err
+	}
+
+	return err
+}
+
+// candidates returns the nodes in the order they should be tried.
+func (p *pool) candidates(wallet bool) []*poolNode {
+	healthy := make([]*poolNode, 0, len(p.nodes))
+	unhealthy := make([]*poolNode, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		if n.healthy.Load() {
+			healthy = append(healthy, n)
+		} else {
+			unhealthy = append(unhealthy, n)
+		}
+	}
+
+	switch {
+	case len(healthy) == 0:
+	case p.cfg.LeastLatency:
+		slices.SortStableFunc(healthy, func(a, b *poolNode) int {
+			return cmp.Compare(a.latency.Load(), b.latency.Load())
+		})
+	default:
+		i := int(p.next.Add(1)-1) % len(healthy)
+		healthy = slices.Concat(healthy[i:], healthy[:i])
+	}
+
+	// The sticky node is only preferred if it was healthy above, as it may since have been
+	// marked otherwise.
+	if s := p.sticky.Load(); wallet && s != nil {
+		if i := slices.Index(healthy, s); i >= 0 {
+			healthy = slices.Concat([]*poolNode{s}, healthy[:i], healthy[i+1:])
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// checkHealth checks the health of the nodes when due. The initial check is waited on by
+// every request arriving before it completes, later checks run in the background.
+func (p *pool) checkHealth(ctx context.Context) {
+	interval := p.cfg.HealthCheckInterval
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+
+	// Checks outlive the requests which trigger them.
+	bg := context.WithoutCancel(ctx)
+	p.initial.Do(func() {
+		p.mu.Lock()
+		p.checking = true
+		p.mu.Unlock()
+
+		go func() {
+			defer close(p.ready)
+			p.check(bg)
+		}()
+	})
+
+	select {
+	case <-p.ready:
+	case <-ctx.Done():
+		return
+	}
+
+	p.mu.Lock()
+	due := !p.checking && time.Since(p.checked) >= interval
+	if due {
+		p.checking = true
+	}
+	p.mu.Unlock()
+
+	if due {
+		go p.check(bg)
+	}
+}
+
+// check the health of each node, measuring its latency and height.
+func (p *pool) check(ctx context.Context) {
+	defer func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.checked = time.Now()
+		p.checking = false
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	heights := make([]uint32, len(p.nodes))
+	errs := make([]error, len(p.nodes))
+
+	var wg sync.WaitGroup
+	for i, n := range p.nodes {
+		wg.Go(func() {
+			start := time.Now()
+			errs[i] = n.rpc.Do(ctx, "getblockcount", &heights[i])
+			n.latency.Store(int64(time.Since(start)))
+		})
+	}
+	wg.Wait()
+
+	var best uint32
+	for i, height := range heights {
+		if errs[i] == nil {
+			best = max(best, height)
+		}
+	}
+
+	for i, n := range p.nodes {
+		n.healthy.Store(errs[i] == nil && (p.cfg.MaxBlockLag == 0 || best-heights[i] <= p.cfg.MaxBlockLag))
+	}
+}
+
+// failover reports whether a request failing with err should be repeated on another node.
+func failover(err error, idempotent bool) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+
+	var netErr net.Error
+	return Retryable(err) || (errors.As(err, &netErr) && netErr.Timeout())
+}
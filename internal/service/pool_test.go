@@ -0,0 +1,273 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/internal/config"
+	"github.com/bsv-blockchain/go-bn/internal/service"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+type poolNode struct {
+	height uint32
+	delay  time.Duration
+	down   bool
+	status int
+}
+
+func TestPool_Do(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		nodes    []poolNode
+		cfg      config.Pool
+		method   string
+		calls    int
+		expNodes []string
+		expErr   bool
+	}{
+		"round robin": {
+			nodes:    []poolNode{{height: 100}, {height: 100}, {height: 100}},
+			method:   "getbestblockhash",
+			calls:    6,
+			expNodes: []string{"node-0", "node-1", "node-2", "node-0", "node-1", "node-2"},
+		},
+		"least latency": {
+			nodes:    []poolNode{{height: 100, delay: 50 * time.Millisecond}, {height: 100}},
+			cfg:      config.Pool{LeastLatency: true},
+			method:   "getbestblockhash",
+			calls:    3,
+			expNodes: []string{"node-1", "node-1", "node-1"},
+		},
+		"lagging node is avoided": {
+			nodes:    []poolNode{{height: 100}, {height: 90}, {height: 98}},
+			cfg:      config.Pool{MaxBlockLag: 5},
+			method:   "getbestblockhash",
+			calls:    4,
+			expNodes: []string{"node-0", "node-2", "node-0", "node-2"},
+		},
+		"lag is ignored when disabled": {
+			nodes:    []poolNode{{height: 100}, {height: 90}},
+			method:   "getbestblockhash",
+			calls:    2,
+			expNodes: []string{"node-0", "node-1"},
+		},
+		"down node is failed over": {
+			nodes:    []poolNode{{height: 100}, {down: true}, {height: 100}},
+			method:   "getbestblockhash",
+			calls:    4,
+			expNodes: []string{"node-0", "node-2", "node-0", "node-2"},
+		},
+		"failing node is failed over": {
+			nodes:    []poolNode{{height: 100, status: http.StatusServiceUnavailable}, {height: 100}},
+			method:   "getbestblockhash",
+			calls:    3,
+			expNodes: []string{"node-1", "node-1", "node-1"},
+		},
+		"non-idempotent methods are not failed over": {
+			nodes:  []poolNode{{height: 100, status: http.StatusServiceUnavailable}, {height: 100}},
+			method: "sendrawtransaction",
+			calls:  1,
+			expErr: true,
+		},
+		"wallet methods are sticky": {
+			nodes:    []poolNode{{height: 100}, {height: 100}, {height: 100}},
+			method:   "getbalance",
+			calls:    4,
+			expNodes: []string{"node-0", "node-0", "node-0", "node-0"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodes := make([]service.RPC, len(test.nodes))
+			for i, n := range test.nodes {
+				svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var req models.Request
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+					time.Sleep(n.delay)
+
+					var result interface{} = "node-" + strconv.Itoa(i)
+					switch {
+					case req.Method == "getblockcount":
+						result = n.height
+					case n.status != 0:
+						w.WriteHeader(n.status)
+						return
+					}
+
+					bb, err := json.Marshal(models.Response{Result: result})
+					assert.NoError(t, err)
+					_, _ = w.Write(bb)
+				}))
+				if n.down {
+					svr.Close()
+				} else {
+					defer svr.Close()
+				}
+
+				nodes[i] = service.NewRPC(&config.RPC{Host: svr.URL}, &http.Client{})
+			}
+
+			p := service.NewPool(nodes, &test.cfg)
+
+			var served []string
+			for range test.calls {
+				var resp string
+				err := p.Do(context.TODO(), test.method, &resp)
+				if test.expErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				served = append(served, resp)
+			}
+			assert.Equal(t, test.expNodes, served)
+		})
+	}
+}
+
+func TestPool_Do_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	height := uint32(100)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		var result interface{} = height
+		mu.Unlock()
+		if req.Method != "getblockcount" {
+			result = "node-0"
+		}
+
+		bb, err := json.Marshal(models.Response{Result: result})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer svr.Close()
+
+	lagging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{} = uint32(100)
+		if req.Method != "getblockcount" {
+			result = "node-1"
+		}
+
+		bb, err := json.Marshal(models.Response{Result: result})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer lagging.Close()
+
+	p := service.NewPool([]service.RPC{
+		service.NewRPC(&config.RPC{Host: svr.URL}, &http.Client{}),
+		service.NewRPC(&config.RPC{Host: lagging.URL}, &http.Client{}),
+	}, &config.Pool{MaxBlockLag: 1, HealthCheckInterval: 10 * time.Millisecond})
+
+	served := func() map[string]bool {
+		nodes := map[string]bool{}
+		for range 4 {
+			var resp string
+			require.NoError(t, p.Do(context.TODO(), "getbestblockhash", &resp))
+			nodes[resp] = true
+		}
+		return nodes
+	}
+	assert.Equal(t, map[string]bool{"node-0": true, "node-1": true}, served())
+
+	mu.Lock()
+	height = 110
+	mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(map[string]bool{"node-0": true}, served())
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestPool_Do_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var checks int
+	flapping := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{} = "node-0"
+		if req.Method == "getblockcount" {
+			// The initial check is slow, and the node lags on every other check after it.
+			mu.Lock()
+			checks++
+			n := checks
+			mu.Unlock()
+			if n == 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			result = uint32(100 - 10*(n%2))
+		}
+
+		bb, err := json.Marshal(models.Response{Result: result})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer flapping.Close()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{} = uint32(100)
+		if req.Method != "getblockcount" {
+			result = "node-1"
+		}
+
+		bb, err := json.Marshal(models.Response{Result: result})
+		assert.NoError(t, err)
+		_, _ = w.Write(bb)
+	}))
+	defer svr.Close()
+
+	p := service.NewPool([]service.RPC{
+		service.NewRPC(&config.RPC{Host: flapping.URL}, &http.Client{}),
+		service.NewRPC(&config.RPC{Host: svr.URL}, &http.Client{}),
+	}, &config.Pool{MaxBlockLag: 5, HealthCheckInterval: time.Millisecond})
+
+	// Every request arriving during the initial check waits on it, so none reach the node
+	// found to be lagging.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			var resp string
+			assert.NoError(t, p.Do(context.TODO(), "getbestblockhash", &resp))
+			assert.Equal(t, "node-1", resp)
+		})
+	}
+	wg.Wait()
+
+	// Wallet requests stay safe while the health of the sticky node changes under them.
+	for range 10 {
+		wg.Go(func() {
+			for range 50 {
+				var resp string
+				assert.NoError(t, p.Do(context.TODO(), "getbalance", &resp))
+			}
+		})
+	}
+	wg.Wait()
+}
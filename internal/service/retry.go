@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/internal/config"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// nonIdempotent methods which change state on the node, and so are unsafe to repeat
+// after a failure in which the node may have processed the request.
+var nonIdempotent = map[string]bool{
+	"addToConfiscationTxidWhitelist": true,
+	"addToConsensusBlacklist":        true,
+	"generate":                       true,
+	"generatetoaddress":              true,
+	"getnewaddress":                  true,
+	"getrawchangeaddress":            true,
+	"move":                           true,
+	"sendfrom":                       true,
+	"sendmany":                       true,
+	"sendrawtransaction":             true,
+	"sendrawtransactions":            true,
+	"sendtoaddress":                  true,
+	"stop":                           true,
+	"submitblock":                    true,
+	"submitminingsolution":           true,
+}
+
+type retry struct {
+	rpc RPC
+	cfg *config.Retry
+}
+
+// NewRetry returns a wrapper around an RPC service which retries failed requests
+// with exponential backoff and jitter.
+func NewRetry(rpc RPC, cfg *config.Retry) RPC {
+	return &retry{
+		rpc: rpc,
+		cfg: cfg,
+	}
+}
+
+// Do an RPC request, retrying on transient failure.
+func (r *retry) Do(ctx context.Context, method string, out interface{}, args ...interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := r.rpc.Do(ctx, method, out, args...)
+		if err == nil || attempt >= r.cfg.MaxAttempts || !r.allowed(method) || !Retryable(err) {
+			return err
+		}
+
+		if err = r.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// DoBatch performs a batch request, retrying the whole batch on transient transport failure
+// and individual elements which failed transiently.
+func (r *retry) DoBatch(ctx context.Context, calls ...*Call) error {
+	pending := calls
+	for attempt := 1; ; attempt++ {
+		err := DoBatch(ctx, r.rpc, pending...)
+		if err != nil {
+			if attempt >= r.cfg.MaxAttempts || !Retryable(err) {
+				return err
+			}
+			for _, call := range pending {
+				if !r.allowed(call.Method) {
+					return err
+				}
+			}
+		} else {
+			retries := make([]*Call, 0, len(pending))
+			for _, call := range pending {
+				if call.Err != nil && r.allowed(call.Method) && Retryable(call.Err) {
+					retries = append(retries, call)
+				}
+			}
+			if len(retries) == 0 || attempt >= r.cfg.MaxAttempts {
+				return nil
+			}
+			pending = retries
+		}
+
+		if err = r.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// DoStream performs a streaming request, retrying on transient failure to open the stream.
+// Failures once reading the result has begun are returned by the reader.
+func (r *retry) DoStream(ctx context.Context, method string, args ...interface{}) (io.ReadCloser, error) {
+	for attempt := 1; ; attempt++ {
+		rc, err := DoStream(ctx, r.rpc, method, args...)
+		if err == nil || attempt >= r.cfg.MaxAttempts || !r.allowed(method) || !Retryable(err) {
+			return rc, err
+		}
+
+		if err = r.wait(ctx, attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Retryable reports whether err is a transient failure worth retrying: the connection
+// being refused or reset, a 5xx reply, or the node still warming up.
+func Retryable(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var httpErr *models.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return errors.Is(err, models.ErrInWarmup)
+}
+
+// allowed reports whether the method may be retried.
+func (r *retry) allowed(method string) bool {
+	return r.cfg.NonIdempotent || !nonIdempotent[method]
+}
+
+// wait sleeps for the backoff delay of the given attempt, or until the context is done.
+func (r *retry) wait(ctx context.Context, attempt int) error {
+	delay := r.cfg.BaseDelay << (attempt - 1)
+	if delay <= 0 || (r.cfg.MaxDelay > 0 && delay > r.cfg.MaxDelay) {
+		delay = r.cfg.MaxDelay
+	}
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2) //nolint:gosec // G404: jitter does not need a secure source
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/internal/config"
+	"github.com/bsv-blockchain/go-bn/internal/service"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+func TestRetry_Do(t *testing.T) {
+	t.Parallel()
+
+	type reply struct {
+		status int
+		body   interface{}
+	}
+	workQueueFull := reply{status: http.StatusServiceUnavailable, body: "Work queue depth exceeded"}
+	warmingUp := reply{status: http.StatusInternalServerError, body: models.Response{
+		Error: &models.Error{Code: -28, Message: "Loading block index..."},
+	}}
+	rejected := reply{status: http.StatusInternalServerError, body: models.Response{
+		Error: &models.Error{Code: -26, Message: "16: mandatory-script-verify-flag-failed"},
+	}}
+	unauthorised := reply{status: http.StatusUnauthorized, body: ""}
+	success := reply{status: http.StatusOK, body: models.Response{Result: "ok"}}
+
+	tests := map[string]struct {
+		method   string
+		cfg      config.Retry
+		replies  []reply
+		expCalls int32
+		expErr   error
+	}{
+		"5xx is retried": {
+			method:   "getblockcount",
+			cfg:      config.Retry{MaxAttempts: 3},
+			replies:  []reply{workQueueFull, success},
+			expCalls: 2,
+		},
+		"warm up is retried": {
+			method:   "getblockcount",
+			cfg:      config.Retry{MaxAttempts: 3},
+			replies:  []reply{warmingUp, warmingUp, success},
+			expCalls: 3,
+		},
+		"attempts are bounded": {
+			method:   "getblockcount",
+			cfg:      config.Retry{MaxAttempts: 2},
+			replies:  []reply{warmingUp, warmingUp, success},
+			expCalls: 2,
+			expErr:   &models.Error{Code: -28, Message: "Loading block index..."},
+		},
+		"rpc errors are not retried": {
+			method:   "sendrawtransaction",
+			cfg:      config.Retry{MaxAttempts: 3, NonIdempotent: true},
+			replies:  []reply{rejected, success},
+			expCalls: 1,
+			expErr:   &models.Error{Code: -26, Message: "16: mandatory-script-verify-flag-failed"},
+		},
+		"4xx is not retried": {
+			method:   "getblockcount",
+			cfg:      config.Retry{MaxAttempts: 3},
+			replies:  []reply{unauthorised, success},
+			expCalls: 1,
+			expErr:   &models.HTTPError{StatusCode: http.StatusUnauthorized},
+		},
+		"non-idempotent methods are not retried": {
+			method:   "sendrawtransaction",
+			cfg:      config.Retry{MaxAttempts: 3},
+			replies:  []reply{workQueueFull, success},
+			expCalls: 1,
+			expErr:   &models.HTTPError{StatusCode: http.StatusServiceUnavailable, Body: "Work queue depth exceeded"},
+		},
+		"non-idempotent methods are retried when allowed": {
+			method:   "sendrawtransaction",
+			cfg:      config.Retry{MaxAttempts: 3, NonIdempotent: true},
+			replies:  []reply{workQueueFull, success},
+			expCalls: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var timesCalled int32
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				r := test.replies[atomic.AddInt32(&timesCalled, 1)-1]
+				w.WriteHeader(r.status)
+				if s, ok := r.body.(string); ok {
+					_, _ = w.Write([]byte(s))
+					return
+				}
+				bb, err := json.Marshal(r.body)
+				assert.NoError(t, err)
+				_, _ = w.Write(bb)
+			}))
+			defer svr.Close()
+
+			test.cfg.BaseDelay = time.Millisecond
+			test.cfg.MaxDelay = 5 * time.Millisecond
+			c := service.NewRetry(service.NewRPC(&config.RPC{
+				Host: svr.URL,
+			}, &http.Client{}), &test.cfg)
+
+			var resp string
+			err := c.Do(context.TODO(), test.method, &resp)
+			if test.expErr != nil {
+				require.Error(t, err)
+				require.EqualError(t, err, test.expErr.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ok", resp)
+			}
+			assert.Equal(t, test.expCalls, timesCalled)
+		})
+	}
+}
+
+func TestRetry_Do_ConnectionRefused(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.NotFoundHandler())
+	host := svr.URL
+	svr.Close()
+
+	c := service.NewRetry(service.NewRPC(&config.RPC{
+		Host: host,
+	}, &http.Client{}), &config.Retry{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond})
+
+	start := time.Now()
+	err := c.Do(context.TODO(), "getblockcount", nil)
+	require.Error(t, err)
+	assert.True(t, service.Retryable(err))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// streamBufferSize the size of the buffer streamed results are read through.
+const streamBufferSize = 64 << 10
+
+// streamResult reads the response body up to the start of the result, returning a reader
+// decoding the hex string result as the rest of the body arrives. Errors reported by the
+// node are returned as usual.
+func streamResult(body io.ReadCloser) (io.ReadCloser, error) {
+	dec := json.NewDecoder(body)
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, ErrResultNotHex
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok != "result" {
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			if tok == "error" {
+				if err = decode([]byte(`{"error":`+string(raw)+`}`), nil); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		r := bufio.NewReaderSize(io.MultiReader(dec.Buffered(), body), streamBufferSize)
+		b, err := skipSpace(r)
+		if err != nil {
+			return nil, err
+		}
+
+		switch b {
+		case '"':
+			return struct {
+				io.Reader
+				io.Closer
+			}{hex.NewDecoder(&quoted{r: r}), body}, nil
+		case 'n':
+			// A null result, so the error follows.
+			bb, err := io.ReadAll(io.MultiReader(strings.NewReader(`{"result":n`), r))
+			if err != nil {
+				return nil, err
+			}
+			if err = decode(bb, nil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrResultNotHex
+	}
+
+	return nil, ErrResultNotHex
+}
+
+// skipSpace skips whitespace and the colon following an object key, returning the first
+// byte of the value.
+func skipSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n', ':':
+			continue
+		}
+		return b, nil
+	}
+}
+
+// quoted reads the contents of a JSON string, up to its closing quote.
+type quoted struct {
+	r    *bufio.Reader
+	done bool
+}
+
+func (q *quoted) Read(p []byte) (int, error) {
+	if q.done {
+		return 0, io.EOF
+	}
+	if _, err := q.r.Peek(1); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+
+	buf, _ := q.r.Peek(min(len(p), q.r.Buffered()))
+	skip := 0
+	if i := bytes.IndexByte(buf, '"'); i >= 0 {
+		q.done = true
+		buf, skip = buf[:i], 1
+	}
+
+	n := copy(p, buf)
+	_, _ = q.r.Discard(n + skip)
+	if q.done {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+// Package txgraph orders transactions by the outputs they spend from one another.
+package txgraph
+
+import (
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+// Sort returns the transactions ordered so each follows those it spends from, which is the
+// order a node must accept them in. Transactions are otherwise kept in the order given.
+func Sort(txs []*bt.Tx) []*bt.Tx {
+	byID := make(map[string]*bt.Tx, len(txs))
+	for _, tx := range txs {
+		byID[tx.TxID()] = tx
+	}
+
+	sorted := make([]*bt.Tx, 0, len(txs))
+	visited := make(map[string]bool, len(txs))
+
+	var visit func(tx *bt.Tx)
+	visit = func(tx *bt.Tx) {
+		txID := tx.TxID()
+		if visited[txID] {
+			return
+		}
+		visited[txID] = true
+
+		for _, in := range tx.Inputs {
+			if parent, ok := byID[in.PreviousTxIDStr()]; ok {
+				visit(parent)
+			}
+		}
+		sorted = append(sorted, tx)
+	}
+	for _, tx := range txs {
+		visit(tx)
+	}
+
+	return sorted
+}
+
+// Ancestors returns the transactions of the set which those given spend from, directly or
+// through others of the set, in the order of the set. The given transactions are not
+// included unless they are ancestors of one another.
+func Ancestors(set []*bt.Tx, txs ...*bt.Tx) []*bt.Tx {
+	byID := make(map[string]*bt.Tx, len(set))
+	for _, tx := range set {
+		byID[tx.TxID()] = tx
+	}
+
+	found := make(map[string]bool)
+	queue := slices.Clone(txs)
+	for len(queue) > 0 {
+		tx := queue[0]
+		queue = queue[1:]
+
+		for _, in := range tx.Inputs {
+			parentID := in.PreviousTxIDStr()
+			if parent, ok := byID[parentID]; ok && !found[parentID] {
+				found[parentID] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	ancestors := make([]*bt.Tx, 0, len(found))
+	for _, tx := range set {
+		if found[tx.TxID()] {
+			ancestors = append(ancestors, tx)
+		}
+	}
+	return ancestors
+}
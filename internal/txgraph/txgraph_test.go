@@ -0,0 +1,149 @@
+package txgraph_test
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-bn/internal/txgraph"
+	"github.com/bsv-blockchain/go-bn/testing/util"
+)
+
+// txIDs returns the names of the transactions, as given by names.
+func txIDs(names map[string]string, txs []*bt.Tx) []string {
+	ids := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, names[tx.TxID()])
+	}
+	return ids
+}
+
+// diamond returns a diamond of transactions, b and c spending a, and d spending b and c,
+// alongside an unrelated x, named by txid.
+func diamond(t *testing.T) (map[string]*bt.Tx, map[string]string) {
+	t.Helper()
+
+	a := util.ChainedTx(t, 1000)
+	b := util.ChainedTx(t, 900, a)
+	c := util.ChainedTx(t, 800, a)
+	d := util.ChainedTx(t, 700, b, c)
+	x := util.ChainedTx(t, 500)
+
+	txs := map[string]*bt.Tx{"a": a, "b": b, "c": c, "d": d, "x": x}
+	names := make(map[string]string, len(txs))
+	for name, tx := range txs {
+		names[tx.TxID()] = name
+	}
+	return txs, names
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	txs, names := diamond(t)
+
+	tests := map[string]struct {
+		txs []string
+		exp []string
+	}{
+		"already sorted": {
+			txs: []string{"a", "b", "c", "d"},
+			exp: []string{"a", "b", "c", "d"},
+		},
+		"reversed": {
+			txs: []string{"d", "c", "b", "a"},
+			exp: []string{"a", "b", "c", "d"},
+		},
+		"siblings keep their order": {
+			txs: []string{"c", "d", "b", "a"},
+			exp: []string{"a", "c", "b", "d"},
+		},
+		"unrelated transactions keep their order": {
+			txs: []string{"x", "b", "a"},
+			exp: []string{"x", "a", "b"},
+		},
+		"parents outside the set are ignored": {
+			txs: []string{"d", "x", "b"},
+			exp: []string{"b", "d", "x"},
+		},
+		"duplicates are dropped": {
+			txs: []string{"b", "a", "b", "a"},
+			exp: []string{"a", "b"},
+		},
+		"empty": {
+			txs: nil,
+			exp: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			in := make([]*bt.Tx, 0, len(test.txs))
+			for _, n := range test.txs {
+				in = append(in, txs[n])
+			}
+			assert.Equal(t, test.exp, txIDs(names, txgraph.Sort(in)))
+		})
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	t.Parallel()
+
+	txs, names := diamond(t)
+
+	tests := map[string]struct {
+		set []string
+		txs []string
+		exp []string
+	}{
+		"diamond": {
+			set: []string{"x", "d", "c", "b", "a"},
+			txs: []string{"d"},
+			exp: []string{"c", "b", "a"},
+		},
+		"shared ancestor found once": {
+			set: []string{"a", "b", "c", "d"},
+			txs: []string{"b", "c"},
+			exp: []string{"a"},
+		},
+		"given transactions included when ancestors of one another": {
+			set: []string{"a", "b", "c", "d"},
+			txs: []string{"d", "b"},
+			exp: []string{"a", "b", "c"},
+		},
+		"followed through others of the set": {
+			set: []string{"a", "c", "d"},
+			txs: []string{"d"},
+			exp: []string{"a", "c"},
+		},
+		"not followed through transactions outside the set": {
+			set: []string{"a", "d"},
+			txs: []string{"d"},
+			exp: []string{},
+		},
+		"no ancestors": {
+			set: []string{"a", "b", "x"},
+			txs: []string{"a", "x"},
+			exp: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var set, in []*bt.Tx
+			for _, n := range test.set {
+				set = append(set, txs[n])
+			}
+			for _, n := range test.txs {
+				in = append(in, txs[n])
+			}
+			assert.Equal(t, test.exp, txIDs(names, txgraph.Ancestors(set, in...)))
+		})
+	}
+}
@@ -0,0 +1,391 @@
+// Package mempool mirrors the mempool of a bitcoin node, seeding from `getrawmempool` and
+// applying zmq notifications as they arrive.
+package mempool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// Mirror a local copy of the mempool of a node.
+type Mirror interface {
+	Sync(ctx context.Context) error
+	Run(ctx context.Context) error
+	Subscribe(z zmq.NodeMQ) error
+	OnHashTx(ctx context.Context, txID string)
+	OnRemoved(ctx context.Context, discard *zmq.MempoolDiscard)
+
+	Len() int
+	Entry(txID string) (*Entry, bool)
+	Entries() []*Entry
+	Ancestors(txID string) []*Entry
+	Descendants(txID string) []*Entry
+	ByFeeRate(minRate, maxRate float64) []*Entry
+	ByAge(minAge, maxAge time.Duration) []*Entry
+	BySize(minSize, maxSize uint32) []*Entry
+}
+
+type mirror struct {
+	client bn.BlockChainClient
+	cfg    *mirrorCfg
+
+	syncMu  sync.Mutex
+	mu      sync.RWMutex
+	synced  bool
+	syncing bool
+	journal []event
+
+	// Transactions removed while entries are being fetched, by the removal generation, so a
+	// fetch started before the removal does not add them back.
+	fetching   int
+	removals   uint64
+	tombstones map[string]uint64
+
+	entries  map[string]*Entry
+	children map[string]map[string]struct{}
+}
+
+// event a change applied while a sync is in flight, replayed over the snapshot it returns.
+type event struct {
+	entry   *Entry
+	removed string
+}
+
+// NewMirror returns a mirror of the mempool of the node behind client, configured via the
+// provided opt funcs. It is empty until seeded by Sync or Run.
+func NewMirror(client bn.BlockChainClient, oo ...MirrorOptFunc) Mirror {
+	cfg := &mirrorCfg{
+		interval: DefaultReconcileInterval,
+		errorFn:  func(context.Context, error) {},
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+
+	return &mirror{
+		client:     client,
+		cfg:        cfg,
+		tombstones: make(map[string]uint64),
+		entries:    make(map[string]*Entry),
+		children:   make(map[string]map[string]struct{}),
+	}
+}
+
+// Sync replaces the mirror with the result of `getrawmempool true`. Notifications applied
+// while the request is in flight are replayed over the result, so none are lost. After the
+// initial sync, any difference found is passed to the DriftFunc set WithDriftHandler.
+func (m *mirror) Sync(ctx context.Context) error {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	m.mu.Lock()
+	m.syncing = true
+	m.mu.Unlock()
+
+	txs, err := m.client.RawMempool(ctx)
+
+	m.mu.Lock()
+	journal := m.journal
+	m.syncing, m.journal = false, nil
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+
+	prev, initial := m.entries, !m.synced
+	m.entries = make(map[string]*Entry, len(txs))
+	m.children = make(map[string]map[string]struct{})
+	for txID, e := range txs {
+		m.add(&Entry{TxID: txID, MempoolEntry: e})
+	}
+	for _, ev := range journal {
+		m.apply(ev)
+	}
+	m.synced = true
+
+	var added, removed []string
+	for txID := range m.entries {
+		if _, ok := prev[txID]; !ok {
+			added = append(added, txID)
+		}
+	}
+	for txID := range prev {
+		if _, ok := m.entries[txID]; !ok {
+			removed = append(removed, txID)
+		}
+	}
+	m.mu.Unlock()
+
+	if !initial && m.cfg.driftFn != nil && (len(added) > 0 || len(removed) > 0) {
+		slices.Sort(added)
+		slices.Sort(removed)
+		m.cfg.driftFn(ctx, added, removed)
+	}
+
+	return nil
+}
+
+// Run seeds the mirror, then reconciles it with the node every reconcile interval until ctx
+// is done. An error is returned only if the initial sync fails, later failures being passed
+// to the error handler.
+func (m *mirror) Run(ctx context.Context) error {
+	m.mu.RLock()
+	synced := m.synced
+	m.mu.RUnlock()
+
+	if !synced {
+		if err := m.Sync(ctx); err != nil {
+			return err
+		}
+	}
+
+	t := time.NewTicker(m.cfg.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if err := m.Sync(ctx); err != nil && ctx.Err() == nil {
+			m.cfg.errorFn(ctx, err)
+		}
+	}
+}
+
+// Subscribe registers the handlers of the mirror for the `hashtx`, `removedfrommempoolblock`
+// and `discardfrommempool` topics. The topics are shared via zmq.Share, so may also be
+// subscribed to by others, such as a tracker.Tracker.
+func (m *mirror) Subscribe(z zmq.NodeMQ) error {
+	z = zmq.Share(z)
+	if err := z.SubscribeHashTx(m.OnHashTx); err != nil {
+		return err
+	}
+	if err := z.SubscribeRemovedFromMempoolBlock(m.OnRemoved); err != nil {
+		return err
+	}
+	return z.SubscribeDiscardFromMempool(m.OnRemoved)
+}
+
+// OnHashTx adds the transaction to the mirror, fetching its entry from the node. Transactions
+// which have already left the mempool, were announced as part of a block, or are removed
+// while their entry is fetched, are ignored.
+func (m *mirror) OnHashTx(ctx context.Context, txID string) {
+	m.mu.Lock()
+	m.fetching++
+	gen := m.removals
+	m.mu.Unlock()
+
+	e, err := m.client.MempoolEntry(ctx, txID)
+
+	m.mu.Lock()
+	removed := m.tombstones[txID] > gen
+	if m.fetching--; m.fetching == 0 {
+		clear(m.tombstones)
+	}
+	if err == nil && !removed {
+		m.record(event{entry: &Entry{TxID: txID, MempoolEntry: *e}})
+	}
+	m.mu.Unlock()
+
+	if err != nil && !errors.Is(err, models.ErrTxNotFound) {
+		m.cfg.errorFn(ctx, err)
+	}
+}
+
+// OnRemoved removes the transaction from the mirror.
+func (m *mirror) OnRemoved(_ context.Context, discard *zmq.MempoolDiscard) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fetching > 0 {
+		m.removals++
+		m.tombstones[discard.TxID] = m.removals
+	}
+	m.record(event{removed: discard.TxID})
+}
+
+// Len returns the number of transactions in the mirror.
+func (m *mirror) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.entries)
+}
+
+// Entry returns the entry of the transaction, false if it is not in the mirror.
+func (m *mirror) Entry(txID string) (*Entry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e, ok := m.entries[txID]
+	if !ok {
+		return nil, false
+	}
+	cp := *e
+	return &cp, true
+}
+
+// Entries returns every transaction in the mirror, in no particular order.
+func (m *mirror) Entries() []*Entry {
+	return m.filter(func(*Entry) bool { return true })
+}
+
+// Ancestors returns the in-mempool ancestors of the transaction, following Depends, nearest
+// first.
+func (m *mirror) Ancestors(txID string) []*Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.walk(txID, func(e *Entry) []string {
+		return e.Depends
+	})
+}
+
+// Descendants returns the in-mempool descendants of the transaction, nearest first.
+func (m *mirror) Descendants(txID string) []*Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.walk(txID, func(e *Entry) []string {
+		return slices.Sorted(maps.Keys(m.children[e.TxID]))
+	})
+}
+
+// ByFeeRate returns the transactions paying at least minRate and less than maxRate satoshis
+// per byte, highest first. A maxRate of zero is unbounded.
+func (m *mirror) ByFeeRate(minRate, maxRate float64) []*Entry {
+	ee := m.filter(func(e *Entry) bool {
+		rate := e.FeeRate()
+		return rate >= minRate && (maxRate <= 0 || rate < maxRate)
+	})
+	slices.SortStableFunc(ee, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(b.FeeRate(), a.FeeRate()), cmp.Compare(a.TxID, b.TxID))
+	})
+
+	return ee
+}
+
+// ByAge returns the transactions which have been in the mempool for at least minAge and less
+// than maxAge, oldest first. A maxAge of zero is unbounded.
+func (m *mirror) ByAge(minAge, maxAge time.Duration) []*Entry {
+	now := time.Now()
+	ee := m.filter(func(e *Entry) bool {
+		age := e.Age(now)
+		return age >= minAge && (maxAge <= 0 || age < maxAge)
+	})
+	slices.SortStableFunc(ee, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(a.Time, b.Time), cmp.Compare(a.TxID, b.TxID))
+	})
+
+	return ee
+}
+
+// BySize returns the transactions of at least minSize and less than maxSize bytes, largest
+// first. A maxSize of zero is unbounded.
+func (m *mirror) BySize(minSize, maxSize uint32) []*Entry {
+	ee := m.filter(func(e *Entry) bool {
+		return e.Size >= minSize && (maxSize == 0 || e.Size < maxSize)
+	})
+	slices.SortStableFunc(ee, func(a, b *Entry) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.TxID, b.TxID))
+	})
+
+	return ee
+}
+
+// record applies the event, journalling it when a sync is in flight.
+func (m *mirror) record(ev event) {
+	if m.syncing {
+		m.journal = append(m.journal, ev)
+	}
+	m.apply(ev)
+}
+
+func (m *mirror) apply(ev event) {
+	if ev.entry != nil {
+		m.add(ev.entry)
+		return
+	}
+	m.remove(ev.removed)
+}
+
+func (m *mirror) add(e *Entry) {
+	m.remove(e.TxID)
+
+	m.entries[e.TxID] = e
+	for _, parent := range e.Depends {
+		if m.children[parent] == nil {
+			m.children[parent] = make(map[string]struct{})
+		}
+		m.children[parent][e.TxID] = struct{}{}
+	}
+}
+
+func (m *mirror) remove(txID string) {
+	e, ok := m.entries[txID]
+	if !ok {
+		return
+	}
+
+	delete(m.entries, txID)
+	for _, parent := range e.Depends {
+		delete(m.children[parent], txID)
+		if len(m.children[parent]) == 0 {
+			delete(m.children, parent)
+		}
+	}
+}
+
+// walk returns copies of the entries reachable from txID via next, breadth first.
+func (m *mirror) walk(txID string, next func(e *Entry) []string) []*Entry {
+	start, ok := m.entries[txID]
+	if !ok {
+		return nil
+	}
+
+	var ee []*Entry
+	seen := map[string]bool{txID: true}
+	queue := []*Entry{start}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		for _, id := range next(e) {
+			n, ok := m.entries[id]
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			cp := *n
+			ee = append(ee, &cp)
+			queue = append(queue, n)
+		}
+	}
+
+	return ee
+}
+
+// filter returns copies of the entries matching fn.
+func (m *mirror) filter(fn func(e *Entry) bool) []*Entry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ee := make([]*Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		if fn(e) {
+			cp := *e
+			ee = append(ee, &cp)
+		}
+	}
+
+	return ee
+}
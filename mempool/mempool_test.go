@@ -0,0 +1,177 @@
+package mempool_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/mempool"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+func txIDs(ee []*mempool.Entry) []string {
+	ids := make([]string, len(ee))
+	for i, e := range ee {
+		ids[i] = e.TxID
+	}
+	return ids
+}
+
+func TestMirror_Sync(t *testing.T) {
+	t.Parallel()
+
+	now := uint32(time.Now().Unix())
+	txs := models.MempoolTxs{
+		"a": {Size: 100, Fee: 0.000001, Time: now - 600},
+		"b": {Size: 200, Fee: 0.00001, Time: now - 60, Depends: []string{"a"}},
+		"c": {Size: 50, Fee: 0.00000025, Time: now, Depends: []string{"b"}},
+		"d": {Size: 1000, Fee: 0.0001, Time: now - 3600},
+	}
+
+	m := mempool.NewMirror(&mocks.BlockChainClientMock{
+		RawMempoolFunc: func(context.Context) (models.MempoolTxs, error) {
+			return txs, nil
+		},
+	})
+	require.NoError(t, m.Sync(context.TODO()))
+
+	assert.Equal(t, 4, m.Len())
+
+	e, ok := m.Entry("b")
+	require.True(t, ok)
+	assert.InDelta(t, 5.0, e.FeeRate(), 0.0001)
+
+	_, ok = m.Entry("z")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"b", "a"}, txIDs(m.Ancestors("c")))
+	assert.Equal(t, []string{"b", "c"}, txIDs(m.Descendants("a")))
+	assert.Empty(t, m.Ancestors("d"))
+	assert.Empty(t, m.Descendants("z"))
+
+	assert.Equal(t, []string{"d", "b", "a", "c"}, txIDs(m.ByFeeRate(0, 0)))
+	assert.Equal(t, []string{"b", "a"}, txIDs(m.ByFeeRate(1, 5.5)))
+	assert.Equal(t, []string{"d", "a", "b"}, txIDs(m.ByAge(time.Minute, 0)))
+	assert.Equal(t, []string{"a", "b"}, txIDs(m.ByAge(time.Minute, time.Hour)))
+	assert.Equal(t, []string{"b", "a"}, txIDs(m.BySize(100, 1000)))
+}
+
+func TestMirror_Notifications(t *testing.T) {
+	t.Parallel()
+
+	m := mempool.NewMirror(&mocks.BlockChainClientMock{
+		RawMempoolFunc: func(context.Context) (models.MempoolTxs, error) {
+			return models.MempoolTxs{"a": {Size: 100}}, nil
+		},
+		MempoolEntryFunc: func(_ context.Context, txID string) (*models.MempoolEntry, error) {
+			if txID == "mined" {
+				return nil, &models.Error{Code: -5, Message: "Transaction not in mempool"}
+			}
+			return &models.MempoolEntry{Size: 200, Depends: []string{"a"}}, nil
+		},
+	})
+	require.NoError(t, m.Sync(context.TODO()))
+
+	m.OnHashTx(context.TODO(), "b")
+	m.OnHashTx(context.TODO(), "mined")
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, []string{"b"}, txIDs(m.Descendants("a")))
+
+	m.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "a"})
+	assert.Equal(t, 1, m.Len())
+	assert.Empty(t, m.Ancestors("b"))
+}
+
+func TestMirror_Notifications_RemovedWhileFetching(t *testing.T) {
+	t.Parallel()
+
+	fetching := make(chan string)
+	release := make(chan struct{})
+	m := mempool.NewMirror(&mocks.BlockChainClientMock{
+		RawMempoolFunc: func(context.Context) (models.MempoolTxs, error) {
+			return models.MempoolTxs{}, nil
+		},
+		MempoolEntryFunc: func(_ context.Context, txID string) (*models.MempoolEntry, error) {
+			fetching <- txID
+			<-release
+			return &models.MempoolEntry{Size: 200}, nil
+		},
+	})
+	require.NoError(t, m.Sync(context.TODO()))
+
+	// The transaction is mined while its entry is in flight.
+	var wg sync.WaitGroup
+	wg.Go(func() { m.OnHashTx(context.TODO(), "a") })
+	assert.Equal(t, "a", <-fetching)
+	m.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "a"})
+	close(release)
+	wg.Wait()
+
+	_, ok := m.Entry("a")
+	assert.False(t, ok)
+
+	// Once announced again, it is added.
+	wg.Go(func() { m.OnHashTx(context.TODO(), "a") })
+	assert.Equal(t, "a", <-fetching)
+	wg.Wait()
+
+	_, ok = m.Entry("a")
+	assert.True(t, ok)
+}
+
+func TestMirror_Sync_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls atomic.Int32
+	txs := models.MempoolTxs{"a": {}, "b": {}}
+	syncing := make(chan struct{})
+	release := make(chan struct{})
+
+	var added, removed []string
+	m := mempool.NewMirror(&mocks.BlockChainClientMock{
+		RawMempoolFunc: func(context.Context) (models.MempoolTxs, error) {
+			if calls.Add(1) == 1 {
+				close(syncing)
+				<-release
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return txs, nil
+		},
+		MempoolEntryFunc: func(context.Context, string) (*models.MempoolEntry, error) {
+			return &models.MempoolEntry{}, nil
+		},
+	}, mempool.WithDriftHandler(func(_ context.Context, a, r []string) {
+		added, removed = a, r
+	}))
+
+	// Notifications arriving while the seed is in flight are kept.
+	done := make(chan error)
+	go func() { done <- m.Sync(context.TODO()) }()
+	<-syncing
+	m.OnHashTx(context.TODO(), "c")
+	m.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "a"})
+	close(release)
+	require.NoError(t, <-done)
+
+	assert.ElementsMatch(t, []string{"b", "c"}, txIDs(m.Entries()))
+	assert.Nil(t, added)
+
+	// A later sync corrects drift.
+	mu.Lock()
+	txs = models.MempoolTxs{"b": {}, "d": {}}
+	mu.Unlock()
+	require.NoError(t, m.Sync(context.TODO()))
+
+	assert.ElementsMatch(t, []string{"b", "d"}, txIDs(m.Entries()))
+	assert.Equal(t, []string{"d"}, added)
+	assert.Equal(t, []string{"c"}, removed)
+}
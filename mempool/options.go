@@ -0,0 +1,39 @@
+package mempool
+
+import "time"
+
+// DefaultReconcileInterval the default interval between reconciliations of the mirror with
+// the mempool of the node.
+const DefaultReconcileInterval = time.Minute
+
+type mirrorCfg struct {
+	interval time.Duration
+	errorFn  ErrorFunc
+	driftFn  DriftFunc
+}
+
+// MirrorOptFunc option func.
+type MirrorOptFunc func(c *mirrorCfg)
+
+// WithReconcileInterval set the interval at which Run reconciles the mirror with a full
+// `getrawmempool`. Defaults to DefaultReconcileInterval.
+func WithReconcileInterval(d time.Duration) MirrorOptFunc {
+	return func(c *mirrorCfg) {
+		c.interval = d
+	}
+}
+
+// WithErrorHandler set a handler for errors raised applying notifications and reconciling
+// in the background. They are discarded by default.
+func WithErrorHandler(fn ErrorFunc) MirrorOptFunc {
+	return func(c *mirrorCfg) {
+		c.errorFn = fn
+	}
+}
+
+// WithDriftHandler set a handler called when a reconciliation corrects the mirror.
+func WithDriftHandler(fn DriftFunc) MirrorOptFunc {
+	return func(c *mirrorCfg) {
+		c.driftFn = fn
+	}
+}
@@ -0,0 +1,39 @@
+package mempool
+
+import (
+	"context"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// ErrorFunc a func in which errors keeping the mirror in sync are passed to.
+type ErrorFunc func(ctx context.Context, err error)
+
+// DriftFunc a func called when a reconciliation finds the mirror had drifted from the
+// mempool of the node, with the IDs of the transactions added and removed to correct it.
+type DriftFunc func(ctx context.Context, added, removed []string)
+
+// Entry a transaction in the mempool.
+type Entry struct {
+	TxID string
+	models.MempoolEntry
+}
+
+// FeeRate returns the fee rate of the transaction in satoshis per byte.
+func (e *Entry) FeeRate() float64 {
+	if e.Size == 0 {
+		return 0
+	}
+	return e.Fee * 1e8 / float64(e.Size)
+}
+
+// Entered returns the time the transaction entered the mempool.
+func (e *Entry) Entered() time.Time {
+	return time.Unix(int64(e.Time), 0)
+}
+
+// Age returns the time the transaction has spent in the mempool as of now.
+func (e *Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.Entered())
+}
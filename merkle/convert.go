@@ -0,0 +1,103 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-bc"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// bumpLeaf mirrors the JSON of a leaf of a bc.BUMP, which cannot be built directly.
+type bumpLeaf struct {
+	Offset    uint64 `json:"offset"`
+	Hash      string `json:"hash,omitempty"`
+	TxID      bool   `json:"txid,omitempty"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+// ToTSC converts a proof returned by `getmerkleproof` to the TSC format returned by
+// `getmerkleproof2`, targeting the header of its block.
+func ToTSC(legacy *models.LegacyMerkleProof) (*bc.MerkleProof, error) {
+	if legacy.Target.BlockHeader == nil {
+		return nil, fmt.Errorf("%w: missing block header", ErrInvalidTarget)
+	}
+
+	return &bc.MerkleProof{
+		Index:      uint64(legacy.Index),
+		TxOrID:     legacy.TxOrID,
+		Target:     hex.EncodeToString(legacy.Target.Bytes()),
+		Nodes:      legacy.Nodes,
+		TargetType: TargetTypeHeader,
+	}, nil
+}
+
+// ToBUMP converts a TSC proof of a transaction in the block at the given height to a BRC-74
+// BUMP.
+func ToBUMP(proof *bc.MerkleProof, height uint64) (*bc.BUMP, error) {
+	if proof.Composite || (proof.ProofType != "" && proof.ProofType != "branch") {
+		return nil, ErrUnsupported
+	}
+	if proof.Index>>len(proof.Nodes) != 0 {
+		return nil, fmt.Errorf("%w: index %d with %d nodes", ErrIndexOutOfRange, proof.Index, len(proof.Nodes))
+	}
+
+	txID, err := TxID(proof.TxOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := bumpLeaf{Offset: proof.Index, Hash: txID, TxID: true}
+	if len(proof.Nodes) == 0 {
+		return newBUMP(height, [][]bumpLeaf{{tx}})
+	}
+
+	path := make([][]bumpLeaf, len(proof.Nodes))
+	for h, n := range proof.Nodes {
+		l := bumpLeaf{Offset: (proof.Index >> h) ^ 1}
+		switch {
+		case n == "*":
+			if l.Offset%2 == 0 {
+				return nil, fmt.Errorf("%w: duplicate node on the left", ErrInvalidNodes)
+			}
+			l.Duplicate = true
+		case len(n) != 64:
+			return nil, fmt.Errorf("%w: node of %d characters", ErrInvalidNodes, len(n))
+		default:
+			l.Hash = n
+		}
+		path[h] = []bumpLeaf{l}
+	}
+
+	if tx.Offset < path[0][0].Offset {
+		path[0] = []bumpLeaf{tx, path[0][0]}
+	} else {
+		path[0] = []bumpLeaf{path[0][0], tx}
+	}
+
+	return newBUMP(height, path)
+}
+
+// LegacyToBUMP converts a proof returned by `getmerkleproof` to a BRC-74 BUMP.
+func LegacyToBUMP(legacy *models.LegacyMerkleProof) (*bc.BUMP, error) {
+	p, err := ToTSC(legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToBUMP(p, legacy.Target.Height)
+}
+
+func newBUMP(height uint64, path [][]bumpLeaf) (*bc.BUMP, error) {
+	bb, err := json.Marshal(struct {
+		BlockHeight uint64       `json:"blockHeight"`
+		Path        [][]bumpLeaf `json:"path"`
+	}{height, path})
+	if err != nil {
+		return nil, err
+	}
+
+	return bc.NewBUMPFromJSON(string(bb))
+}
@@ -0,0 +1,16 @@
+package merkle
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrRootMismatch      = errors.New("merkle root does not match block header")
+	ErrTxMismatch        = errors.New("proof is for another transaction")
+	ErrTargetMismatch    = errors.New("proof target does not match block")
+	ErrInvalidTarget     = errors.New("invalid proof target")
+	ErrInvalidTargetType = errors.New("invalid proof target type")
+	ErrInvalidNodes      = errors.New("invalid proof nodes")
+	ErrIndexOutOfRange   = errors.New("index out of range for proof")
+	ErrUnsupported       = errors.New("only single merkle branch proofs are supported")
+	ErrBlockRequired     = errors.New("block hash required to verify merkle root target")
+)
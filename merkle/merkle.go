@@ -0,0 +1,197 @@
+// Package merkle verifies merkle proofs returned by a bitcoin node against its block headers,
+// and converts them to the TSC and BRC-74 BUMP formats used in SPV envelopes.
+package merkle
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Proof target types, as set in bc.MerkleProof.TargetType. The node names the merkle root
+// target type `merkleroot`, while the TSC specification names it `merkleRoot`, so both are
+// accepted.
+const (
+	TargetTypeHash          = string(models.MerkleProofTargetTypeHash)
+	TargetTypeHeader        = string(models.MerkleProofTargetTypeHeader)
+	TargetTypeMerkleRoot    = string(models.MerkleProofTargetTypeMerkleRoot)
+	TargetTypeMerkleRootTSC = "merkleRoot"
+)
+
+// Verifier checks merkle proofs against the block headers of a node.
+type Verifier interface {
+	Verify(ctx context.Context, txOrID string, proof *bc.MerkleProof) (*models.BlockHeader, error)
+	VerifyInBlock(ctx context.Context, txOrID string, proof *bc.MerkleProof, blockHash string) (*models.BlockHeader, error)
+	VerifyLegacy(ctx context.Context, txOrID string, proof *models.LegacyMerkleProof) (*models.BlockHeader, error)
+}
+
+type verifier struct {
+	client bn.BlockChainClient
+}
+
+// NewVerifier returns a Verifier fetching block headers from the node behind client.
+func NewVerifier(client bn.BlockChainClient) Verifier {
+	return &verifier{client: client}
+}
+
+// Verify checks the proof proves txOrID, a transaction or its ID, is included in the block
+// the proof targets, returning the header of that block as known to the node. Proofs
+// targeting a merkle root do not identify a block, so must be checked with VerifyInBlock.
+func (v *verifier) Verify(ctx context.Context, txOrID string, proof *bc.MerkleProof) (*models.BlockHeader, error) {
+	hash, err := TargetHash(proof)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, ErrBlockRequired
+	}
+
+	return v.VerifyInBlock(ctx, txOrID, proof, hash)
+}
+
+// VerifyInBlock checks the proof proves txOrID, a transaction or its ID, is included in the
+// block with the given hash, returning its header as known to the node. The target of the
+// proof, whatever its type, must agree with the block.
+func (v *verifier) VerifyInBlock(ctx context.Context, txOrID string, proof *bc.MerkleProof,
+	blockHash string,
+) (*models.BlockHeader, error) {
+	txID, err := TxID(txOrID)
+	if err != nil {
+		return nil, err
+	}
+	proofTxID, err := TxID(proof.TxOrID)
+	if err != nil {
+		return nil, err
+	}
+	if proofTxID != txID {
+		return nil, fmt.Errorf("%w: expected %s, proof is for %s", ErrTxMismatch, txID, proofTxID)
+	}
+
+	// Validates the target type, the target naming a block unless it is a merkle root.
+	target, err := TargetHash(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := Root(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr, err := v.client.BlockHeader(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case target != "" && target != hdr.Hash:
+		return nil, fmt.Errorf("%w: proof targets %s, block is %s", ErrTargetMismatch, target, hdr.Hash)
+	case target == "" && proof.Target != hdr.HashMerkleRootStr():
+		return nil, fmt.Errorf("%w: proof targets root %s, block %s has root %s",
+			ErrTargetMismatch, proof.Target, hdr.Hash, hdr.HashMerkleRootStr())
+	}
+
+	if root != hdr.HashMerkleRootStr() {
+		return nil, fmt.Errorf("%w: computed %s, block %s has %s", ErrRootMismatch, root, hdr.Hash,
+			hdr.HashMerkleRootStr())
+	}
+
+	return hdr, nil
+}
+
+// VerifyLegacy checks a proof returned by `getmerkleproof`, converting it with ToTSC.
+func (v *verifier) VerifyLegacy(ctx context.Context, txOrID string,
+	proof *models.LegacyMerkleProof,
+) (*models.BlockHeader, error) {
+	p, err := ToTSC(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Verify(ctx, txOrID, p)
+}
+
+// Root recomputes the merkle root from the transaction, index and nodes of the proof.
+func Root(proof *bc.MerkleProof) (string, error) {
+	if proof.Composite || (proof.ProofType != "" && proof.ProofType != "branch") {
+		return "", ErrUnsupported
+	}
+
+	c, err := TxID(proof.TxOrID)
+	if err != nil {
+		return "", err
+	}
+
+	index := proof.Index
+	for _, n := range proof.Nodes {
+		left := index%2 == 0
+
+		// A duplicate of the node itself, when it is last in an odd length level of the tree.
+		if n == "*" {
+			if !left {
+				return "", fmt.Errorf("%w: duplicate node on the left", ErrInvalidNodes)
+			}
+			n = c
+		}
+
+		if left {
+			c, err = bc.MerkleTreeParentStr(c, n)
+		} else {
+			c, err = bc.MerkleTreeParentStr(n, c)
+		}
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidNodes, err)
+		}
+		index /= 2
+	}
+	if index != 0 {
+		return "", fmt.Errorf("%w: index %d with %d nodes", ErrIndexOutOfRange, proof.Index, len(proof.Nodes))
+	}
+
+	return c, nil
+}
+
+// TargetHash returns the hash of the block targeted by the proof, or an empty string when
+// the proof targets a merkle root.
+func TargetHash(proof *bc.MerkleProof) (string, error) {
+	switch proof.TargetType {
+	case "", TargetTypeHash:
+		if len(proof.Target) != 64 {
+			return "", fmt.Errorf("%w: block hash of %d characters", ErrInvalidTarget, len(proof.Target))
+		}
+		return proof.Target, nil
+	case TargetTypeHeader:
+		bb, err := hex.DecodeString(proof.Target)
+		if err != nil || len(bb) != 80 {
+			return "", fmt.Errorf("%w: block header is not 80 bytes of hex", ErrInvalidTarget)
+		}
+		return chainhash.DoubleHashH(bb).String(), nil
+	case TargetTypeMerkleRoot, TargetTypeMerkleRootTSC:
+		if len(proof.Target) != 64 {
+			return "", fmt.Errorf("%w: merkle root of %d characters", ErrInvalidTarget, len(proof.Target))
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidTargetType, proof.TargetType)
+}
+
+// TxID returns the ID of txOrID, which is either a transaction ID or a raw transaction.
+func TxID(txOrID string) (string, error) {
+	if len(txOrID) == 64 {
+		return txOrID, nil
+	}
+
+	tx, err := bt.NewTxFromString(txOrID)
+	if err != nil {
+		return "", err
+	}
+	return tx.TxID(), nil
+}
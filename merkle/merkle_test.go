@@ -0,0 +1,462 @@
+package merkle_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/merkle"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// tree a block of five transactions, with the headers and branches needed to prove them.
+type tree struct {
+	txIDs  []string
+	root   string
+	header *models.BlockHeader
+	hex    string
+	nodes  map[uint64][]string
+}
+
+func parent(t *testing.T, l, r string) string {
+	t.Helper()
+
+	p, err := bc.MerkleTreeParentStr(l, r)
+	require.NoError(t, err)
+	return p
+}
+
+func newTree(t *testing.T) *tree {
+	t.Helper()
+
+	txIDs := make([]string, 5)
+	for i := range txIDs {
+		txIDs[i] = strings.Repeat(string(rune('a'+i)), 64)
+	}
+
+	p01 := parent(t, txIDs[0], txIDs[1])
+	p23 := parent(t, txIDs[2], txIDs[3])
+	p44 := parent(t, txIDs[4], txIDs[4])
+	q0 := parent(t, p01, p23)
+	q1 := parent(t, p44, p44)
+	root := parent(t, q0, q1)
+
+	merkleRoot, err := hex.DecodeString(root)
+	require.NoError(t, err)
+	bh := &bc.BlockHeader{
+		Version:        1,
+		Time:           1231006505,
+		Nonce:          2083236893,
+		HashPrevBlock:  make([]byte, 32),
+		HashMerkleRoot: merkleRoot,
+		Bits:           []byte{0x1d, 0x00, 0xff, 0xff},
+	}
+
+	return &tree{
+		txIDs: txIDs,
+		root:  root,
+		header: &models.BlockHeader{
+			BlockHeader: bh,
+			Hash:        chainhash.DoubleHashH(bh.Bytes()).String(),
+			Height:      100,
+		},
+		hex: hex.EncodeToString(bh.Bytes()),
+		nodes: map[uint64][]string{
+			1: {txIDs[0], p23, q1},
+			2: {txIDs[3], p01, q1},
+			4: {"*", "*", q0},
+		},
+	}
+}
+
+func (tr *tree) client(t *testing.T) *mocks.BlockChainClientMock {
+	t.Helper()
+
+	return &mocks.BlockChainClientMock{
+		BlockHeaderFunc: func(ctx context.Context, hash string) (*models.BlockHeader, error) {
+			if hash != tr.header.Hash {
+				return nil, models.ErrBlockNotFound
+			}
+			return tr.header, nil
+		},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	tr := newTree(t)
+
+	tests := map[string]struct {
+		txOrID     string
+		index      uint64
+		target     string
+		targetType string
+		nodes      []string
+		blockHash  string
+		err        error
+	}{
+		"hash target": {
+			index:      2,
+			target:     tr.header.Hash,
+			targetType: merkle.TargetTypeHash,
+		},
+		"default target": {
+			index:  1,
+			target: tr.header.Hash,
+		},
+		"header target with duplicate nodes": {
+			index:      4,
+			target:     tr.hex,
+			targetType: merkle.TargetTypeHeader,
+		},
+		"merkle root target": {
+			index:      2,
+			target:     tr.root,
+			targetType: merkle.TargetTypeMerkleRoot,
+			blockHash:  tr.header.Hash,
+		},
+		"tsc merkle root target": {
+			index:      4,
+			target:     tr.root,
+			targetType: merkle.TargetTypeMerkleRootTSC,
+			blockHash:  tr.header.Hash,
+		},
+		"merkle root target without block": {
+			index:      2,
+			target:     tr.root,
+			targetType: merkle.TargetTypeMerkleRoot,
+			err:        merkle.ErrBlockRequired,
+		},
+		"wrong merkle root target": {
+			index:      2,
+			target:     tr.txIDs[0],
+			targetType: merkle.TargetTypeMerkleRoot,
+			blockHash:  tr.header.Hash,
+			err:        merkle.ErrTargetMismatch,
+		},
+		"wrong nodes": {
+			index:      3,
+			target:     tr.header.Hash,
+			targetType: merkle.TargetTypeHash,
+			nodes:      tr.nodes[2],
+			err:        merkle.ErrRootMismatch,
+		},
+		"wrong tx": {
+			txOrID: tr.txIDs[0],
+			index:  2,
+			target: tr.header.Hash,
+			err:    merkle.ErrTxMismatch,
+		},
+		"unknown block": {
+			index:  2,
+			target: tr.root,
+			err:    models.ErrBlockNotFound,
+		},
+		"unknown target type": {
+			index:      2,
+			target:     tr.header.Hash,
+			targetType: "height",
+			err:        merkle.ErrInvalidTargetType,
+		},
+		"unknown target type in block": {
+			index:      2,
+			target:     tr.root,
+			targetType: "height",
+			blockHash:  tr.header.Hash,
+			err:        merkle.ErrInvalidTargetType,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			v := merkle.NewVerifier(tr.client(t))
+			proof := &bc.MerkleProof{
+				Index:      test.index,
+				TxOrID:     tr.txIDs[test.index],
+				Target:     test.target,
+				TargetType: test.targetType,
+				Nodes:      tr.nodes[test.index],
+			}
+			if test.nodes != nil {
+				proof.Nodes = test.nodes
+			}
+			txOrID := test.txOrID
+			if txOrID == "" {
+				txOrID = proof.TxOrID
+			}
+
+			var hdr *models.BlockHeader
+			var err error
+			if test.blockHash != "" {
+				hdr, err = v.VerifyInBlock(context.TODO(), txOrID, proof, test.blockHash)
+			} else {
+				hdr, err = v.Verify(context.TODO(), txOrID, proof)
+			}
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tr.header.Hash, hdr.Hash)
+		})
+	}
+}
+
+func TestRoot_InvalidNodes(t *testing.T) {
+	t.Parallel()
+
+	tr := newTree(t)
+
+	_, err := merkle.Root(&bc.MerkleProof{Index: 1, TxOrID: tr.txIDs[1], Nodes: []string{"*"}})
+	require.ErrorIs(t, err, merkle.ErrInvalidNodes)
+
+	_, err = merkle.Root(&bc.MerkleProof{Index: 8, TxOrID: tr.txIDs[4], Nodes: tr.nodes[4]})
+	require.ErrorIs(t, err, merkle.ErrIndexOutOfRange)
+
+	_, err = merkle.Root(&bc.MerkleProof{TxOrID: tr.txIDs[0], Composite: true})
+	require.ErrorIs(t, err, merkle.ErrUnsupported)
+}
+
+func TestVerifier_VerifyLegacy(t *testing.T) {
+	t.Parallel()
+
+	tr := newTree(t)
+
+	bb, err := json.Marshal(map[string]interface{}{
+		"flags":  2,
+		"index":  4,
+		"txOrId": tr.txIDs[4],
+		"target": map[string]interface{}{
+			"hash":              tr.header.Hash,
+			"height":            tr.header.Height,
+			"version":           tr.header.Version,
+			"merkleroot":        tr.root,
+			"previousblockhash": strings.Repeat("0", 64),
+			"time":              tr.header.Time,
+			"nonce":             tr.header.Nonce,
+			"bits":              "1d00ffff",
+		},
+		"nodes": tr.nodes[4],
+	})
+	require.NoError(t, err)
+
+	var legacy models.LegacyMerkleProof
+	require.NoError(t, json.Unmarshal(bb, &legacy))
+
+	proof, err := merkle.ToTSC(&legacy)
+	require.NoError(t, err)
+	assert.Equal(t, tr.hex, proof.Target)
+	assert.Equal(t, merkle.TargetTypeHeader, proof.TargetType)
+
+	hdr, err := merkle.NewVerifier(tr.client(t)).VerifyLegacy(context.TODO(), tr.txIDs[4], &legacy)
+	require.NoError(t, err)
+	assert.Equal(t, tr.header.Hash, hdr.Hash)
+
+	bump, err := merkle.LegacyToBUMP(&legacy)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), bump.BlockHeight)
+	root, err := bump.CalculateRootGivenTxid(tr.txIDs[4])
+	require.NoError(t, err)
+	assert.Equal(t, tr.root, root)
+}
+
+func TestToBUMP(t *testing.T) {
+	t.Parallel()
+
+	tr := newTree(t)
+
+	for _, index := range []uint64{1, 2, 4} {
+		bump, err := merkle.ToBUMP(&bc.MerkleProof{
+			Index:  index,
+			TxOrID: tr.txIDs[index],
+			Nodes:  tr.nodes[index],
+		}, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{tr.txIDs[index]}, bump.Txids())
+
+		root, err := bump.CalculateRootGivenTxid(tr.txIDs[index])
+		require.NoError(t, err)
+		assert.Equal(t, tr.root, root, "index %d", index)
+
+		// The BRC-74 encoding round trips.
+		s, err := bump.String()
+		require.NoError(t, err)
+		decoded, err := bc.NewBUMPFromStr(s)
+		require.NoError(t, err)
+		root, err = decoded.CalculateRootGivenTxid(tr.txIDs[index])
+		require.NoError(t, err)
+		assert.Equal(t, tr.root, root, "index %d", index)
+	}
+
+	bump, err := merkle.ToBUMP(&bc.MerkleProof{TxOrID: tr.txIDs[0]}, 1)
+	require.NoError(t, err)
+	root, err := bump.CalculateRootGivenTxid(tr.txIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, tr.txIDs[0], root)
+
+	_, err = merkle.ToBUMP(&bc.MerkleProof{Index: 1, TxOrID: tr.txIDs[1], Nodes: []string{"*"}}, 1)
+	require.ErrorIs(t, err, merkle.ErrInvalidNodes)
+}
+
+// Mainnet block 100000, its header as returned by `getblockheader`, and the branches proving
+// its second and third transactions. Unlike newTree, none of it is computed by the code under
+// test.
+const (
+	mainnetHash   = "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506"
+	mainnetRoot   = "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766"
+	mainnetHeight = 100000
+	mainnetHex    = "0100000050120119172a610421a6c3011dd330d9df07b63616c2cc1f1cd00200000000006657a9252aac" +
+		"d5c0b2940996ecff952228c3067cc38d4885efb5a4ac4247e9f337221b4d4c86041b0f2b5710"
+	mainnetHeader = `{
+		"hash": "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506",
+		"height": 100000,
+		"version": 1,
+		"versionHex": "00000001",
+		"merkleroot": "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+		"num_tx": 4,
+		"time": 1293623863,
+		"nonce": 274148111,
+		"bits": "1b04864c",
+		"previousblockhash": "000000000002d01c1fccc21636b607dfd930d31d01c3a62104612a1719011250"
+	}`
+)
+
+var mainnetTxIDs = []string{
+	"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+	"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+	"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+	"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+}
+
+var mainnetNodes = map[uint64][]string{
+	1: {mainnetTxIDs[0], "8e30899078ca1813be036a073bbf80b86cdddde1c96e9e9c99e9e3782df4ae49"},
+	2: {mainnetTxIDs[3], "ccdafb73d8dcd0173d5d5c3c9a0770d0b3953db889dab99ef05b1907518cb815"},
+}
+
+func mainnetClient(t *testing.T) *mocks.BlockChainClientMock {
+	t.Helper()
+
+	var hdr models.BlockHeader
+	require.NoError(t, json.Unmarshal([]byte(mainnetHeader), &hdr))
+	return &mocks.BlockChainClientMock{
+		BlockHeaderFunc: func(ctx context.Context, hash string) (*models.BlockHeader, error) {
+			if hash != mainnetHash {
+				return nil, models.ErrBlockNotFound
+			}
+			return &hdr, nil
+		},
+	}
+}
+
+func TestVerifier_Verify_Mainnet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		index      uint64
+		target     string
+		targetType string
+		inBlock    bool
+	}{
+		"hash target": {
+			index:  1,
+			target: mainnetHash,
+		},
+		"header target": {
+			index:      2,
+			target:     mainnetHex,
+			targetType: merkle.TargetTypeHeader,
+		},
+		"merkle root target": {
+			index:      1,
+			target:     mainnetRoot,
+			targetType: merkle.TargetTypeMerkleRoot,
+			inBlock:    true,
+		},
+		"tsc merkle root target": {
+			index:      2,
+			target:     mainnetRoot,
+			targetType: merkle.TargetTypeMerkleRootTSC,
+			inBlock:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Decoded from a reply in the shape of `getmerkleproof2`.
+			bb, err := json.Marshal(map[string]interface{}{
+				"index":      test.index,
+				"txOrId":     mainnetTxIDs[test.index],
+				"targetType": test.targetType,
+				"target":     test.target,
+				"nodes":      mainnetNodes[test.index],
+			})
+			require.NoError(t, err)
+			var proof bc.MerkleProof
+			require.NoError(t, json.Unmarshal(bb, &proof))
+
+			root, err := merkle.Root(&proof)
+			require.NoError(t, err)
+			assert.Equal(t, mainnetRoot, root)
+
+			v := merkle.NewVerifier(mainnetClient(t))
+			verify := func(txID string) (*models.BlockHeader, error) {
+				if test.inBlock {
+					return v.VerifyInBlock(context.TODO(), txID, &proof, mainnetHash)
+				}
+				return v.Verify(context.TODO(), txID, &proof)
+			}
+
+			hdr, err := verify(proof.TxOrID)
+			require.NoError(t, err)
+			assert.Equal(t, mainnetHash, hdr.Hash)
+			assert.Equal(t, uint64(mainnetHeight), hdr.Height)
+
+			// The sibling of the transaction proves nothing with the same branch.
+			_, err = verify(mainnetTxIDs[test.index^1])
+			require.ErrorIs(t, err, merkle.ErrTxMismatch)
+		})
+	}
+}
+
+func TestVerifier_VerifyLegacy_Mainnet(t *testing.T) {
+	t.Parallel()
+
+	// In the shape of a `getmerkleproof` reply, the target being the full block header.
+	bb := []byte(`{"flags":2,"index":2,"txOrId":"` + mainnetTxIDs[2] + `","target":` + mainnetHeader +
+		`,"nodes":["` + strings.Join(mainnetNodes[2], `","`) + `"]}`)
+	var legacy models.LegacyMerkleProof
+	require.NoError(t, json.Unmarshal(bb, &legacy))
+
+	proof, err := merkle.ToTSC(&legacy)
+	require.NoError(t, err)
+	assert.Equal(t, mainnetHex, proof.Target)
+	assert.Equal(t, merkle.TargetTypeHeader, proof.TargetType)
+
+	hdr, err := merkle.NewVerifier(mainnetClient(t)).VerifyLegacy(context.TODO(), mainnetTxIDs[2], &legacy)
+	require.NoError(t, err)
+	assert.Equal(t, mainnetHash, hdr.Hash)
+
+	bump, err := merkle.LegacyToBUMP(&legacy)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(mainnetHeight), bump.BlockHeight)
+	root, err := bump.CalculateRootGivenTxid(mainnetTxIDs[2])
+	require.NoError(t, err)
+	assert.Equal(t, mainnetRoot, root)
+
+	bump, err = merkle.ToBUMP(&bc.MerkleProof{Index: 1, TxOrID: mainnetTxIDs[1], Nodes: mainnetNodes[1]}, mainnetHeight)
+	require.NoError(t, err)
+	root, err = bump.CalculateRootGivenTxid(mainnetTxIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, mainnetRoot, root)
+}
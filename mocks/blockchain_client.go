@@ -61,6 +61,12 @@ var _ bn.BlockChainClient = &BlockChainClientMock{}
 //			BlockStatsByHeightFunc: func(ctx context.Context, height int, fields ...string) (*models.BlockStats, error) {
 //				panic("mock out the BlockStatsByHeight method")
 //			},
+//			BlockStreamFunc: func(ctx context.Context, hash string) (*models.BlockStream, error) {
+//				panic("mock out the BlockStream method")
+//			},
+//			BlockStreamByHeightFunc: func(ctx context.Context, height int) (*models.BlockStream, error) {
+//				panic("mock out the BlockStreamByHeight method")
+//			},
 //			ChainInfoFunc: func(ctx context.Context) (*models.ChainInfo, error) {
 //				panic("mock out the ChainInfo method")
 //			},
@@ -82,6 +88,9 @@ var _ bn.BlockChainClient = &BlockChainClientMock{}
 //			GenerateToAddressFunc: func(ctx context.Context, n int, addr string, opts *models.OptsGenerate) ([]string, error) {
 //				panic("mock out the GenerateToAddress method")
 //			},
+//			InvalidateBlockFunc: func(ctx context.Context, blockHash string) error {
+//				panic("mock out the InvalidateBlock method")
+//			},
 //			LegacyMerkleProofFunc: func(ctx context.Context, txID string, opts *models.OptsLegacyMerkleProof) (*models.LegacyMerkleProof, error) {
 //				panic("mock out the LegacyMerkleProof method")
 //			},
@@ -176,6 +185,12 @@ type BlockChainClientMock struct {
 	// BlockStatsByHeightFunc mocks the BlockStatsByHeight method.
 	BlockStatsByHeightFunc func(ctx context.Context, height int, fields ...string) (*models.BlockStats, error)
 
+	// BlockStreamFunc mocks the BlockStream method.
+	BlockStreamFunc func(ctx context.Context, hash string) (*models.BlockStream, error)
+
+	// BlockStreamByHeightFunc mocks the BlockStreamByHeight method.
+	BlockStreamByHeightFunc func(ctx context.Context, height int) (*models.BlockStream, error)
+
 	// ChainInfoFunc mocks the ChainInfo method.
 	ChainInfoFunc func(ctx context.Context) (*models.ChainInfo, error)
 
@@ -197,7 +212,7 @@ type BlockChainClientMock struct {
 	// GenerateToAddressFunc mocks the GenerateToAddress method.
 	GenerateToAddressFunc func(ctx context.Context, n int, addr string, opts *models.OptsGenerate) ([]string, error)
 
-	// InvalidateBlockFunc mockes the InvalidateBlock method
+	// InvalidateBlockFunc mocks the InvalidateBlock method.
 	InvalidateBlockFunc func(ctx context.Context, blockHash string) error
 
 	// LegacyMerkleProofFunc mocks the LegacyMerkleProof method.
@@ -341,6 +356,20 @@ type BlockChainClientMock struct {
 			// Fields is the fields argument value.
 			Fields []string
 		}
+		// BlockStream holds details about calls to the BlockStream method.
+		BlockStream []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Hash is the hash argument value.
+			Hash string
+		}
+		// BlockStreamByHeight holds details about calls to the BlockStreamByHeight method.
+		BlockStreamByHeight []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Height is the height argument value.
+			Height int
+		}
 		// ChainInfo holds details about calls to the ChainInfo method.
 		ChainInfo []struct {
 			// Ctx is the ctx argument value.
@@ -388,11 +417,11 @@ type BlockChainClientMock struct {
 			// Opts is the opts argument value.
 			Opts *models.OptsGenerate
 		}
-		// InvalidateBlock holds details about calls to the InvalidateBlock method
+		// InvalidateBlock holds details about calls to the InvalidateBlock method.
 		InvalidateBlock []struct {
-			// Ctx is the ctx argument value
+			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// BlockHash is the hash of the block to invalidate
+			// BlockHash is the blockHash argument value.
 			BlockHash string
 		}
 		// LegacyMerkleProof holds details about calls to the LegacyMerkleProof method.
@@ -519,6 +548,8 @@ type BlockChainClientMock struct {
 	lockBlockHexByHeight          sync.RWMutex
 	lockBlockStats                sync.RWMutex
 	lockBlockStatsByHeight        sync.RWMutex
+	lockBlockStream               sync.RWMutex
+	lockBlockStreamByHeight       sync.RWMutex
 	lockChainInfo                 sync.RWMutex
 	lockChainTips                 sync.RWMutex
 	lockChainTxStats              sync.RWMutex
@@ -1013,6 +1044,78 @@ func (mock *BlockChainClientMock) BlockStatsByHeightCalls() []struct {
 	return calls
 }
 
+// BlockStream calls BlockStreamFunc.
+func (mock *BlockChainClientMock) BlockStream(ctx context.Context, hash string) (*models.BlockStream, error) {
+	if mock.BlockStreamFunc == nil {
+		panic("BlockChainClientMock.BlockStreamFunc: method is nil but BlockChainClient.BlockStream was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Hash string
+	}{
+		Ctx:  ctx,
+		Hash: hash,
+	}
+	mock.lockBlockStream.Lock()
+	mock.calls.BlockStream = append(mock.calls.BlockStream, callInfo)
+	mock.lockBlockStream.Unlock()
+	return mock.BlockStreamFunc(ctx, hash)
+}
+
+// BlockStreamCalls gets all the calls that were made to BlockStream.
+// Check the length with:
+//
+//	len(mockedBlockChainClient.BlockStreamCalls())
+func (mock *BlockChainClientMock) BlockStreamCalls() []struct {
+	Ctx  context.Context
+	Hash string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Hash string
+	}
+	mock.lockBlockStream.RLock()
+	calls = mock.calls.BlockStream
+	mock.lockBlockStream.RUnlock()
+	return calls
+}
+
+// BlockStreamByHeight calls BlockStreamByHeightFunc.
+func (mock *BlockChainClientMock) BlockStreamByHeight(ctx context.Context, height int) (*models.BlockStream, error) {
+	if mock.BlockStreamByHeightFunc == nil {
+		panic("BlockChainClientMock.BlockStreamByHeightFunc: method is nil but BlockChainClient.BlockStreamByHeight was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Height int
+	}{
+		Ctx:    ctx,
+		Height: height,
+	}
+	mock.lockBlockStreamByHeight.Lock()
+	mock.calls.BlockStreamByHeight = append(mock.calls.BlockStreamByHeight, callInfo)
+	mock.lockBlockStreamByHeight.Unlock()
+	return mock.BlockStreamByHeightFunc(ctx, height)
+}
+
+// BlockStreamByHeightCalls gets all the calls that were made to BlockStreamByHeight.
+// Check the length with:
+//
+//	len(mockedBlockChainClient.BlockStreamByHeightCalls())
+func (mock *BlockChainClientMock) BlockStreamByHeightCalls() []struct {
+	Ctx    context.Context
+	Height int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Height int
+	}
+	mock.lockBlockStreamByHeight.RLock()
+	calls = mock.calls.BlockStreamByHeight
+	mock.lockBlockStreamByHeight.RUnlock()
+	return calls
+}
+
 // ChainInfo calls ChainInfoFunc.
 func (mock *BlockChainClientMock) ChainInfo(ctx context.Context) (*models.ChainInfo, error) {
 	if mock.ChainInfoFunc == nil {
@@ -1262,7 +1365,7 @@ func (mock *BlockChainClientMock) GenerateToAddressCalls() []struct {
 }
 
 // InvalidateBlock calls InvalidateBlockFunc.
-func (mock *BlockChainClientMock) InvalidateBlock(ctx context.Context, hash string) error {
+func (mock *BlockChainClientMock) InvalidateBlock(ctx context.Context, blockHash string) error {
 	if mock.InvalidateBlockFunc == nil {
 		panic("BlockChainClientMock.InvalidateBlockFunc: method is nil but BlockChainClient.InvalidateBlock was just called")
 	}
@@ -1271,12 +1374,30 @@ func (mock *BlockChainClientMock) InvalidateBlock(ctx context.Context, hash stri
 		BlockHash string
 	}{
 		Ctx:       ctx,
-		BlockHash: hash,
+		BlockHash: blockHash,
 	}
 	mock.lockInvalidateBlock.Lock()
 	mock.calls.InvalidateBlock = append(mock.calls.InvalidateBlock, callInfo)
 	mock.lockInvalidateBlock.Unlock()
-	return mock.InvalidateBlockFunc(ctx, hash)
+	return mock.InvalidateBlockFunc(ctx, blockHash)
+}
+
+// InvalidateBlockCalls gets all the calls that were made to InvalidateBlock.
+// Check the length with:
+//
+//	len(mockedBlockChainClient.InvalidateBlockCalls())
+func (mock *BlockChainClientMock) InvalidateBlockCalls() []struct {
+	Ctx       context.Context
+	BlockHash string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		BlockHash string
+	}
+	mock.lockInvalidateBlock.RLock()
+	calls = mock.calls.InvalidateBlock
+	mock.lockInvalidateBlock.RUnlock()
+	return calls
 }
 
 // LegacyMerkleProof calls LegacyMerkleProofFunc.
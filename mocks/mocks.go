@@ -1,7 +1,9 @@
 package mocks
 
 //go:generate moq -pkg mocks -out node_client.go ../ NodeClient
+//go:generate moq -pkg mocks -out batch_client.go ../ BatchClient
 //go:generate moq -pkg mocks -out blockchain_client.go ../ BlockChainClient
+//go:generate moq -pkg mocks -out cache_client.go ../ CacheClient
 //go:generate moq -pkg mocks -out control_client.go ../ ControlClient
 //go:generate moq -pkg mocks -out mining_client.go ../ MiningClient
 //go:generate moq -pkg mocks -out network_client.go ../ NetworkClient
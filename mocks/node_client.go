@@ -47,12 +47,21 @@ var _ bn.NodeClient = &NodeClientMock{}
 //			AddNodeFunc: func(ctx context.Context, node string, command internal.NodeAddType) error {
 //				panic("mock out the AddNode method")
 //			},
+//			AddToConfiscationTransactionWhitelistFunc: func(ctx context.Context, funds []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
+//				panic("mock out the AddToConfiscationTransactionWhitelist method")
+//			},
+//			AddToConsensusBlacklistFunc: func(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+//				panic("mock out the AddToConsensusBlacklist method")
+//			},
 //			BackupWalletFunc: func(ctx context.Context, dest string) error {
 //				panic("mock out the BackupWallet method")
 //			},
 //			BalanceFunc: func(ctx context.Context, opts *models.OptsBalance) (uint64, error) {
 //				panic("mock out the Balance method")
 //			},
+//			BatchFunc: func() *bn.Batch {
+//				panic("mock out the Batch method")
+//			},
 //			BestBlockHashFunc: func(ctx context.Context) (string, error) {
 //				panic("mock out the BestBlockHash method")
 //			},
@@ -92,9 +101,18 @@ var _ bn.NodeClient = &NodeClientMock{}
 //			BlockStatsByHeightFunc: func(ctx context.Context, height int, fields ...string) (*models.BlockStats, error) {
 //				panic("mock out the BlockStatsByHeight method")
 //			},
+//			BlockStreamFunc: func(ctx context.Context, hash string) (*models.BlockStream, error) {
+//				panic("mock out the BlockStream method")
+//			},
+//			BlockStreamByHeightFunc: func(ctx context.Context, height int) (*models.BlockStream, error) {
+//				panic("mock out the BlockStreamByHeight method")
+//			},
 //			BlockTemplateFunc: func(ctx context.Context, opts *models.BlockTemplateRequest) (*models.BlockTemplate, error) {
 //				panic("mock out the BlockTemplate method")
 //			},
+//			CacheStatsFunc: func() models.CacheStats {
+//				panic("mock out the CacheStats method")
+//			},
 //			ChainInfoFunc: func(ctx context.Context) (*models.ChainInfo, error) {
 //				panic("mock out the ChainInfo method")
 //			},
@@ -158,7 +176,7 @@ var _ bn.NodeClient = &NodeClientMock{}
 //			ImportMultiFunc: func(ctx context.Context, reqs []models.ImportMultiRequest, opts *models.OptsImportMulti) ([]*models.ImportMulti, error) {
 //				panic("mock out the ImportMulti method")
 //			},
-//			ImportPrivateKeyFunc: func(ctx context.Context, w *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error {
+//			ImportPrivateKeyFunc: func(ctx context.Context, pk *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error {
 //				panic("mock out the ImportPrivateKey method")
 //			},
 //			ImportPrunedFundsFunc: func(ctx context.Context, tx *bt.Tx, txOutProof string) error {
@@ -173,6 +191,12 @@ var _ bn.NodeClient = &NodeClientMock{}
 //			InfoFunc: func(ctx context.Context) (*models.Info, error) {
 //				panic("mock out the Info method")
 //			},
+//			InvalidateBlockFunc: func(ctx context.Context, blockHash string) error {
+//				panic("mock out the InvalidateBlock method")
+//			},
+//			InvalidateCacheFunc: func(scopes ...models.CacheScope)  {
+//				panic("mock out the InvalidateCache method")
+//			},
 //			KeypoolRefillFunc: func(ctx context.Context, opts *models.OptsKeypoolRefill) error {
 //				panic("mock out the KeypoolRefill method")
 //			},
@@ -417,10 +441,10 @@ type NodeClientMock struct {
 	// AddNodeFunc mocks the AddNode method.
 	AddNodeFunc func(ctx context.Context, node string, command internal.NodeAddType) error
 
-	// AddToConfiscationTransactionWhitelist mocks the AddToConfiscationTransactionWhitelist method
-	AddToConfiscationTransactionWhitelistFunc func(ctx context.Context, confiscationTransactions []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error)
+	// AddToConfiscationTransactionWhitelistFunc mocks the AddToConfiscationTransactionWhitelist method.
+	AddToConfiscationTransactionWhitelistFunc func(ctx context.Context, funds []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error)
 
-	// AddToConsensusBlacklistFunc mocks the AddToConsensusBlacklist
+	// AddToConsensusBlacklistFunc mocks the AddToConsensusBlacklist method.
 	AddToConsensusBlacklistFunc func(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error)
 
 	// BackupWalletFunc mocks the BackupWallet method.
@@ -429,6 +453,9 @@ type NodeClientMock struct {
 	// BalanceFunc mocks the Balance method.
 	BalanceFunc func(ctx context.Context, opts *models.OptsBalance) (uint64, error)
 
+	// BatchFunc mocks the Batch method.
+	BatchFunc func() *bn.Batch
+
 	// BestBlockHashFunc mocks the BestBlockHash method.
 	BestBlockHashFunc func(ctx context.Context) (string, error)
 
@@ -468,9 +495,18 @@ type NodeClientMock struct {
 	// BlockStatsByHeightFunc mocks the BlockStatsByHeight method.
 	BlockStatsByHeightFunc func(ctx context.Context, height int, fields ...string) (*models.BlockStats, error)
 
+	// BlockStreamFunc mocks the BlockStream method.
+	BlockStreamFunc func(ctx context.Context, hash string) (*models.BlockStream, error)
+
+	// BlockStreamByHeightFunc mocks the BlockStreamByHeight method.
+	BlockStreamByHeightFunc func(ctx context.Context, height int) (*models.BlockStream, error)
+
 	// BlockTemplateFunc mocks the BlockTemplate method.
 	BlockTemplateFunc func(ctx context.Context, opts *models.BlockTemplateRequest) (*models.BlockTemplate, error)
 
+	// CacheStatsFunc mocks the CacheStats method.
+	CacheStatsFunc func() models.CacheStats
+
 	// ChainInfoFunc mocks the ChainInfo method.
 	ChainInfoFunc func(ctx context.Context) (*models.ChainInfo, error)
 
@@ -535,7 +571,7 @@ type NodeClientMock struct {
 	ImportMultiFunc func(ctx context.Context, reqs []models.ImportMultiRequest, opts *models.OptsImportMulti) ([]*models.ImportMulti, error)
 
 	// ImportPrivateKeyFunc mocks the ImportPrivateKey method.
-	ImportPrivateKeyFunc func(ctx context.Context, w *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error
+	ImportPrivateKeyFunc func(ctx context.Context, pk *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error
 
 	// ImportPrunedFundsFunc mocks the ImportPrunedFunds method.
 	ImportPrunedFundsFunc func(ctx context.Context, tx *bt.Tx, txOutProof string) error
@@ -550,7 +586,10 @@ type NodeClientMock struct {
 	InfoFunc func(ctx context.Context) (*models.Info, error)
 
 	// InvalidateBlockFunc mocks the InvalidateBlock method.
-	InvalidateBlockFunc func(ctx context.Context, hash string) error
+	InvalidateBlockFunc func(ctx context.Context, blockHash string) error
+
+	// InvalidateCacheFunc mocks the InvalidateCache method.
+	InvalidateCacheFunc func(scopes ...models.CacheScope)
 
 	// KeypoolRefillFunc mocks the KeypoolRefill method.
 	KeypoolRefillFunc func(ctx context.Context, opts *models.OptsKeypoolRefill) error
@@ -821,18 +860,18 @@ type NodeClientMock struct {
 			// Command is the command argument value.
 			Command internal.NodeAddType
 		}
-		// AddToConfiscationTransactionWhitelist holds details about calls to the AddToConfiscationTransactionWhitelist method
+		// AddToConfiscationTransactionWhitelist holds details about calls to the AddToConfiscationTransactionWhitelist method.
 		AddToConfiscationTransactionWhitelist []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// ConfiscationTransactions is the confiscation transactions argument value
-			ConfiscationTransactions []models.ConfiscationTransactionDetails
+			// Funds is the funds argument value.
+			Funds []models.ConfiscationTransactionDetails
 		}
-		// AddToConsensusBlacklist holds details about calls to the AddToConsensusBlacklist method
+		// AddToConsensusBlacklist holds details about calls to the AddToConsensusBlacklist method.
 		AddToConsensusBlacklist []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Funds is the funds argument value
+			// Funds is the funds argument value.
 			Funds []models.Fund
 		}
 		// BackupWallet holds details about calls to the BackupWallet method.
@@ -849,6 +888,9 @@ type NodeClientMock struct {
 			// Opts is the opts argument value.
 			Opts *models.OptsBalance
 		}
+		// Batch holds details about calls to the Batch method.
+		Batch []struct {
+		}
 		// BestBlockHash holds details about calls to the BestBlockHash method.
 		BestBlockHash []struct {
 			// Ctx is the ctx argument value.
@@ -940,6 +982,20 @@ type NodeClientMock struct {
 			// Fields is the fields argument value.
 			Fields []string
 		}
+		// BlockStream holds details about calls to the BlockStream method.
+		BlockStream []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Hash is the hash argument value.
+			Hash string
+		}
+		// BlockStreamByHeight holds details about calls to the BlockStreamByHeight method.
+		BlockStreamByHeight []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Height is the height argument value.
+			Height int
+		}
 		// BlockTemplate holds details about calls to the BlockTemplate method.
 		BlockTemplate []struct {
 			// Ctx is the ctx argument value.
@@ -947,6 +1003,9 @@ type NodeClientMock struct {
 			// Opts is the opts argument value.
 			Opts *models.BlockTemplateRequest
 		}
+		// CacheStats holds details about calls to the CacheStats method.
+		CacheStats []struct {
+		}
 		// ChainInfo holds details about calls to the ChainInfo method.
 		ChainInfo []struct {
 			// Ctx is the ctx argument value.
@@ -1096,8 +1155,8 @@ type NodeClientMock struct {
 		ImportPrivateKey []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// P is the p argument value.
-			P *primitives.PrivateKey
+			// Pk is the pk argument value.
+			Pk *primitives.PrivateKey
 			// Opts is the opts argument value.
 			Opts *models.OptsImportPrivateKey
 		}
@@ -1135,9 +1194,14 @@ type NodeClientMock struct {
 		InvalidateBlock []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// BlockHash is the hash argument value.
+			// BlockHash is the blockHash argument value.
 			BlockHash string
 		}
+		// InvalidateCache holds details about calls to the InvalidateCache method.
+		InvalidateCache []struct {
+			// Scopes is the scopes argument value.
+			Scopes []models.CacheScope
+		}
 		// KeypoolRefill holds details about calls to the KeypoolRefill method.
 		KeypoolRefill []struct {
 			// Ctx is the ctx argument value.
@@ -1546,8 +1610,8 @@ type NodeClientMock struct {
 		SignMessageWithPrivKey []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Pis the w argument value.
-			P *primitives.PrivateKey
+			// W is the w argument value.
+			W *primitives.PrivateKey
 			// Msg is the msg argument value.
 			Msg string
 		}
@@ -1623,8 +1687,8 @@ type NodeClientMock struct {
 		VerifySignedMessage []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// Pis the w argument value.
-			P *primitives.PrivateKey
+			// W is the w argument value.
+			W *primitives.PrivateKey
 			// Signature is the signature argument value.
 			Signature string
 			// Message is the message argument value.
@@ -1666,10 +1730,11 @@ type NodeClientMock struct {
 	lockActiveZMQNotifications                sync.RWMutex
 	lockAddMultiSigAddress                    sync.RWMutex
 	lockAddNode                               sync.RWMutex
-	lockAddToConsensusBlacklist               sync.RWMutex
 	lockAddToConfiscationTransactionWhitelist sync.RWMutex
+	lockAddToConsensusBlacklist               sync.RWMutex
 	lockBackupWallet                          sync.RWMutex
 	lockBalance                               sync.RWMutex
+	lockBatch                                 sync.RWMutex
 	lockBestBlockHash                         sync.RWMutex
 	lockBlock                                 sync.RWMutex
 	lockBlockByHeight                         sync.RWMutex
@@ -1683,7 +1748,10 @@ type NodeClientMock struct {
 	lockBlockHexByHeight                      sync.RWMutex
 	lockBlockStats                            sync.RWMutex
 	lockBlockStatsByHeight                    sync.RWMutex
+	lockBlockStream                           sync.RWMutex
+	lockBlockStreamByHeight                   sync.RWMutex
 	lockBlockTemplate                         sync.RWMutex
+	lockCacheStats                            sync.RWMutex
 	lockChainInfo                             sync.RWMutex
 	lockChainTips                             sync.RWMutex
 	lockChainTxStats                          sync.RWMutex
@@ -1711,6 +1779,7 @@ type NodeClientMock struct {
 	lockImportWallet                          sync.RWMutex
 	lockInfo                                  sync.RWMutex
 	lockInvalidateBlock                       sync.RWMutex
+	lockInvalidateCache                       sync.RWMutex
 	lockKeypoolRefill                         sync.RWMutex
 	lockLegacyMerkleProof                     sync.RWMutex
 	lockListAccounts                          sync.RWMutex
@@ -2041,28 +2110,46 @@ func (mock *NodeClientMock) AddNodeCalls() []struct {
 	return calls
 }
 
-// AddToConfiscationTransactionWhitelist calls AddToConfiscationTransactionWhitelistFunc
-func (mock *NodeClientMock) AddToConfiscationTransactionWhitelist(ctx context.Context, confiscationTxs []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
+// AddToConfiscationTransactionWhitelist calls AddToConfiscationTransactionWhitelistFunc.
+func (mock *NodeClientMock) AddToConfiscationTransactionWhitelist(ctx context.Context, funds []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
 	if mock.AddToConfiscationTransactionWhitelistFunc == nil {
-		panic("TransactionClientMock.AddToConfiscationTransactionWhitelistFunc: method is nil but TransactionClient.AddToConfiscationTransactionWhitelist was just called")
+		panic("NodeClientMock.AddToConfiscationTransactionWhitelistFunc: method is nil but NodeClient.AddToConfiscationTransactionWhitelist was just called")
 	}
 	callInfo := struct {
-		Ctx                      context.Context
-		ConfiscationTransactions []models.ConfiscationTransactionDetails
+		Ctx   context.Context
+		Funds []models.ConfiscationTransactionDetails
 	}{
-		Ctx:                      ctx,
-		ConfiscationTransactions: confiscationTxs,
+		Ctx:   ctx,
+		Funds: funds,
 	}
 	mock.lockAddToConfiscationTransactionWhitelist.Lock()
 	mock.calls.AddToConfiscationTransactionWhitelist = append(mock.calls.AddToConfiscationTransactionWhitelist, callInfo)
 	mock.lockAddToConfiscationTransactionWhitelist.Unlock()
-	return mock.AddToConfiscationTransactionWhitelist(ctx, confiscationTxs)
+	return mock.AddToConfiscationTransactionWhitelistFunc(ctx, funds)
+}
+
+// AddToConfiscationTransactionWhitelistCalls gets all the calls that were made to AddToConfiscationTransactionWhitelist.
+// Check the length with:
+//
+//	len(mockedNodeClient.AddToConfiscationTransactionWhitelistCalls())
+func (mock *NodeClientMock) AddToConfiscationTransactionWhitelistCalls() []struct {
+	Ctx   context.Context
+	Funds []models.ConfiscationTransactionDetails
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Funds []models.ConfiscationTransactionDetails
+	}
+	mock.lockAddToConfiscationTransactionWhitelist.RLock()
+	calls = mock.calls.AddToConfiscationTransactionWhitelist
+	mock.lockAddToConfiscationTransactionWhitelist.RUnlock()
+	return calls
 }
 
-// AddToConsensusBlacklist calls AddToConsensusBlacklistFunc
+// AddToConsensusBlacklist calls AddToConsensusBlacklistFunc.
 func (mock *NodeClientMock) AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
 	if mock.AddToConsensusBlacklistFunc == nil {
-		panic("TransactionClientMock.AddToConsensusBlacklistFunc: method is nil but TransactionClient.CreateRawTransaction was just called")
+		panic("NodeClientMock.AddToConsensusBlacklistFunc: method is nil but NodeClient.AddToConsensusBlacklist was just called")
 	}
 	callInfo := struct {
 		Ctx   context.Context
@@ -2077,6 +2164,24 @@ func (mock *NodeClientMock) AddToConsensusBlacklist(ctx context.Context, funds [
 	return mock.AddToConsensusBlacklistFunc(ctx, funds)
 }
 
+// AddToConsensusBlacklistCalls gets all the calls that were made to AddToConsensusBlacklist.
+// Check the length with:
+//
+//	len(mockedNodeClient.AddToConsensusBlacklistCalls())
+func (mock *NodeClientMock) AddToConsensusBlacklistCalls() []struct {
+	Ctx   context.Context
+	Funds []models.Fund
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Funds []models.Fund
+	}
+	mock.lockAddToConsensusBlacklist.RLock()
+	calls = mock.calls.AddToConsensusBlacklist
+	mock.lockAddToConsensusBlacklist.RUnlock()
+	return calls
+}
+
 // BackupWallet calls BackupWalletFunc.
 func (mock *NodeClientMock) BackupWallet(ctx context.Context, dest string) error {
 	if mock.BackupWalletFunc == nil {
@@ -2149,6 +2254,33 @@ func (mock *NodeClientMock) BalanceCalls() []struct {
 	return calls
 }
 
+// Batch calls BatchFunc.
+func (mock *NodeClientMock) Batch() *bn.Batch {
+	if mock.BatchFunc == nil {
+		panic("NodeClientMock.BatchFunc: method is nil but NodeClient.Batch was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockBatch.Lock()
+	mock.calls.Batch = append(mock.calls.Batch, callInfo)
+	mock.lockBatch.Unlock()
+	return mock.BatchFunc()
+}
+
+// BatchCalls gets all the calls that were made to Batch.
+// Check the length with:
+//
+//	len(mockedNodeClient.BatchCalls())
+func (mock *NodeClientMock) BatchCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockBatch.RLock()
+	calls = mock.calls.Batch
+	mock.lockBatch.RUnlock()
+	return calls
+}
+
 // BestBlockHash calls BestBlockHashFunc.
 func (mock *NodeClientMock) BestBlockHash(ctx context.Context) (string, error) {
 	if mock.BestBlockHashFunc == nil {
@@ -2617,6 +2749,78 @@ func (mock *NodeClientMock) BlockStatsByHeightCalls() []struct {
 	return calls
 }
 
+// BlockStream calls BlockStreamFunc.
+func (mock *NodeClientMock) BlockStream(ctx context.Context, hash string) (*models.BlockStream, error) {
+	if mock.BlockStreamFunc == nil {
+		panic("NodeClientMock.BlockStreamFunc: method is nil but NodeClient.BlockStream was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Hash string
+	}{
+		Ctx:  ctx,
+		Hash: hash,
+	}
+	mock.lockBlockStream.Lock()
+	mock.calls.BlockStream = append(mock.calls.BlockStream, callInfo)
+	mock.lockBlockStream.Unlock()
+	return mock.BlockStreamFunc(ctx, hash)
+}
+
+// BlockStreamCalls gets all the calls that were made to BlockStream.
+// Check the length with:
+//
+//	len(mockedNodeClient.BlockStreamCalls())
+func (mock *NodeClientMock) BlockStreamCalls() []struct {
+	Ctx  context.Context
+	Hash string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Hash string
+	}
+	mock.lockBlockStream.RLock()
+	calls = mock.calls.BlockStream
+	mock.lockBlockStream.RUnlock()
+	return calls
+}
+
+// BlockStreamByHeight calls BlockStreamByHeightFunc.
+func (mock *NodeClientMock) BlockStreamByHeight(ctx context.Context, height int) (*models.BlockStream, error) {
+	if mock.BlockStreamByHeightFunc == nil {
+		panic("NodeClientMock.BlockStreamByHeightFunc: method is nil but NodeClient.BlockStreamByHeight was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Height int
+	}{
+		Ctx:    ctx,
+		Height: height,
+	}
+	mock.lockBlockStreamByHeight.Lock()
+	mock.calls.BlockStreamByHeight = append(mock.calls.BlockStreamByHeight, callInfo)
+	mock.lockBlockStreamByHeight.Unlock()
+	return mock.BlockStreamByHeightFunc(ctx, height)
+}
+
+// BlockStreamByHeightCalls gets all the calls that were made to BlockStreamByHeight.
+// Check the length with:
+//
+//	len(mockedNodeClient.BlockStreamByHeightCalls())
+func (mock *NodeClientMock) BlockStreamByHeightCalls() []struct {
+	Ctx    context.Context
+	Height int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Height int
+	}
+	mock.lockBlockStreamByHeight.RLock()
+	calls = mock.calls.BlockStreamByHeight
+	mock.lockBlockStreamByHeight.RUnlock()
+	return calls
+}
+
 // BlockTemplate calls BlockTemplateFunc.
 func (mock *NodeClientMock) BlockTemplate(ctx context.Context, opts *models.BlockTemplateRequest) (*models.BlockTemplate, error) {
 	if mock.BlockTemplateFunc == nil {
@@ -2653,6 +2857,33 @@ func (mock *NodeClientMock) BlockTemplateCalls() []struct {
 	return calls
 }
 
+// CacheStats calls CacheStatsFunc.
+func (mock *NodeClientMock) CacheStats() models.CacheStats {
+	if mock.CacheStatsFunc == nil {
+		panic("NodeClientMock.CacheStatsFunc: method is nil but NodeClient.CacheStats was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockCacheStats.Lock()
+	mock.calls.CacheStats = append(mock.calls.CacheStats, callInfo)
+	mock.lockCacheStats.Unlock()
+	return mock.CacheStatsFunc()
+}
+
+// CacheStatsCalls gets all the calls that were made to CacheStats.
+// Check the length with:
+//
+//	len(mockedNodeClient.CacheStatsCalls())
+func (mock *NodeClientMock) CacheStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCacheStats.RLock()
+	calls = mock.calls.CacheStats
+	mock.lockCacheStats.RUnlock()
+	return calls
+}
+
 // ChainInfo calls ChainInfoFunc.
 func (mock *NodeClientMock) ChainInfo(ctx context.Context) (*models.ChainInfo, error) {
 	if mock.ChainInfoFunc == nil {
@@ -3406,23 +3637,23 @@ func (mock *NodeClientMock) ImportMultiCalls() []struct {
 }
 
 // ImportPrivateKey calls ImportPrivateKeyFunc.
-func (mock *NodeClientMock) ImportPrivateKey(ctx context.Context, p *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error {
+func (mock *NodeClientMock) ImportPrivateKey(ctx context.Context, pk *primitives.PrivateKey, opts *models.OptsImportPrivateKey) error {
 	if mock.ImportPrivateKeyFunc == nil {
 		panic("NodeClientMock.ImportPrivateKeyFunc: method is nil but NodeClient.ImportPrivateKey was just called")
 	}
 	callInfo := struct {
 		Ctx  context.Context
-		P    *primitives.PrivateKey
+		Pk   *primitives.PrivateKey
 		Opts *models.OptsImportPrivateKey
 	}{
 		Ctx:  ctx,
-		P:    p,
+		Pk:   pk,
 		Opts: opts,
 	}
 	mock.lockImportPrivateKey.Lock()
 	mock.calls.ImportPrivateKey = append(mock.calls.ImportPrivateKey, callInfo)
 	mock.lockImportPrivateKey.Unlock()
-	return mock.ImportPrivateKeyFunc(ctx, p, opts)
+	return mock.ImportPrivateKeyFunc(ctx, pk, opts)
 }
 
 // ImportPrivateKeyCalls gets all the calls that were made to ImportPrivateKey.
@@ -3431,12 +3662,12 @@ func (mock *NodeClientMock) ImportPrivateKey(ctx context.Context, p *primitives.
 //	len(mockedNodeClient.ImportPrivateKeyCalls())
 func (mock *NodeClientMock) ImportPrivateKeyCalls() []struct {
 	Ctx  context.Context
-	P    *primitives.PrivateKey
+	Pk   *primitives.PrivateKey
 	Opts *models.OptsImportPrivateKey
 } {
 	var calls []struct {
 		Ctx  context.Context
-		P    *primitives.PrivateKey
+		Pk   *primitives.PrivateKey
 		Opts *models.OptsImportPrivateKey
 	}
 	mock.lockImportPrivateKey.RLock()
@@ -3594,7 +3825,7 @@ func (mock *NodeClientMock) InfoCalls() []struct {
 }
 
 // InvalidateBlock calls InvalidateBlockFunc.
-func (mock *NodeClientMock) InvalidateBlock(ctx context.Context, hash string) error {
+func (mock *NodeClientMock) InvalidateBlock(ctx context.Context, blockHash string) error {
 	if mock.InvalidateBlockFunc == nil {
 		panic("NodeClientMock.InvalidateBlockFunc: method is nil but NodeClient.InvalidateBlock was just called")
 	}
@@ -3603,12 +3834,62 @@ func (mock *NodeClientMock) InvalidateBlock(ctx context.Context, hash string) er
 		BlockHash string
 	}{
 		Ctx:       ctx,
-		BlockHash: hash,
+		BlockHash: blockHash,
 	}
-	mock.lockBlock.Lock()
+	mock.lockInvalidateBlock.Lock()
 	mock.calls.InvalidateBlock = append(mock.calls.InvalidateBlock, callInfo)
-	mock.lockBlock.Unlock()
-	return mock.InvalidateBlockFunc(ctx, hash)
+	mock.lockInvalidateBlock.Unlock()
+	return mock.InvalidateBlockFunc(ctx, blockHash)
+}
+
+// InvalidateBlockCalls gets all the calls that were made to InvalidateBlock.
+// Check the length with:
+//
+//	len(mockedNodeClient.InvalidateBlockCalls())
+func (mock *NodeClientMock) InvalidateBlockCalls() []struct {
+	Ctx       context.Context
+	BlockHash string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		BlockHash string
+	}
+	mock.lockInvalidateBlock.RLock()
+	calls = mock.calls.InvalidateBlock
+	mock.lockInvalidateBlock.RUnlock()
+	return calls
+}
+
+// InvalidateCache calls InvalidateCacheFunc.
+func (mock *NodeClientMock) InvalidateCache(scopes ...models.CacheScope) {
+	if mock.InvalidateCacheFunc == nil {
+		panic("NodeClientMock.InvalidateCacheFunc: method is nil but NodeClient.InvalidateCache was just called")
+	}
+	callInfo := struct {
+		Scopes []models.CacheScope
+	}{
+		Scopes: scopes,
+	}
+	mock.lockInvalidateCache.Lock()
+	mock.calls.InvalidateCache = append(mock.calls.InvalidateCache, callInfo)
+	mock.lockInvalidateCache.Unlock()
+	mock.InvalidateCacheFunc(scopes...)
+}
+
+// InvalidateCacheCalls gets all the calls that were made to InvalidateCache.
+// Check the length with:
+//
+//	len(mockedNodeClient.InvalidateCacheCalls())
+func (mock *NodeClientMock) InvalidateCacheCalls() []struct {
+	Scopes []models.CacheScope
+} {
+	var calls []struct {
+		Scopes []models.CacheScope
+	}
+	mock.lockInvalidateCache.RLock()
+	calls = mock.calls.InvalidateCache
+	mock.lockInvalidateCache.RUnlock()
+	return calls
 }
 
 // KeypoolRefill calls KeypoolRefillFunc.
@@ -5652,23 +5933,23 @@ func (mock *NodeClientMock) SignMessageCalls() []struct {
 }
 
 // SignMessageWithPrivKey calls SignMessageWithPrivKeyFunc.
-func (mock *NodeClientMock) SignMessageWithPrivKey(ctx context.Context, p *primitives.PrivateKey, msg string) (string, error) {
+func (mock *NodeClientMock) SignMessageWithPrivKey(ctx context.Context, w *primitives.PrivateKey, msg string) (string, error) {
 	if mock.SignMessageWithPrivKeyFunc == nil {
 		panic("NodeClientMock.SignMessageWithPrivKeyFunc: method is nil but NodeClient.SignMessageWithPrivKey was just called")
 	}
 	callInfo := struct {
 		Ctx context.Context
-		P   *primitives.PrivateKey
+		W   *primitives.PrivateKey
 		Msg string
 	}{
 		Ctx: ctx,
-		P:   p,
+		W:   w,
 		Msg: msg,
 	}
 	mock.lockSignMessageWithPrivKey.Lock()
 	mock.calls.SignMessageWithPrivKey = append(mock.calls.SignMessageWithPrivKey, callInfo)
 	mock.lockSignMessageWithPrivKey.Unlock()
-	return mock.SignMessageWithPrivKeyFunc(ctx, p, msg)
+	return mock.SignMessageWithPrivKeyFunc(ctx, w, msg)
 }
 
 // SignMessageWithPrivKeyCalls gets all the calls that were made to SignMessageWithPrivKey.
@@ -5677,12 +5958,12 @@ func (mock *NodeClientMock) SignMessageWithPrivKey(ctx context.Context, p *primi
 //	len(mockedNodeClient.SignMessageWithPrivKeyCalls())
 func (mock *NodeClientMock) SignMessageWithPrivKeyCalls() []struct {
 	Ctx context.Context
-	P   *primitives.PrivateKey
+	W   *primitives.PrivateKey
 	Msg string
 } {
 	var calls []struct {
 		Ctx context.Context
-		P   *primitives.PrivateKey
+		W   *primitives.PrivateKey
 		Msg string
 	}
 	mock.lockSignMessageWithPrivKey.RLock()
@@ -6048,25 +6329,25 @@ func (mock *NodeClientMock) VerifyChainCalls() []struct {
 }
 
 // VerifySignedMessage calls VerifySignedMessageFunc.
-func (mock *NodeClientMock) VerifySignedMessage(ctx context.Context, p *primitives.PrivateKey, signature string, message string) (bool, error) {
+func (mock *NodeClientMock) VerifySignedMessage(ctx context.Context, w *primitives.PrivateKey, signature string, message string) (bool, error) {
 	if mock.VerifySignedMessageFunc == nil {
 		panic("NodeClientMock.VerifySignedMessageFunc: method is nil but NodeClient.VerifySignedMessage was just called")
 	}
 	callInfo := struct {
 		Ctx       context.Context
-		P         *primitives.PrivateKey
+		W         *primitives.PrivateKey
 		Signature string
 		Message   string
 	}{
 		Ctx:       ctx,
-		P:         p,
+		W:         w,
 		Signature: signature,
 		Message:   message,
 	}
 	mock.lockVerifySignedMessage.Lock()
 	mock.calls.VerifySignedMessage = append(mock.calls.VerifySignedMessage, callInfo)
 	mock.lockVerifySignedMessage.Unlock()
-	return mock.VerifySignedMessageFunc(ctx, p, signature, message)
+	return mock.VerifySignedMessageFunc(ctx, w, signature, message)
 }
 
 // VerifySignedMessageCalls gets all the calls that were made to VerifySignedMessage.
@@ -6075,13 +6356,13 @@ func (mock *NodeClientMock) VerifySignedMessage(ctx context.Context, p *primitiv
 //	len(mockedNodeClient.VerifySignedMessageCalls())
 func (mock *NodeClientMock) VerifySignedMessageCalls() []struct {
 	Ctx       context.Context
-	P         *primitives.PrivateKey
+	W         *primitives.PrivateKey
 	Signature string
 	Message   string
 } {
 	var calls []struct {
 		Ctx       context.Context
-		P         *primitives.PrivateKey
+		W         *primitives.PrivateKey
 		Signature string
 		Message   string
 	}
@@ -1,13 +1,19 @@
 package models
 
 import (
+	"bufio"
 	"encoding/hex"
 	"encoding/json"
+	"io"
+	"iter"
 
 	"github.com/bsv-blockchain/go-bc"
 	"github.com/bsv-blockchain/go-bt/v2"
 )
 
+// blockStreamBufferSize the size of the buffer a BlockStream is read through.
+const blockStreamBufferSize = 64 << 10
+
 // BlockDecodeHeader model.
 type BlockDecodeHeader struct {
 	BlockHeader
@@ -56,7 +62,8 @@ func (b *Block) UnmarshalJSON(bb []byte) error {
 type BlockHeader struct {
 	*bc.BlockHeader
 
-	Hash          string `json:"hash"`
+	Hash string `json:"hash"`
+	// Confirmations is 0 for blocks which are not on the best chain.
 	Confirmations uint64 `json:"confirmations"`
 	Height        uint64 `json:"height"`
 	// Version           uint64  `json:"version"`
@@ -76,7 +83,7 @@ type BlockHeader struct {
 func (b *BlockHeader) UnmarshalJSON(bb []byte) error {
 	bh := struct {
 		Hash              string  `json:"hash"`
-		Confirmations     uint64  `json:"confirmations"`
+		Confirmations     int64   `json:"confirmations"`
 		Height            uint64  `json:"height"`
 		VersionHex        string  `json:"versionHex"`
 		NumTx             uint64  `json:"num_tx"`
@@ -109,7 +116,8 @@ func (b *BlockHeader) UnmarshalJSON(bb []byte) error {
 	}
 
 	b.Hash = bh.Hash
-	b.Confirmations = bh.Confirmations
+	// Blocks off the best chain have -1 confirmations.
+	b.Confirmations = uint64(max(bh.Confirmations, 0))
 	b.Height = bh.Height
 	b.VersionHex = bh.VersionHex
 	b.NumTx = bh.NumTx
@@ -117,6 +125,9 @@ func (b *BlockHeader) UnmarshalJSON(bb []byte) error {
 	b.Difficulty = bh.Difficulty
 	b.Chainwork = bh.Chainwork
 	b.NextBlockHash = bh.NextBlockHash
+	if b.BlockHeader == nil {
+		b.BlockHeader = &bc.BlockHeader{}
+	}
 	*b.BlockHeader = blockHeader
 	return nil
 }
@@ -143,6 +154,72 @@ func (b *BlockHeader) MarshalJSON() ([]byte, error) {
 	return json.Marshal(bh)
 }
 
+// BlockStream a serialized block read incrementally, holding a single transaction in memory
+// at a time. It must be closed once done with.
+type BlockStream struct {
+	Header  *bc.BlockHeader
+	TxCount uint64
+
+	r    *bufio.Reader
+	c    io.Closer
+	read uint64
+}
+
+// NewBlockStream reads the header and transaction count of the serialized block from rc,
+// leaving the transactions to be read by Txs.
+func NewBlockStream(rc io.ReadCloser) (*BlockStream, error) {
+	b := &BlockStream{
+		r: bufio.NewReaderSize(rc, blockStreamBufferSize),
+		c: rc,
+	}
+
+	hdr := make([]byte, 80)
+	if _, err := io.ReadFull(b.r, hdr); err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	header, err := bc.NewBlockHeaderFromBytes(hdr)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+
+	var n bt.VarInt
+	if _, err = n.ReadFrom(b.r); err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+
+	b.Header = header
+	b.TxCount = uint64(n)
+	return b, nil
+}
+
+// Txs returns an iterator over the transactions of the block, each decoded as it is read.
+// Iteration stops at the first error, which is yielded along with a nil transaction. The
+// transactions can only be iterated once.
+func (b *BlockStream) Txs() iter.Seq2[*bt.Tx, error] {
+	return func(yield func(*bt.Tx, error) bool) {
+		for b.read < b.TxCount {
+			tx := &bt.Tx{}
+			if _, err := tx.ReadFrom(b.r); err != nil {
+				yield(nil, err)
+				return
+			}
+			b.read++
+
+			if !yield(tx, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Close releases the underlying reader.
+func (b *BlockStream) Close() error {
+	return b.c.Close()
+}
+
 // BlockTemplate model.
 type BlockTemplate struct {
 	Capabilities      []string `json:"capabilities"`
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+func TestBlockHeader_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		confirmations string
+		exp           uint64
+	}{
+		"best chain": {
+			confirmations: "3",
+			exp:           3,
+		},
+		"off the best chain": {
+			confirmations: "-1",
+			exp:           0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var bh models.BlockHeader
+			require.NoError(t, json.Unmarshal([]byte(`{
+				"hash": "0000000000000000031c8df6a6bd8ce8bbc0ca4b2b2f3a5e6cb0e8fda1e0b8a1",
+				"confirmations": `+test.confirmations+`,
+				"height": 700000,
+				"version": 536870912,
+				"merkleroot": "7a0e8f4dbf6bdfa2a1c4c1e7f8b73b1b9b4c2c0d9e8f7a6b5c4d3e2f1a0b9c8d",
+				"time": 1630000000,
+				"nonce": 1,
+				"bits": "180f0a3b",
+				"previousblockhash": "00000000000000000a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071"
+			}`), &bh))
+			assert.Equal(t, test.exp, bh.Confirmations)
+			assert.Equal(t, uint64(700000), bh.Height)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// CacheScope groups cached responses that become stale on the same chain event.
+type CacheScope string
+
+// Cache scopes.
+const (
+	// CacheScopeTip responses that are stale once a new block is connected.
+	CacheScopeTip CacheScope = "tip"
+	// CacheScopeMempool responses that are stale once the mempool changes, which includes
+	// a new block being connected.
+	CacheScopeMempool CacheScope = "mempool"
+)
+
+// CachePolicy describes how responses to an RPC method are cached.
+type CachePolicy struct {
+	// NoCache never cache responses to the method.
+	NoCache bool
+	// TTL how long a response remains valid. A zero TTL keeps the response until it is evicted.
+	TTL time.Duration
+	// Scope the chain event upon which responses to the method are invalidated.
+	Scope CacheScope
+}
+
+// CacheStats response cache statistics.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
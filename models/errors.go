@@ -0,0 +1,172 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// RPC error codes returned by the node.
+const (
+	// Standard JSON-RPC 2.0 errors.
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+	ErrCodeParse          = -32700
+
+	// General application defined errors.
+	ErrCodeMisc                 = -1
+	ErrCodeType                 = -3
+	ErrCodeInvalidAddressOrKey  = -5
+	ErrCodeOutOfMemory          = -7
+	ErrCodeInvalidParameter     = -8
+	ErrCodeDatabase             = -20
+	ErrCodeDeserialization      = -22
+	ErrCodeVerify               = -25
+	ErrCodeVerifyRejected       = -26
+	ErrCodeVerifyAlreadyInChain = -27
+	ErrCodeInWarmup             = -28
+	ErrCodeMethodDeprecated     = -32
+
+	// P2P client errors.
+	ErrCodeClientNotConnected      = -9
+	ErrCodeClientInInitialDownload = -10
+	ErrCodeClientNodeAlreadyAdded  = -23
+	ErrCodeClientNodeNotAdded      = -24
+	ErrCodeClientNodeNotConnected  = -29
+	ErrCodeClientInvalidIPOrSubnet = -30
+	ErrCodeClientP2PDisabled       = -31
+
+	// Wallet errors.
+	ErrCodeWallet                   = -4
+	ErrCodeWalletInsufficientFunds  = -6
+	ErrCodeWalletInvalidAccountName = -11
+	ErrCodeWalletKeypoolRanOut      = -12
+	ErrCodeWalletUnlockNeeded       = -13
+	ErrCodeWalletPassphraseWrong    = -14
+	ErrCodeWalletWrongEncState      = -15
+	ErrCodeWalletEncryptionFailed   = -16
+	ErrCodeWalletAlreadyUnlocked    = -17
+)
+
+// Transaction reject codes, reported as the numeric prefix of the message of a rejected transaction.
+const (
+	RejectCodeMalformed       = 0x01
+	RejectCodeInvalid         = 0x10
+	RejectCodeObsolete        = 0x11
+	RejectCodeDuplicate       = 0x12
+	RejectCodeNonStandard     = 0x40
+	RejectCodeDust            = 0x41
+	RejectCodeInsufficientFee = 0x42
+	RejectCodeCheckpoint      = 0x43
+	RejectCodeAlreadyKnown    = 0x101
+	RejectCodeConflict        = 0x102
+	RejectCodeMempoolFull     = 0x103
+)
+
+// Sentinel errors, matched against an *Error returned by the node with errors.Is:
+//
+//	_, err := c.SendRawTransaction(ctx, tx, nil)
+//	if errors.Is(err, models.ErrTxAlreadyKnown) {
+//		// Already in the mempool, nothing to do.
+//	}
+var (
+	ErrInWarmup           = errors.New("node is warming up")
+	ErrMethodNotFound     = errors.New("method not found")
+	ErrInvalidParameter   = errors.New("invalid parameter")
+	ErrInvalidAddress     = errors.New("invalid address")
+	ErrBlockNotFound      = errors.New("block not found")
+	ErrTxNotFound         = errors.New("transaction not found")
+	ErrTxRejected         = errors.New("transaction rejected")
+	ErrTxAlreadyKnown     = errors.New("transaction already known")
+	ErrTxAlreadyInChain   = errors.New("transaction already in block chain")
+	ErrMissingInputs      = errors.New("missing inputs")
+	ErrTxConflict         = errors.New("transaction conflicts with another")
+	ErrInsufficientFee    = errors.New("insufficient fee")
+	ErrNonStandard        = errors.New("transaction is non-standard")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrWalletUnlockNeeded = errors.New("wallet unlock needed")
+	ErrClientNotConnected = errors.New("node is not connected")
+	ErrInInitialDownload  = errors.New("node is in initial block download")
+)
+
+// Reject a transaction reject reason reported by the node.
+type Reject struct {
+	Code    int
+	Reason  string
+	Details string
+}
+
+// Reject parses the reject code and reason from the message of a transaction rejected
+// by the node, which takes the form `<code>: <reason> (<details>)`. Messages without
+// a reject code, such as `Missing inputs`, are returned as the reason with a zero code.
+func (e Error) Reject() *Reject {
+	r := &Reject{Reason: strings.TrimSpace(e.Message)}
+	if code, reason, ok := strings.Cut(r.Reason, ": "); ok {
+		if n, err := strconv.Atoi(code); err == nil {
+			r.Code = n
+			r.Reason = reason
+		}
+	}
+
+	if reason, details, ok := strings.Cut(r.Reason, " ("); ok && strings.HasSuffix(details, ")") {
+		r.Reason = reason
+		r.Details = strings.TrimSuffix(details, ")")
+	}
+
+	return r
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (e Error) Is(target error) bool {
+	rej := e.Reject()
+	msg := strings.ToLower(e.Message)
+	reason := strings.ToLower(rej.Reason)
+
+	switch target {
+	case ErrInWarmup:
+		return e.Code == ErrCodeInWarmup
+	case ErrMethodNotFound:
+		return e.Code == ErrCodeMethodNotFound
+	case ErrInvalidParameter:
+		return e.Code == ErrCodeInvalidParameter || e.Code == ErrCodeInvalidParams
+	case ErrInvalidAddress:
+		return e.Code == ErrCodeInvalidAddressOrKey && strings.Contains(msg, "address")
+	case ErrBlockNotFound:
+		return (e.Code == ErrCodeInvalidAddressOrKey && strings.Contains(msg, "block not found")) ||
+			(e.Code == ErrCodeInvalidParameter && strings.Contains(msg, "block height out of range"))
+	case ErrTxNotFound:
+		return e.Code == ErrCodeInvalidAddressOrKey &&
+			(strings.Contains(msg, "no such mempool") || strings.Contains(msg, "not in mempool") ||
+				strings.Contains(msg, "not yet in block") || strings.Contains(msg, "not found in provided block"))
+	case ErrTxRejected:
+		return e.Code == ErrCodeVerifyRejected
+	case ErrTxAlreadyKnown:
+		return reason == "txn-already-known" || reason == "txn-already-in-mempool" ||
+			rej.Code == RejectCodeAlreadyKnown || strings.Contains(msg, "already in the mempool")
+	case ErrTxAlreadyInChain:
+		return e.Code == ErrCodeVerifyAlreadyInChain && !e.Is(ErrTxAlreadyKnown)
+	case ErrMissingInputs:
+		return reason == "missing inputs" || reason == "missing-inputs" || reason == "bad-txns-inputs-missingorspent"
+	case ErrTxConflict:
+		return reason == "txn-mempool-conflict" || reason == "txn-double-spend-detected" ||
+			rej.Code == RejectCodeConflict
+	case ErrInsufficientFee:
+		return rej.Code == RejectCodeInsufficientFee || strings.Contains(reason, "insufficient fee") ||
+			strings.Contains(reason, "insufficient priority") || strings.Contains(reason, "min fee not met") ||
+			strings.Contains(reason, "min relay fee not met")
+	case ErrNonStandard:
+		return rej.Code == RejectCodeNonStandard || rej.Code == RejectCodeDust
+	case ErrInsufficientFunds:
+		return e.Code == ErrCodeWalletInsufficientFunds
+	case ErrWalletUnlockNeeded:
+		return e.Code == ErrCodeWalletUnlockNeeded
+	case ErrClientNotConnected:
+		return e.Code == ErrCodeClientNotConnected
+	case ErrInInitialDownload:
+		return e.Code == ErrCodeClientInInitialDownload
+	}
+
+	return false
+}
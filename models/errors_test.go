@@ -0,0 +1,157 @@
+package models_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+func TestError_Reject(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err       models.Error
+		expReject *models.Reject
+	}{
+		"code and reason": {
+			err:       models.Error{Code: -26, Message: "257: txn-already-known"},
+			expReject: &models.Reject{Code: models.RejectCodeAlreadyKnown, Reason: "txn-already-known"},
+		},
+		"code, reason and details": {
+			err: models.Error{
+				Code:    -26,
+				Message: "16: mandatory-script-verify-flag-failed (Script failed an OP_EQUALVERIFY operation)",
+			},
+			expReject: &models.Reject{
+				Code:    models.RejectCodeInvalid,
+				Reason:  "mandatory-script-verify-flag-failed",
+				Details: "Script failed an OP_EQUALVERIFY operation",
+			},
+		},
+		"reason only": {
+			err:       models.Error{Code: -25, Message: "Missing inputs"},
+			expReject: &models.Reject{Reason: "Missing inputs"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expReject, test.err.Reject())
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	t.Parallel()
+
+	sentinels := []error{
+		models.ErrInWarmup,
+		models.ErrMethodNotFound,
+		models.ErrInvalidParameter,
+		models.ErrInvalidAddress,
+		models.ErrBlockNotFound,
+		models.ErrTxNotFound,
+		models.ErrTxRejected,
+		models.ErrTxAlreadyKnown,
+		models.ErrTxAlreadyInChain,
+		models.ErrMissingInputs,
+		models.ErrTxConflict,
+		models.ErrInsufficientFee,
+		models.ErrNonStandard,
+		models.ErrInsufficientFunds,
+	}
+
+	tests := map[string]struct {
+		err   error
+		expIs []error
+	}{
+		"warm up": {
+			err:   &models.Error{Code: -28, Message: "Loading block index..."},
+			expIs: []error{models.ErrInWarmup},
+		},
+		"method not found": {
+			err:   &models.Error{Code: -32601, Message: "Method not found"},
+			expIs: []error{models.ErrMethodNotFound},
+		},
+		"invalid address": {
+			err:   &models.Error{Code: -5, Message: "Invalid Bitcoin address"},
+			expIs: []error{models.ErrInvalidAddress},
+		},
+		"block not found": {
+			err:   &models.Error{Code: -5, Message: "Block not found"},
+			expIs: []error{models.ErrBlockNotFound},
+		},
+		"block height out of range": {
+			err:   &models.Error{Code: -8, Message: "Block height out of range"},
+			expIs: []error{models.ErrBlockNotFound, models.ErrInvalidParameter},
+		},
+		"tx not found": {
+			err:   &models.Error{Code: -5, Message: "No such mempool or blockchain transaction. Use gettransaction for wallet transactions."},
+			expIs: []error{models.ErrTxNotFound},
+		},
+		"tx not in block": {
+			err:   &models.Error{Code: -5, Message: "Transaction not found in provided block"},
+			expIs: []error{models.ErrTxNotFound},
+		},
+		"tx not yet mined": {
+			err:   &models.Error{Code: -5, Message: "Transaction not yet in block"},
+			expIs: []error{models.ErrTxNotFound},
+		},
+		"tx already known": {
+			err:   &models.Error{Code: -26, Message: "257: txn-already-known"},
+			expIs: []error{models.ErrTxAlreadyKnown, models.ErrTxRejected},
+		},
+		"tx already in mempool": {
+			err:   &models.Error{Code: -27, Message: "Transaction already in the mempool"},
+			expIs: []error{models.ErrTxAlreadyKnown},
+		},
+		"tx already in chain": {
+			err:   &models.Error{Code: -27, Message: "Transaction already in block chain"},
+			expIs: []error{models.ErrTxAlreadyInChain},
+		},
+		"missing inputs": {
+			err:   &models.Error{Code: -25, Message: "Missing inputs"},
+			expIs: []error{models.ErrMissingInputs},
+		},
+		"mempool conflict": {
+			err:   &models.Error{Code: -26, Message: "258: txn-mempool-conflict"},
+			expIs: []error{models.ErrTxConflict, models.ErrTxRejected},
+		},
+		"insufficient fee": {
+			err:   &models.Error{Code: -26, Message: "66: mempool min fee not met"},
+			expIs: []error{models.ErrInsufficientFee, models.ErrTxRejected},
+		},
+		"dust": {
+			err:   &models.Error{Code: -26, Message: "64: dust"},
+			expIs: []error{models.ErrNonStandard, models.ErrTxRejected},
+		},
+		"script failure": {
+			err:   &models.Error{Code: -26, Message: "16: mandatory-script-verify-flag-failed (Signature must be zero for failed CHECK(MULTI)SIG operation)"},
+			expIs: []error{models.ErrTxRejected},
+		},
+		"insufficient funds": {
+			err:   &models.Error{Code: -6, Message: "Insufficient funds"},
+			expIs: []error{models.ErrInsufficientFunds},
+		},
+		"wrapped": {
+			err:   fmt.Errorf("broadcast: %w", &models.Error{Code: -25, Message: "Missing inputs"}),
+			expIs: []error{models.ErrMissingInputs},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, sentinel := range sentinels {
+				exp := false
+				for _, is := range test.expIs {
+					exp = exp || is == sentinel
+				}
+				assert.Equal(t, exp, errors.Is(test.err, sentinel), "errors.Is(%q, %q)", test.err, sentinel)
+			}
+		})
+	}
+}
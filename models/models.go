@@ -47,6 +47,17 @@ func (e Error) Error() string {
 	return fmt.Sprintf("%d: %s", e.Code, e.Message)
 }
 
+// HTTPError error when the node replies with an HTTP error status and no JSON-RPC response,
+// such as when its work queue is full or the credentials are wrong.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+}
+
 // OptsChainTxStats options.
 type OptsChainTxStats struct {
 	NumBlocks uint32
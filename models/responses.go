@@ -20,7 +20,7 @@ type ChainInfo struct {
 	} `json:"softforks"`
 }
 
-// BlockStats model.
+// BlockStats model. Fees and amounts are in satoshis, and fee rates in satoshis per byte.
 type BlockStats struct {
 	AvgFee           float64 `json:"avgfee"`
 	AvgFeeRate       float64 `json:"avgfeerate"`
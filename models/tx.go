@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/bsv-blockchain/go-bt/v2"
 	"github.com/bsv-blockchain/go-bt/v2/bscript"
@@ -151,7 +152,7 @@ type OptsFundRawTransaction struct {
 	IncludeWatching        bool     `json:"includeWatching,omitempty"`
 	LockUnspents           bool     `json:"lockUnspents,omitempty"`
 	ReserveChangeKey       *bool    `json:"reserveChangeKey,omitempty"`
-	FeeRate                uint64   `json:"feeRate,omitempty"`
+	FeeRate                float64  `json:"feeRate,omitempty"` // BSV per kilobyte.
 	SubtractFeeFromOutputs []uint64 `json:"subtractFeeFromOutputs,omitempty"`
 }
 
@@ -228,28 +229,53 @@ type ParamsSendRawTransactions struct {
 
 // SendRawTransactionsResponse response.
 type SendRawTransactionsResponse struct {
-	Known   []string `json:"known"`
-	Evicted []string `json:"evicted"`
-	Invalid []struct {
-		TxID         string `json:"txid"`
-		RejectCode   int    `json:"reject_code"`
-		RejectReason string `json:"reject_reason"`
-		CollidedWith []struct {
-			TxID string `json:"txid"`
-			Size uint64 `json:"size"`
-			Hex  string `json:"hex"`
-		} `json:"collidedWith"`
-	} `json:"invalid"`
-	Unconfirmed []struct {
-		TxID      string `json:"txid"`
-		Ancestors []struct {
-			TxID string `json:"txid"`
-			Vin  []struct {
-				TxID string `json:"txid"`
-				Vout uint32 `json:"vout"`
-			} `json:"vin"`
-		} `json:"ancestors"`
-	} `json:"unconfirmed"`
+	Known       []string                         `json:"known"`
+	Evicted     []string                         `json:"evicted"`
+	Invalid     []SendRawTransactionsInvalid     `json:"invalid"`
+	Unconfirmed []SendRawTransactionsUnconfirmed `json:"unconfirmed"`
+}
+
+// SendRawTransactionsInvalid a transaction rejected by `sendrawtransactions`.
+type SendRawTransactionsInvalid struct {
+	TxID         string                         `json:"txid"`
+	RejectCode   int                            `json:"reject_code"`
+	RejectReason string                         `json:"reject_reason"`
+	CollidedWith []SendRawTransactionsCollision `json:"collidedWith"`
+}
+
+// Err returns the rejection as an *Error, so the reason can be checked with errors.Is
+// against the errors of this package.
+func (s *SendRawTransactionsInvalid) Err() *Error {
+	return &Error{
+		Code:    ErrCodeVerifyRejected,
+		Message: fmt.Sprintf("%d: %s", s.RejectCode, s.RejectReason),
+	}
+}
+
+// SendRawTransactionsCollision a transaction a rejected transaction double spends with.
+type SendRawTransactionsCollision struct {
+	TxID string `json:"txid"`
+	Size uint64 `json:"size"`
+	Hex  string `json:"hex"`
+}
+
+// SendRawTransactionsUnconfirmed the unconfirmed ancestors of a submitted transaction, listed
+// when requested with ListUnconfirmedAncestors.
+type SendRawTransactionsUnconfirmed struct {
+	TxID      string                        `json:"txid"`
+	Ancestors []SendRawTransactionsAncestor `json:"ancestors"`
+}
+
+// SendRawTransactionsAncestor an unconfirmed ancestor of a submitted transaction.
+type SendRawTransactionsAncestor struct {
+	TxID string                     `json:"txid"`
+	Vin  []SendRawTransactionsInput `json:"vin"`
+}
+
+// SendRawTransactionsInput an input of an unconfirmed ancestor.
+type SendRawTransactionsInput struct {
+	TxID string `json:"txid"`
+	Vout uint32 `json:"vout"`
 }
 
 // AddToConsensusBlacklistNotProcessed represents a not processed transaction output or confiscation transaction
@@ -3,7 +3,9 @@ package bn
 import (
 	"time"
 
+	"github.com/bsv-blockchain/go-bn/internal/config"
 	"github.com/bsv-blockchain/go-bn/internal/service"
+	"github.com/bsv-blockchain/go-bn/models"
 )
 
 // BitcoinClientOptFunc for setting bitcoin client options.
@@ -13,10 +15,14 @@ type BitcoinClientOptFunc func(c *clientOpts)
 type clientOpts struct {
 	timeout   time.Duration
 	host      string
+	hosts     []string
+	pool      config.Pool
 	rpc       service.RPC
 	username  string
 	password  string
 	cache     bool
+	cacheOpts []service.CacheOptFunc
+	retry     *config.Retry
 	isMainnet bool
 }
 
@@ -34,6 +40,56 @@ func WithCache() BitcoinClientOptFunc {
 	}
 }
 
+// WithCacheSize set the maximum number of responses held by the cache enabled with WithCache.
+// Least recently used responses are evicted first.
+func WithCacheSize(n int) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.cacheOpts = append(c.cacheOpts, service.WithCacheSize(n))
+	}
+}
+
+// WithCachePolicy set the policy used by the cache enabled with WithCache for responses to
+// an RPC method, overriding the default. For example, to hold `getblockheader` responses for a
+// minute between blocks, rather than a few seconds:
+//
+//	c := bn.NewNodeClient(bn.WithCache(), bn.WithCachePolicy("getblockheader", models.CachePolicy{
+//		TTL:   time.Minute,
+//		Scope: models.CacheScopeTip,
+//	}))
+func WithCachePolicy(method string, p models.CachePolicy) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.cacheOpts = append(c.cacheOpts, service.WithCachePolicy(method, p))
+	}
+}
+
+// WithRetry retry requests which fail transiently, up to maxAttempts attempts in total.
+// Connection refused and reset errors, 5xx replies and the node warming up (RPC error -28)
+// are retried, with the delay starting at baseDelay and doubling up to maxDelay, plus jitter.
+//
+// Methods which are unsafe to repeat, such as `sendrawtransaction`, are not retried
+// unless WithRetryNonIdempotent is also set.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		if c.retry == nil {
+			c.retry = &config.Retry{}
+		}
+		c.retry.MaxAttempts = maxAttempts
+		c.retry.BaseDelay = baseDelay
+		c.retry.MaxDelay = maxDelay
+	}
+}
+
+// WithRetryNonIdempotent allow methods which are unsafe to repeat, such as `sendrawtransaction`,
+// to be retried when WithRetry is set.
+func WithRetryNonIdempotent() BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		if c.retry == nil {
+			c.retry = &config.Retry{}
+		}
+		c.retry.NonIdempotent = true
+	}
+}
+
 // WithHost set the bitcoin node host.
 func WithHost(host string) BitcoinClientOptFunc {
 	return func(c *clientOpts) {
@@ -41,6 +97,39 @@ func WithHost(host string) BitcoinClientOptFunc {
 	}
 }
 
+// WithHosts spread requests over several bitcoin nodes sharing the same credentials, in
+// place of the single node set WithHost. Nodes which fail health checks, stop responding
+// or lag the best chain tip by more than WithMaxBlockLag blocks are avoided, with
+// requests failing over to the next healthy node. Wallet requests stick to one node.
+func WithHosts(hosts ...string) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.hosts = hosts
+	}
+}
+
+// WithLeastLatency route requests to the node set WithHosts with the lowest latency,
+// rather than round-robin.
+func WithLeastLatency() BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.pool.LeastLatency = true
+	}
+}
+
+// WithMaxBlockLag set the number of blocks a node set WithHosts may lag the best chain tip
+// of the others before it is considered unhealthy.
+func WithMaxBlockLag(blocks uint32) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.pool.MaxBlockLag = blocks
+	}
+}
+
+// WithHealthCheckInterval set the interval between health checks of the nodes set WithHosts.
+func WithHealthCheckInterval(interval time.Duration) BitcoinClientOptFunc {
+	return func(c *clientOpts) {
+		c.pool.HealthCheckInterval = interval
+	}
+}
+
 // WithCreds set the bitcoin node credentials.
 func WithCreds(username, password string) BitcoinClientOptFunc {
 	return func(c *clientOpts) {
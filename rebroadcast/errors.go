@@ -0,0 +1,9 @@
+package rebroadcast
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrEvicted    = errors.New("transaction evicted on resubmission")
+	ErrUnresolved = errors.New("cannot tell whether transaction was mined or double spent")
+)
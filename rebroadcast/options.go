@@ -0,0 +1,61 @@
+package rebroadcast
+
+import "time"
+
+// DefaultInterval the default interval between checks of the unconfirmed transactions.
+const DefaultInterval = 5 * time.Minute
+
+// DefaultSearchDepth the default number of blocks searched for transactions which have left
+// the mempool.
+const DefaultSearchDepth = 144
+
+type engineCfg struct {
+	store       Store
+	interval    time.Duration
+	searchDepth int
+	failureFn   FailureFunc
+	errorFn     ErrorFunc
+}
+
+// EngineOptFunc option func.
+type EngineOptFunc func(c *engineCfg)
+
+// WithStore set the Store the unconfirmed transactions are persisted to. Defaults to one
+// held in memory.
+func WithStore(s Store) EngineOptFunc {
+	return func(c *engineCfg) {
+		c.store = s
+	}
+}
+
+// WithInterval set the interval at which Run checks the unconfirmed transactions. Defaults
+// to DefaultInterval.
+func WithInterval(d time.Duration) EngineOptFunc {
+	return func(c *engineCfg) {
+		c.interval = d
+	}
+}
+
+// WithSearchDepth set the number of blocks back from the tip searched for a transaction which
+// has left the mempool, when the node has no transaction index. Defaults to
+// DefaultSearchDepth.
+func WithSearchDepth(n int) EngineOptFunc {
+	return func(c *engineCfg) {
+		c.searchDepth = n
+	}
+}
+
+// WithFailureHandler set a handler called with each transaction rejected for good.
+func WithFailureHandler(fn FailureFunc) EngineOptFunc {
+	return func(c *engineCfg) {
+		c.failureFn = fn
+	}
+}
+
+// WithErrorHandler set a handler for errors raised checking transactions in the background.
+// They are discarded by default.
+func WithErrorHandler(fn ErrorFunc) EngineOptFunc {
+	return func(c *engineCfg) {
+		c.errorFn = fn
+	}
+}
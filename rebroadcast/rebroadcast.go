@@ -0,0 +1,417 @@
+// Package rebroadcast holds unconfirmed transactions and resubmits them to a bitcoin node
+// when they go missing from its mempool, such as after the node restarts or evicts them.
+package rebroadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn/internal/txgraph"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Engine keeps unconfirmed transactions in the mempool of a node.
+type Engine interface {
+	Add(ctx context.Context, txs ...*bt.Tx) error
+	Remove(ctx context.Context, txIDs ...string) error
+	Txs(ctx context.Context) ([]*bt.Tx, error)
+	Check(ctx context.Context) (*Report, error)
+	Run(ctx context.Context) error
+}
+
+type engine struct {
+	client Client
+	cfg    *engineCfg
+
+	checkMu sync.Mutex
+	// seen the height of the tip when each transaction was last known to be in the mempool,
+	// guarded by checkMu.
+	seen   map[string]uint32
+	mu     sync.Mutex
+	loaded bool
+	txs    []*bt.Tx
+}
+
+// NewEngine returns an engine keeping transactions in the mempool of the node behind client,
+// configured via the provided opt funcs. The transactions held are loaded from the Store on
+// first use.
+func NewEngine(client Client, oo ...EngineOptFunc) Engine {
+	cfg := &engineCfg{
+		store:       NewMemoryStore(),
+		interval:    DefaultInterval,
+		searchDepth: DefaultSearchDepth,
+		failureFn:   func(context.Context, *Failure) {},
+		errorFn:     func(context.Context, error) {},
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+
+	return &engine{
+		client: client,
+		cfg:    cfg,
+		seen:   make(map[string]uint32),
+	}
+}
+
+// Add holds the transactions until they are mined, saving them to the Store. They are not
+// submitted until found missing from the mempool by a check.
+func (e *engine) Add(ctx context.Context, txs ...*bt.Tx) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.load(ctx); err != nil {
+		return err
+	}
+
+	held := make(map[string]bool, len(e.txs))
+	for _, tx := range e.txs {
+		held[tx.TxID()] = true
+	}
+
+	next := slices.Clone(e.txs)
+	for _, tx := range txs {
+		if txID := tx.TxID(); !held[txID] {
+			held[txID] = true
+			next = append(next, tx)
+		}
+	}
+	return e.save(ctx, next)
+}
+
+// Remove stops holding the transactions, saving the rest to the Store. It is called by
+// checks for transactions found mined, but can be called sooner, such as once a transaction
+// is confirmed by a tracker.
+func (e *engine) Remove(ctx context.Context, txIDs ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.load(ctx); err != nil {
+		return err
+	}
+
+	next := slices.DeleteFunc(slices.Clone(e.txs), func(tx *bt.Tx) bool {
+		return slices.Contains(txIDs, tx.TxID())
+	})
+	if len(next) == len(e.txs) {
+		return nil
+	}
+	return e.save(ctx, next)
+}
+
+// Txs returns the transactions held, in the order they were added.
+func (e *engine) Txs(ctx context.Context) ([]*bt.Tx, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.load(ctx); err != nil {
+		return nil, err
+	}
+	return slices.Clone(e.txs), nil
+}
+
+// Check looks up each transaction held with the node. Those missing from the mempool are
+// looked for in the block chain, which needs a transaction index on the node, then
+// resubmitted with `sendrawtransactions`, preceded by the transactions held they spend from
+// so the node accepts them in order. Those rejected for spending missing inputs are looked
+// for in the blocks mined since they were last seen in the mempool, telling transactions
+// mined apart from those double spent.
+//
+// Transactions found mined, or rejected for good, are no longer held, the latter being passed
+// to the FailureFunc. Rejections for too low a fee, evictions and transactions which cannot be
+// told mined or double spent are returned as errors, the transactions being tried again on
+// the next check.
+func (e *engine) Check(ctx context.Context) (*Report, error) {
+	e.checkMu.Lock()
+	defer e.checkMu.Unlock()
+
+	txs, err := e.Txs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{}
+	if len(txs) == 0 {
+		return r, nil
+	}
+
+	tip, err := e.client.BlockCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := &chain{client: e.client, tip: tip, hashes: make(map[int]string)}
+
+	var missing []*bt.Tx
+	for _, tx := range txs {
+		txID := tx.TxID()
+		found, err := e.inMempool(ctx, txID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			e.seen[txID] = tip
+			r.Pending = append(r.Pending, txID)
+			continue
+		}
+
+		if found, err = e.inChain(ctx, txID); err != nil {
+			return nil, err
+		}
+		if found {
+			r.Mined = append(r.Mined, txID)
+			continue
+		}
+		missing = append(missing, tx)
+	}
+
+	var errs []error
+	if len(missing) > 0 {
+		errs = e.resubmit(ctx, c, txs, missing, r)
+	}
+
+	done := slices.Clone(r.Mined)
+	for _, f := range r.Failed {
+		done = append(done, f.Tx.TxID())
+	}
+	if err = e.Remove(ctx, done...); err != nil {
+		return nil, err
+	}
+	for _, txID := range done {
+		delete(e.seen, txID)
+	}
+	for _, f := range r.Failed {
+		e.cfg.failureFn(ctx, f)
+	}
+
+	return r, errors.Join(errs...)
+}
+
+// Run checks the transactions held every interval until ctx is done, passing failures to the
+// error handler.
+func (e *engine) Run(ctx context.Context) error {
+	t := time.NewTicker(e.cfg.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := e.Check(ctx); err != nil && ctx.Err() == nil {
+			e.cfg.errorFn(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// resubmit sends the missing transactions, along with those held they spend from, recording
+// the outcome of each missing transaction in the report. The errors of those to be tried
+// again are returned.
+func (e *engine) resubmit(ctx context.Context, c *chain, held, missing []*bt.Tx, r *Report) []error {
+	txs := txgraph.Sort(append(txgraph.Ancestors(held, missing...), missing...))
+	sent := make(map[string]bool, len(txs))
+	params := make([]models.ParamsSendRawTransactions, 0, len(txs))
+	for _, tx := range txs {
+		sent[tx.TxID()] = true
+		params = append(params, models.ParamsSendRawTransactions{Hex: tx.String()})
+	}
+
+	resp, err := e.client.SendRawTransactions(ctx, params...)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	failed := make(map[string]bool)
+	for _, tx := range txgraph.Sort(missing) {
+		txID := tx.TxID()
+		if slices.Contains(resp.Evicted, txID) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrEvicted, txID))
+			continue
+		}
+
+		rejErr := rejection(resp, txID)
+		if rejErr == nil {
+			e.seen[txID] = c.tip
+			r.Resubmitted = append(r.Resubmitted, txID)
+			continue
+		}
+
+		// Inputs are missing when a parent sent alongside is not accepted, in which case the
+		// transaction shares its fate.
+		var parentFailed, parentRetried bool
+		for _, in := range tx.Inputs {
+			parentID := in.PreviousTxIDStr()
+			parentFailed = parentFailed || failed[parentID]
+			parentRetried = parentRetried || (sent[parentID] && !accepted(resp, parentID))
+		}
+
+		switch {
+		case errors.Is(rejErr, models.ErrTxAlreadyKnown):
+			e.seen[txID] = c.tip
+			r.Pending = append(r.Pending, txID)
+		case errors.Is(rejErr, models.ErrInsufficientFee),
+			errors.Is(rejErr, models.ErrMissingInputs) && parentRetried && !parentFailed:
+			errs = append(errs, fmt.Errorf("failed to resubmit %s: %w", txID, rejErr))
+		case errors.Is(rejErr, models.ErrMissingInputs) && !parentFailed:
+			// A transaction mined while the node has no transaction index spends inputs
+			// which are now spent, as does one double spent.
+			mined, err := e.mined(ctx, c, tx)
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+			case mined:
+				r.Mined = append(r.Mined, txID)
+			default:
+				failed[txID] = true
+				r.Failed = append(r.Failed, &Failure{Tx: tx, Err: rejErr})
+			}
+		default:
+			failed[txID] = true
+			r.Failed = append(r.Failed, &Failure{Tx: tx, Err: rejErr})
+		}
+	}
+
+	return errs
+}
+
+// accepted reports whether the node accepted the transaction, or already knew of it.
+func accepted(resp *models.SendRawTransactionsResponse, txID string) bool {
+	if slices.Contains(resp.Evicted, txID) {
+		return false
+	}
+	rejErr := rejection(resp, txID)
+	return rejErr == nil || errors.Is(rejErr, models.ErrTxAlreadyKnown)
+}
+
+// rejection returns the reason the node gave for rejecting the transaction, nil if it did
+// not.
+func rejection(resp *models.SendRawTransactionsResponse, txID string) *models.Error {
+	for _, inv := range resp.Invalid {
+		if inv.TxID == txID {
+			return inv.Err()
+		}
+	}
+
+	return nil
+}
+
+func (e *engine) inMempool(ctx context.Context, txID string) (bool, error) {
+	_, err := e.client.MempoolEntry(ctx, txID)
+	if errors.Is(err, models.ErrTxNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (e *engine) inChain(ctx context.Context, txID string) (bool, error) {
+	_, err := e.client.RawTransaction(ctx, txID)
+	if errors.Is(err, models.ErrTxNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// mined reports whether the transaction, rejected for spending missing inputs, was mined
+// rather than double spent. It is looked for in the blocks mined since it was last seen in the
+// mempool. When those are not all within the search depth, such as after a restart, it is
+// looked for in as many blocks, then taken as mined if any of its outputs are unspent, and
+// otherwise left unresolved so it is tried again.
+func (e *engine) mined(ctx context.Context, c *chain, tx *bt.Tx) (bool, error) {
+	txID := tx.TxID()
+	from := int(c.tip) - e.cfg.searchDepth + 1
+	seen, ok := e.seen[txID]
+	resolved := ok && int(seen) >= from-1
+	if resolved {
+		from = int(seen) + 1
+	}
+
+	for height := int(c.tip); height >= max(from, 0); height-- {
+		found, err := c.holds(ctx, height, txID)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			return true, nil
+		}
+	}
+	if resolved {
+		return false, nil
+	}
+
+	unspent, err := e.hasUnspent(ctx, tx)
+	if err != nil || unspent {
+		return unspent, err
+	}
+	return false, fmt.Errorf("%w: %s", ErrUnresolved, txID)
+}
+
+// hasUnspent reports whether any output of the transaction is unspent in the block chain.
+func (e *engine) hasUnspent(ctx context.Context, tx *bt.Tx) (bool, error) {
+	for i := range tx.Outputs {
+		out, err := e.client.Output(ctx, tx.TxID(), i, &models.OptsOutput{})
+		if err != nil {
+			return false, err
+		}
+		if out.BestBlock != "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// load reads the transactions from the Store on first use.
+func (e *engine) load(ctx context.Context) error {
+	if e.loaded {
+		return nil
+	}
+
+	txs, err := e.cfg.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	e.txs, e.loaded = txs, true
+	return nil
+}
+
+// save writes the transactions to the Store, holding them once saved.
+func (e *engine) save(ctx context.Context, txs []*bt.Tx) error {
+	if err := e.cfg.store.Save(ctx, txs); err != nil {
+		return err
+	}
+	e.txs = txs
+	return nil
+}
+
+// chain the best chain of the node as of a check, with the hashes of the blocks searched.
+type chain struct {
+	client Client
+	tip    uint32
+	hashes map[int]string
+}
+
+// holds reports whether the block at height on the best chain holds the transaction.
+func (c *chain) holds(ctx context.Context, height int, txID string) (bool, error) {
+	hash, ok := c.hashes[height]
+	if !ok {
+		var err error
+		if hash, err = c.client.BlockHash(ctx, height); err != nil {
+			return false, err
+		}
+		c.hashes[height] = hash
+	}
+
+	_, err := c.client.MerkleProof(ctx, hash, txID, nil)
+	if errors.Is(err, models.ErrTxNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,382 @@
+package rebroadcast_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/rebroadcast"
+	"github.com/bsv-blockchain/go-bn/testing/util"
+)
+
+// node a fake node, holding a mempool and a chain of blocks, replying to
+// `sendrawtransactions` with the rejections given.
+type node struct {
+	mu       sync.Mutex
+	mempool  map[string]bool
+	blocks   [][]string
+	unspent  map[string]bool
+	txIndex  bool
+	rejected map[string]string
+	evicted  []string
+	sent     [][]string
+}
+
+func newNode() *node {
+	return &node{
+		mempool:  make(map[string]bool),
+		blocks:   [][]string{nil},
+		unspent:  make(map[string]bool),
+		rejected: make(map[string]string),
+	}
+}
+
+// mine connects a block holding the txs, removing them from the mempool.
+func (n *node) mine(txIDs ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.blocks = append(n.blocks, txIDs)
+	for _, txID := range txIDs {
+		delete(n.mempool, txID)
+	}
+}
+
+func (n *node) client() *mocks.NodeClientMock {
+	notFound := &models.Error{Code: -5, Message: "No such mempool or blockchain transaction"}
+	return &mocks.NodeClientMock{
+		MempoolEntryFunc: func(_ context.Context, txID string) (*models.MempoolEntry, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if !n.mempool[txID] {
+				return nil, &models.Error{Code: -5, Message: "Transaction not in mempool"}
+			}
+			return &models.MempoolEntry{}, nil
+		},
+		BlockCountFunc: func(context.Context) (uint32, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			return uint32(len(n.blocks) - 1), nil
+		},
+		BlockHashFunc: func(_ context.Context, height int) (string, error) {
+			return strconv.Itoa(height), nil
+		},
+		MerkleProofFunc: func(_ context.Context, hash, txID string, _ *models.OptsMerkleProof) (*bc.MerkleProof, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			height, err := strconv.Atoi(hash)
+			if err != nil || height >= len(n.blocks) {
+				return nil, &models.Error{Code: -5, Message: "Block not found"}
+			}
+			if !slices.Contains(n.blocks[height], txID) {
+				return nil, &models.Error{Code: -5, Message: "Transaction not found in provided block"}
+			}
+			return &bc.MerkleProof{TxOrID: txID, Target: hash}, nil
+		},
+		OutputFunc: func(_ context.Context, txID string, _ int, _ *models.OptsOutput) (*models.Output, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if !n.unspent[txID] {
+				return &models.Output{Output: &bt.Output{}}, nil
+			}
+			return &models.Output{Output: &bt.Output{}, BestBlock: "best"}, nil
+		},
+		RawTransactionFunc: func(_ context.Context, txID string) (*bt.Tx, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			mined := slices.ContainsFunc(n.blocks, func(txIDs []string) bool {
+				return slices.Contains(txIDs, txID)
+			})
+			if !n.mempool[txID] && !(n.txIndex && mined) {
+				return nil, notFound
+			}
+			return bt.NewTx(), nil
+		},
+		SendRawTransactionsFunc: func(_ context.Context,
+			params ...models.ParamsSendRawTransactions,
+		) (*models.SendRawTransactionsResponse, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			resp := &models.SendRawTransactionsResponse{}
+			var sent []string
+			for _, p := range params {
+				tx, err := bt.NewTxFromString(p.Hex)
+				if err != nil {
+					return nil, err
+				}
+				txID := tx.TxID()
+				sent = append(sent, txID)
+
+				reason, ok := n.rejected[txID]
+				switch {
+				case ok:
+					inv := models.SendRawTransactionsInvalid{TxID: txID, RejectReason: reason}
+					if code, r, found := strings.Cut(reason, ": "); found {
+						inv.RejectCode, _ = strconv.Atoi(code)
+						inv.RejectReason = r
+					}
+					resp.Invalid = append(resp.Invalid, inv)
+				case slices.Contains(n.evicted, txID):
+					resp.Evicted = append(resp.Evicted, txID)
+				default:
+					n.mempool[txID] = true
+				}
+			}
+			n.sent = append(n.sent, sent)
+			return resp, nil
+		},
+	}
+}
+
+func TestEngine_Check(t *testing.T) {
+	t.Parallel()
+
+	p := util.ChainedTx(t, 1000)
+	c := util.ChainedTx(t, 900, p)
+	g := util.ChainedTx(t, 800, c)
+	x := util.ChainedTx(t, 500)
+
+	t.Run("pending transactions are left alone", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		for _, tx := range []*bt.Tx{p, c, g, x} {
+			n.mempool[tx.TxID()] = true
+		}
+
+		e := rebroadcast.NewEngine(n.client())
+		require.NoError(t, e.Add(context.TODO(), g, x, c, p))
+		r, err := e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []string{g.TxID(), x.TxID(), c.TxID(), p.TxID()}, r.Pending)
+		assert.Empty(t, n.sent)
+	})
+
+	t.Run("missing transactions are resubmitted parents first", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		e := rebroadcast.NewEngine(n.client())
+		require.NoError(t, e.Add(context.TODO(), g, x, c, p))
+		r, err := e.Check(context.TODO())
+		require.NoError(t, err)
+
+		exp := []string{p.TxID(), c.TxID(), g.TxID(), x.TxID()}
+		assert.Equal(t, [][]string{exp}, n.sent)
+		assert.Equal(t, exp, r.Resubmitted)
+
+		// Now back in the mempool, they are held until mined.
+		r, err = e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, r.Pending, 4)
+		assert.Len(t, n.sent, 1)
+	})
+
+	t.Run("pending ancestors are sent alongside", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.mempool[p.TxID()] = true
+		n.mempool[x.TxID()] = true
+
+		e := rebroadcast.NewEngine(n.client())
+		require.NoError(t, e.Add(context.TODO(), p, c, x))
+		r, err := e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{p.TxID(), c.TxID()}}, n.sent)
+		assert.Equal(t, []string{p.TxID(), x.TxID()}, r.Pending)
+		assert.Equal(t, []string{c.TxID()}, r.Resubmitted)
+	})
+
+	t.Run("mined transactions are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.txIndex = true
+		n.mine(p.TxID())
+
+		e := rebroadcast.NewEngine(n.client())
+		require.NoError(t, e.Add(context.TODO(), p))
+		r, err := e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []string{p.TxID()}, r.Mined)
+
+		txs, err := e.Txs(context.TODO())
+		require.NoError(t, err)
+		assert.Empty(t, txs)
+	})
+
+	t.Run("without a transaction index, mined transactions are found in blocks", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.mempool[p.TxID()] = true
+		n.mempool[x.TxID()] = true
+
+		var failed []*rebroadcast.Failure
+		e := rebroadcast.NewEngine(n.client(), rebroadcast.WithFailureHandler(
+			func(_ context.Context, f *rebroadcast.Failure) {
+				failed = append(failed, f)
+			}))
+		require.NoError(t, e.Add(context.TODO(), p, x))
+		r, err := e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, r.Pending, 2)
+
+		// The outputs of p are since spent, while x is double spent.
+		n.mine()
+		n.mine(p.TxID())
+		delete(n.mempool, x.TxID())
+		n.mine()
+		n.rejected[p.TxID()] = "missing-inputs"
+		n.rejected[x.TxID()] = "missing-inputs"
+
+		r, err = e.Check(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []string{p.TxID()}, r.Mined)
+		require.Len(t, r.Failed, 1)
+		assert.Equal(t, x.TxID(), r.Failed[0].Tx.TxID())
+		require.ErrorIs(t, r.Failed[0].Err, models.ErrMissingInputs)
+		assert.Equal(t, r.Failed, failed)
+	})
+
+	t.Run("transactions not seen since a restart are left unresolved", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.mine(p.TxID())
+		n.mine()
+		n.mine()
+		n.rejected[p.TxID()] = "missing-inputs"
+		n.rejected[x.TxID()] = "missing-inputs"
+
+		e := rebroadcast.NewEngine(n.client(), rebroadcast.WithSearchDepth(2))
+		require.NoError(t, e.Add(context.TODO(), p, x))
+		r, err := e.Check(context.TODO())
+		require.ErrorIs(t, err, rebroadcast.ErrUnresolved)
+		assert.Empty(t, r.Mined)
+		assert.Empty(t, r.Failed)
+
+		// Mined beyond the search depth, p is told apart by its unspent outputs.
+		n.unspent[p.TxID()] = true
+		r, err = e.Check(context.TODO())
+		require.ErrorIs(t, err, rebroadcast.ErrUnresolved)
+		assert.Equal(t, []string{p.TxID()}, r.Mined)
+
+		txs, err := e.Txs(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []*bt.Tx{x}, txs)
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.rejected[p.TxID()] = "66: insufficient priority"
+		n.rejected[c.TxID()] = "missing-inputs"
+		n.rejected[x.TxID()] = "258: txn-mempool-conflict"
+
+		var failed []*rebroadcast.Failure
+		e := rebroadcast.NewEngine(n.client(), rebroadcast.WithFailureHandler(
+			func(_ context.Context, f *rebroadcast.Failure) {
+				failed = append(failed, f)
+			}))
+		require.NoError(t, e.Add(context.TODO(), p, c, x))
+
+		// The child of a transaction paying too low a fee is tried again alongside it.
+		r, err := e.Check(context.TODO())
+		require.ErrorIs(t, err, models.ErrInsufficientFee)
+		require.ErrorIs(t, err, models.ErrMissingInputs)
+		require.Len(t, r.Failed, 1)
+		assert.Equal(t, x.TxID(), r.Failed[0].Tx.TxID())
+		require.ErrorIs(t, r.Failed[0].Err, models.ErrTxConflict)
+		assert.Equal(t, r.Failed, failed)
+
+		txs, err := e.Txs(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []*bt.Tx{p, c}, txs)
+
+		// Once the parent is double spent, the child fails with it.
+		n.rejected[p.TxID()] = "258: txn-mempool-conflict"
+		r, err = e.Check(context.TODO())
+		require.NoError(t, err)
+		require.Len(t, r.Failed, 2)
+		assert.Equal(t, p.TxID(), r.Failed[0].Tx.TxID())
+		assert.Equal(t, c.TxID(), r.Failed[1].Tx.TxID())
+		require.ErrorIs(t, r.Failed[1].Err, models.ErrMissingInputs)
+	})
+
+	t.Run("evictions are tried again", func(t *testing.T) {
+		t.Parallel()
+
+		n := newNode()
+		n.evicted = []string{x.TxID()}
+
+		e := rebroadcast.NewEngine(n.client())
+		require.NoError(t, e.Add(context.TODO(), x))
+		_, err := e.Check(context.TODO())
+		require.ErrorIs(t, err, rebroadcast.ErrEvicted)
+
+		txs, err := e.Txs(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, txs, 1)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "txs.json")
+	p := util.ChainedTx(t, 1000)
+	c := util.ChainedTx(t, 900, p)
+
+	txs, err := rebroadcast.NewFileStore(path).Load(context.TODO())
+	require.NoError(t, err)
+	assert.Nil(t, txs)
+
+	n := newNode()
+	e := rebroadcast.NewEngine(n.client(), rebroadcast.WithStore(rebroadcast.NewFileStore(path)))
+	require.NoError(t, e.Add(context.TODO(), p, c))
+
+	e = rebroadcast.NewEngine(n.client(), rebroadcast.WithStore(rebroadcast.NewFileStore(path)))
+	require.NoError(t, e.Remove(context.TODO(), p.TxID()))
+
+	txs, err = rebroadcast.NewFileStore(path).Load(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, c.TxID(), txs[0].TxID())
+
+	// Stores sharing the path save without clobbering one another's temporary files.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			assert.NoError(t, rebroadcast.NewFileStore(path).Save(context.TODO(), []*bt.Tx{p, c}))
+		})
+	}
+	wg.Wait()
+
+	txs, err = rebroadcast.NewFileStore(path).Load(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, txs, 2)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,132 @@
+package rebroadcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+type memoryStore struct {
+	mu  sync.Mutex
+	txs []*bt.Tx
+}
+
+// NewMemoryStore returns a Store holding the transactions in memory, which are lost on
+// restart.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+// Load returns the transactions.
+func (m *memoryStore) Load(context.Context) ([]*bt.Tx, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.txs), nil
+}
+
+// Save stores the transactions.
+func (m *memoryStore) Save(_ context.Context, txs []*bt.Tx) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.txs = slices.Clone(txs)
+	return nil
+}
+
+type fileStore struct {
+	path string
+}
+
+// NewFileStore returns a Store keeping the transactions as a JSON array of raw hex in the
+// file at path. The file is replaced atomically on each save, and synced to disk before and
+// after, so a crash or power loss leaves either the old or the new transactions.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+// Load reads the transactions from the file, returning nil if it does not exist.
+func (f *fileStore) Load(context.Context) ([]*bt.Tx, error) {
+	bb, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hexes []string
+	if err = json.Unmarshal(bb, &hexes); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*bt.Tx, 0, len(hexes))
+	for _, h := range hexes {
+		tx, err := bt.NewTxFromString(h)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// Save writes the transactions to the file.
+func (f *fileStore) Save(_ context.Context, txs []*bt.Tx) error {
+	hexes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		hexes = append(hexes, tx.String())
+	}
+
+	bb, err := json.Marshal(hexes)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(f.path, bb)
+}
+
+// writeFile replaces the file at path with bb, writing them to a temporary file in the same
+// directory which is synced to disk before being renamed over it. The directory is then synced
+// so the rename survives a power loss.
+func writeFile(path string, bb []byte) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(bb); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	d, err := os.Open(dir) //nolint:gosec // G304: the directory of the store's own file
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+	return d.Sync()
+}
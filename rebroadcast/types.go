@@ -0,0 +1,48 @@
+package rebroadcast
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn"
+)
+
+// Client the node commands the engine uses to check on and resubmit transactions.
+type Client interface {
+	bn.BlockChainClient
+	bn.TransactionClient
+}
+
+// Store persists the unconfirmed transactions of the engine, so they survive a restart.
+// Load returns nil when nothing has been saved.
+type Store interface {
+	Load(ctx context.Context) ([]*bt.Tx, error)
+	Save(ctx context.Context, txs []*bt.Tx) error
+}
+
+// Failure a transaction the node rejected for good on resubmission, such as one whose
+// inputs were double spent. Err is a *models.Error holding the reject code and reason, so
+// can be checked with errors.Is against the errors of the models package.
+type Failure struct {
+	Tx  *bt.Tx
+	Err error
+}
+
+// Report the outcome of a check of the unconfirmed transactions.
+type Report struct {
+	// Pending the transactions still in the mempool of the node.
+	Pending []string
+	// Mined the transactions found mined, which are no longer held.
+	Mined []string
+	// Resubmitted the transactions missing from the mempool which were accepted again.
+	Resubmitted []string
+	// Failed the transactions rejected for good, which are no longer held.
+	Failed []*Failure
+}
+
+// FailureFunc a func in which transactions rejected for good are passed to.
+type FailureFunc func(ctx context.Context, f *Failure)
+
+// ErrorFunc a func in which errors checking transactions in the background are passed to.
+type ErrorFunc func(ctx context.Context, err error)
@@ -0,0 +1,8 @@
+package rest
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrMalformed = errors.New("malformed response")
+)
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+)
+
+type clientCfg struct {
+	host       string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// ClientOptFunc option func.
+type ClientOptFunc func(c *clientCfg)
+
+// WithHost set the host of the node, defaulting to `http://localhost:8332`. The node must be
+// started with `-rest`.
+func WithHost(host string) ClientOptFunc {
+	return func(c *clientCfg) {
+		c.host = host
+	}
+}
+
+// WithTimeout set the timeout for the http client. It does not apply to BlockStream, which is
+// bounded by its context alone.
+func WithTimeout(timeout time.Duration) ClientOptFunc {
+	return func(c *clientCfg) {
+		c.timeout = timeout
+	}
+}
+
+// WithHTTPClient set the http client requests are sent with, overriding WithTimeout.
+func WithHTTPClient(hc *http.Client) ClientOptFunc {
+	return func(c *clientCfg) {
+		c.httpClient = hc
+	}
+}
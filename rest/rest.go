@@ -0,0 +1,276 @@
+// Package rest interfaces the REST API of a bitcoin node, which serves blocks, transactions
+// and headers in binary without the overhead of JSON-RPC. Results are returned as the same
+// models, bt and bc types as the JSON-RPC client.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Client interfaces the REST API of a bitcoin node.
+type Client interface {
+	RawTransaction(ctx context.Context, txID string) (*bt.Tx, error)
+	Block(ctx context.Context, hash string) (*models.Block, error)
+	BlockHex(ctx context.Context, hash string) (string, error)
+	BlockStream(ctx context.Context, hash string) (*models.BlockStream, error)
+	BlockHeader(ctx context.Context, hash string) (*models.BlockHeader, error)
+	BlockHeaders(ctx context.Context, hash string, count int) ([]*bc.BlockHeader, error)
+	ChainInfo(ctx context.Context) (*models.ChainInfo, error)
+	RawMempool(ctx context.Context) (models.MempoolTxs, error)
+	Outputs(ctx context.Context, checkMempool bool, outpoints ...Outpoint) (*UTXOs, error)
+}
+
+type client struct {
+	host string
+	c    *http.Client
+}
+
+// NewClient returns a client of the REST API of a node, configured via the provided opt funcs.
+func NewClient(oo ...ClientOptFunc) Client {
+	cfg := &clientCfg{
+		host:    "http://localhost:8332",
+		timeout: 30 * time.Second,
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+	if cfg.httpClient == nil {
+		cfg.httpClient = &http.Client{Timeout: cfg.timeout}
+	}
+
+	return &client{
+		host: strings.TrimSuffix(cfg.host, "/"),
+		c:    cfg.httpClient,
+	}
+}
+
+// RawTransaction retrieves a transaction by its ID. The node must be started with `-txindex`
+// to serve transactions which are not in the mempool.
+func (c *client) RawTransaction(ctx context.Context, txID string) (*bt.Tx, error) {
+	bb, err := c.get(ctx, "/rest/tx/"+txID+".bin", models.ErrTxNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return bt.NewTxFromBytes(bb)
+}
+
+// Block returns the block for a given block hash, with its transactions decoded.
+func (c *client) Block(ctx context.Context, hash string) (*models.Block, error) {
+	resp := models.Block{BlockHeader: models.BlockHeader{BlockHeader: &bc.BlockHeader{}}}
+	return &resp, c.getJSON(ctx, "/rest/block/"+hash+".json", &resp, models.ErrBlockNotFound)
+}
+
+// BlockHex returns the raw hex representation of a block given its hash.
+func (c *client) BlockHex(ctx context.Context, hash string) (string, error) {
+	bb, err := c.get(ctx, "/rest/block/"+hash+".hex", models.ErrBlockNotFound)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(bb)), nil
+}
+
+// BlockStream returns the block for a given block hash, decoded incrementally as the binary
+// response arrives. The stream must be closed. It is bounded by ctx rather than the client
+// timeout.
+func (c *client) BlockStream(ctx context.Context, hash string) (*models.BlockStream, error) {
+	hc := *c.c
+	hc.Timeout = 0
+
+	resp, err := c.do(ctx, &hc, "/rest/block/"+hash+".bin", models.ErrBlockNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewBlockStream(resp.Body)
+}
+
+// BlockHeader returns the block header for a given block hash.
+func (c *client) BlockHeader(ctx context.Context, hash string) (*models.BlockHeader, error) {
+	var resp []json.RawMessage
+	if err := c.getJSON(ctx, "/rest/headers/1/"+hash+".json", &resp, models.ErrBlockNotFound); err != nil {
+		return nil, err
+	}
+	if len(resp) == 0 {
+		return nil, models.ErrBlockNotFound
+	}
+
+	hdr := models.BlockHeader{BlockHeader: &bc.BlockHeader{}}
+	return &hdr, json.Unmarshal(resp[0], &hdr)
+}
+
+// BlockHeaders returns up to count headers of the best chain, starting with the given block
+// hash.
+func (c *client) BlockHeaders(ctx context.Context, hash string, count int) ([]*bc.BlockHeader, error) {
+	bb, err := c.get(ctx, "/rest/headers/"+strconv.Itoa(count)+"/"+hash+".bin", models.ErrBlockNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if len(bb)%80 != 0 {
+		return nil, fmt.Errorf("%w: %d bytes is not a whole number of headers", ErrMalformed, len(bb))
+	}
+
+	hh := make([]*bc.BlockHeader, 0, len(bb)/80)
+	for i := 0; i < len(bb); i += 80 {
+		h, err := bc.NewBlockHeaderFromBytes(bb[i : i+80])
+		if err != nil {
+			return nil, err
+		}
+		hh = append(hh, h)
+	}
+
+	return hh, nil
+}
+
+// ChainInfo returns information about the current state of the blockchain.
+func (c *client) ChainInfo(ctx context.Context) (*models.ChainInfo, error) {
+	var resp models.ChainInfo
+	return &resp, c.getJSON(ctx, "/rest/chaininfo.json", &resp, nil)
+}
+
+// RawMempool returns the transactions in the mempool.
+func (c *client) RawMempool(ctx context.Context) (models.MempoolTxs, error) {
+	var resp models.MempoolTxs
+	return resp, c.getJSON(ctx, "/rest/mempool/contents.json", &resp, nil)
+}
+
+// Outputs queries whether each outpoint is unspent, returning those which are. Outputs spent
+// or created in the mempool are taken into account when checkMempool is true.
+func (c *client) Outputs(ctx context.Context, checkMempool bool, outpoints ...Outpoint) (*UTXOs, error) {
+	var sb strings.Builder
+	sb.WriteString("/rest/getutxos")
+	if checkMempool {
+		sb.WriteString("/checkmempool")
+	}
+	for _, o := range outpoints {
+		sb.WriteString("/" + o.TxID + "-" + strconv.FormatUint(uint64(o.Vout), 10))
+	}
+	sb.WriteString(".json")
+
+	var resp struct {
+		ChainHeight  uint32 `json:"chainHeight"`
+		ChainTipHash string `json:"chaintipHash"`
+		Bitmap       string `json:"bitmap"`
+		UTXOs        []struct {
+			Height       uint32  `json:"height"`
+			Value        float64 `json:"value"`
+			ScriptPubKey struct {
+				Hex string `json:"hex"`
+			} `json:"scriptPubKey"`
+		} `json:"utxos"`
+	}
+	if err := c.getJSON(ctx, sb.String(), &resp, nil); err != nil {
+		return nil, err
+	}
+	if len(resp.Bitmap) != len(outpoints) {
+		return nil, fmt.Errorf("%w: bitmap of %d outpoints for %d queried", ErrMalformed, len(resp.Bitmap), len(outpoints))
+	}
+
+	u := &UTXOs{
+		ChainHeight:  resp.ChainHeight,
+		ChainTipHash: resp.ChainTipHash,
+		Unspent:      make([]bool, len(outpoints)),
+		UTXOs:        make([]*UTXO, 0, len(resp.UTXOs)),
+	}
+	for i, o := range outpoints {
+		if resp.Bitmap[i] != '1' {
+			continue
+		}
+		if len(u.UTXOs) == len(resp.UTXOs) {
+			return nil, fmt.Errorf("%w: fewer utxos than set in bitmap", ErrMalformed)
+		}
+		out := resp.UTXOs[len(u.UTXOs)]
+
+		txID, err := chainhash.NewHashFromStr(o.TxID)
+		if err != nil {
+			return nil, err
+		}
+		script, err := bscript.NewFromHexString(out.ScriptPubKey.Hex)
+		if err != nil {
+			return nil, err
+		}
+
+		u.Unspent[i] = true
+		u.UTXOs = append(u.UTXOs, &UTXO{
+			UTXO: &bt.UTXO{
+				TxIDHash:      txID,
+				Vout:          o.Vout,
+				LockingScript: script,
+				Satoshis:      uint64(math.Round(out.Value * 1e8)),
+			},
+			Height: out.Height,
+		})
+	}
+
+	return u, nil
+}
+
+// getJSON requests the path, decoding the JSON response into out.
+func (c *client) getJSON(ctx context.Context, path string, out interface{}, notFound error) error {
+	bb, err := c.get(ctx, path, notFound)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bb, out)
+}
+
+// get requests the path, returning the response body.
+func (c *client) get(ctx context.Context, path string, notFound error) ([]byte, error) {
+	resp, err := c.do(ctx, c.c, path, notFound)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return io.ReadAll(resp.Body)
+}
+
+// do requests the path, returning the response with its body unread. An error status is
+// returned as a *models.HTTPError, wrapped by notFound when the node replies not found.
+func (c *client) do(ctx context.Context, hc *http.Client, path string, notFound error) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.host+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	bb, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	httpErr := &models.HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       string(bytes.TrimSpace(bb)),
+	}
+	if resp.StatusCode == http.StatusNotFound && notFound != nil {
+		return nil, fmt.Errorf("%w: %w", notFound, httpErr)
+	}
+
+	return nil, httpErr
+}
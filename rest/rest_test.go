@@ -0,0 +1,142 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/rest"
+)
+
+const (
+	header = "0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+	txHex  = "0200000001fbb877c83aaf682f74611628b0088254c8094fff9cf6328ed969c587112b8fc90000000000feffffff025e2e1a1e010000001976a91401becd83278806a62cd87bed129faa72af38a0d588ac00e1f505000000001976a91467e701e630adaee761583a894b53d4356028ca0b88ac00000000"
+	txID   = "b7a3a61e3ba6ac6f5d7bb1f4fb5a7fd5b9c0fcaf7b5e6a1e6eeae2a52ab5fd3c"
+)
+
+func testServer(t *testing.T, routes map[string]string) rest.Client {
+	t.Helper()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(r.URL.Path + " not found\r\n"))
+			return
+		}
+
+		bb := []byte(body)
+		if strings.HasSuffix(r.URL.Path, ".bin") {
+			var err error
+			bb, err = hex.DecodeString(body)
+			assert.NoError(t, err)
+		}
+		_, _ = w.Write(bb)
+	}))
+	t.Cleanup(svr.Close)
+
+	return rest.NewClient(rest.WithHost(svr.URL))
+}
+
+func TestClient_RawTransaction(t *testing.T) {
+	t.Parallel()
+
+	c := testServer(t, map[string]string{
+		"/rest/tx/abc.bin": txHex,
+	})
+
+	tx, err := c.RawTransaction(context.TODO(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, txHex, tx.String())
+
+	_, err = c.RawTransaction(context.TODO(), "def")
+	require.ErrorIs(t, err, models.ErrTxNotFound)
+	var httpErr *models.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func TestClient_Blocks(t *testing.T) {
+	t.Parallel()
+
+	c := testServer(t, map[string]string{
+		"/rest/block/abc.bin":       header + "01" + txHex,
+		"/rest/block/abc.hex":       header + "01" + txHex + "\n",
+		"/rest/headers/2/abc.bin":   header + header,
+		"/rest/headers/1/abc.json":  `[{"hash":"abc","height":5,"version":1,"merkleroot":"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b","previousblockhash":"","time":1231006505,"nonce":2083236893,"bits":"1d00ffff"}]`,
+		"/rest/headers/1/none.json": `[]`,
+	})
+
+	blk, err := c.BlockStream(context.TODO(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, header, hex.EncodeToString(blk.Header.Bytes()))
+	for tx, err := range blk.Txs() {
+		require.NoError(t, err)
+		assert.Equal(t, txHex, tx.String())
+	}
+	require.NoError(t, blk.Close())
+
+	blkHex, err := c.BlockHex(context.TODO(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, header+"01"+txHex, blkHex)
+
+	hh, err := c.BlockHeaders(context.TODO(), "abc", 2)
+	require.NoError(t, err)
+	require.Len(t, hh, 2)
+	assert.Equal(t, header, hex.EncodeToString(hh[1].Bytes()))
+
+	hdr, err := c.BlockHeader(context.TODO(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", hdr.Hash)
+	assert.Equal(t, uint64(5), hdr.Height)
+
+	_, err = c.BlockHeader(context.TODO(), "none")
+	require.ErrorIs(t, err, models.ErrBlockNotFound)
+
+	_, err = c.BlockStream(context.TODO(), "def")
+	require.ErrorIs(t, err, models.ErrBlockNotFound)
+}
+
+func TestClient_Outputs(t *testing.T) {
+	t.Parallel()
+
+	c := testServer(t, map[string]string{
+		"/rest/getutxos/checkmempool/" + txID + "-0/" + txID + "-1/" + txID + "-2.json": `{
+			"chainHeight": 100,
+			"chaintipHash": "abc",
+			"bitmap": "101",
+			"utxos": [
+				{"height": 99, "value": 48.2865, "scriptPubKey": {"hex": "76a91401becd83278806a62cd87bed129faa72af38a0d588ac"}},
+				{"height": 2147483647, "value": 1, "scriptPubKey": {"hex": "76a91467e701e630adaee761583a894b53d4356028ca0b88ac"}}
+			]
+		}`,
+	})
+
+	u, err := c.Outputs(context.TODO(), true,
+		rest.Outpoint{TxID: txID, Vout: 0},
+		rest.Outpoint{TxID: txID, Vout: 1},
+		rest.Outpoint{TxID: txID, Vout: 2},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(100), u.ChainHeight)
+	assert.Equal(t, []bool{true, false, true}, u.Unspent)
+	require.Len(t, u.UTXOs, 2)
+	assert.Equal(t, txID, u.UTXOs[0].TxIDStr())
+	assert.Equal(t, uint32(0), u.UTXOs[0].Vout)
+	assert.Equal(t, uint64(4828650000), u.UTXOs[0].Satoshis)
+	assert.Equal(t, uint32(99), u.UTXOs[0].Height)
+	assert.Equal(t, uint32(2), u.UTXOs[1].Vout)
+	assert.Equal(t, uint64(100000000), u.UTXOs[1].Satoshis)
+	assert.Equal(t, "76a91467e701e630adaee761583a894b53d4356028ca0b88ac", u.UTXOs[1].LockingScriptHexString())
+
+	_, err = c.Outputs(context.TODO(), false, rest.Outpoint{TxID: txID, Vout: 0})
+	require.Error(t, err)
+}
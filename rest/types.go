@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+)
+
+// Outpoint an output of a transaction.
+type Outpoint struct {
+	TxID string
+	Vout uint32
+}
+
+// UTXOs the result of querying a set of outpoints, as of the chain tip.
+type UTXOs struct {
+	ChainHeight  uint32
+	ChainTipHash string
+	// Unspent whether each outpoint queried is unspent, in the order queried.
+	Unspent []bool
+	// UTXOs the unspent outpoints, in the order queried.
+	UTXOs []*UTXO
+}
+
+// UTXO an unspent output, along with the height of the block it was mined in.
+type UTXO struct {
+	*bt.UTXO
+
+	Height uint32
+}
@@ -11,7 +11,9 @@ import (
 
 // NodeClient interfaces interacting with all commands on a bitcoin node.
 type NodeClient interface {
+	BatchClient
 	BlockChainClient
+	CacheClient
 	ControlClient
 	MiningClient
 	NetworkClient
@@ -27,6 +29,7 @@ type positionalOptionalArgs interface {
 
 type client struct {
 	rpc       service.RPC
+	cache     *service.Cache
 	isMainnet bool
 }
 
@@ -50,17 +53,36 @@ func NewNodeClient(oo ...BitcoinClientOptFunc) NodeClient {
 		}
 	}
 
+	httpClient := &http.Client{Timeout: opts.timeout}
 	rpc := service.NewRPC(&config.RPC{
 		Username: opts.username,
 		Password: opts.password,
 		Host:     opts.host,
-	}, &http.Client{Timeout: opts.timeout})
+	}, httpClient)
+	if len(opts.hosts) > 0 {
+		nodes := make([]service.RPC, len(opts.hosts))
+		for i, host := range opts.hosts {
+			nodes[i] = service.NewRPC(&config.RPC{
+				Username: opts.username,
+				Password: opts.password,
+				Host:     host,
+			}, httpClient)
+		}
+		rpc = service.NewPool(nodes, &opts.pool)
+	}
+	if opts.retry != nil && opts.retry.MaxAttempts > 1 {
+		rpc = service.NewRetry(rpc, opts.retry)
+	}
+
+	var cache *service.Cache
 	if opts.cache {
-		rpc = service.NewCache(rpc)
+		cache = service.NewCache(rpc, opts.cacheOpts...)
+		rpc = cache
 	}
 
 	return &client{
 		rpc:       rpc,
+		cache:     cache,
 		isMainnet: opts.isMainnet,
 	}
 }
@@ -0,0 +1,8 @@
+package submit
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrEvicted = errors.New("transaction evicted from mempool")
+)
@@ -0,0 +1,57 @@
+package submit
+
+import "github.com/bsv-blockchain/go-bn/models"
+
+// DefaultMaxTxs the default number of transactions submitted in a single request.
+const DefaultMaxTxs = 1000
+
+// DefaultMaxSize the default size in bytes of the transactions submitted in a single request.
+// They are hex encoded, so the request is over twice as large.
+const DefaultMaxSize = 32 << 20
+
+type submitterCfg struct {
+	maxTxs  int
+	maxSize int
+	params  models.ParamsSendRawTransactions
+}
+
+// SubmitterOptFunc option func.
+type SubmitterOptFunc func(c *submitterCfg)
+
+// WithMaxTxs set the number of transactions submitted in a single request. Defaults to
+// DefaultMaxTxs.
+func WithMaxTxs(n int) SubmitterOptFunc {
+	return func(c *submitterCfg) {
+		c.maxTxs = n
+	}
+}
+
+// WithMaxSize set the size in bytes of the transactions submitted in a single request.
+// Transactions larger than it are submitted alone. Defaults to DefaultMaxSize.
+func WithMaxSize(n int) SubmitterOptFunc {
+	return func(c *submitterCfg) {
+		c.maxSize = n
+	}
+}
+
+// WithAllowHighFees set whether transactions paying absurdly high fees are accepted.
+func WithAllowHighFees(allow bool) SubmitterOptFunc {
+	return func(c *submitterCfg) {
+		c.params.AllowHighFees = allow
+	}
+}
+
+// WithDontCheckFee set whether the node skips checking transactions pay its minimum fee.
+func WithDontCheckFee(dontCheck bool) SubmitterOptFunc {
+	return func(c *submitterCfg) {
+		c.params.DontCheckFee = dontCheck
+	}
+}
+
+// WithListUnconfirmedAncestors set whether the node lists the unconfirmed ancestors of each
+// transaction, returned in Result.Unconfirmed.
+func WithListUnconfirmedAncestors(list bool) SubmitterOptFunc {
+	return func(c *submitterCfg) {
+		c.params.ListUnconfirmedAncestors = list
+	}
+}
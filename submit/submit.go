@@ -0,0 +1,138 @@
+// Package submit sends packages of chained transactions to a bitcoin node, ordered so each
+// follows those it spends from, and reports the outcome of each transaction.
+package submit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/internal/txgraph"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Submitter sends packages of transactions.
+type Submitter interface {
+	Submit(ctx context.Context, txs ...*bt.Tx) ([]*Result, error)
+}
+
+type submitter struct {
+	client bn.TransactionClient
+	cfg    *submitterCfg
+}
+
+// NewSubmitter returns a submitter sending transactions to the node behind client,
+// configured via the provided opt funcs.
+func NewSubmitter(client bn.TransactionClient, oo ...SubmitterOptFunc) Submitter {
+	cfg := &submitterCfg{
+		maxTxs:  DefaultMaxTxs,
+		maxSize: DefaultMaxSize,
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+
+	return &submitter{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Submit sends the transactions with `sendrawtransactions`, sorted so each follows those it
+// spends from, and split into chunks within the limits of the node. The chunks are sent one
+// after another, so a transaction is never sent before its parents.
+//
+// A result is returned for each transaction, in the order given. Should a chunk fail to send,
+// the error is returned alongside the results, those of the transactions not yet sent having
+// StatusNotSent.
+func (s *submitter) Submit(ctx context.Context, txs ...*bt.Tx) ([]*Result, error) {
+	byID := make(map[string]*Result, len(txs))
+	results := make([]*Result, len(txs))
+	for i, tx := range txs {
+		txID := tx.TxID()
+		r, ok := byID[txID]
+		if !ok {
+			r = &Result{TxID: txID, Tx: tx}
+			byID[txID] = r
+		}
+		results[i] = r
+	}
+
+	for _, chunk := range s.chunks(txgraph.Sort(txs)) {
+		params := make([]models.ParamsSendRawTransactions, 0, len(chunk))
+		for _, tx := range chunk {
+			p := s.cfg.params
+			p.Hex = tx.String()
+			params = append(params, p)
+		}
+
+		resp, err := s.client.SendRawTransactions(ctx, params...)
+		if err != nil {
+			return results, fmt.Errorf("failed to submit %d transactions: %w", len(chunk), err)
+		}
+		for _, tx := range chunk {
+			apply(byID[tx.TxID()], resp)
+		}
+	}
+
+	return results, nil
+}
+
+// chunks splits the transactions into chunks of at most maxTxs transactions and maxSize
+// bytes. A transaction larger than maxSize is sent alone.
+func (s *submitter) chunks(txs []*bt.Tx) [][]*bt.Tx {
+	var chunks [][]*bt.Tx
+	var chunk []*bt.Tx
+	var size int
+	for _, tx := range txs {
+		n := tx.Size()
+		if len(chunk) > 0 && (len(chunk) >= s.cfg.maxTxs || size+n > s.cfg.maxSize) {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+		chunk = append(chunk, tx)
+		size += n
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// apply sets the outcome of the transaction from the response to the chunk it was sent in.
+func apply(r *Result, resp *models.SendRawTransactionsResponse) {
+	switch {
+	case slices.Contains(resp.Known, r.TxID):
+		r.Status = StatusKnown
+	case slices.Contains(resp.Evicted, r.TxID):
+		r.Status = StatusEvicted
+		r.Err = ErrEvicted
+	default:
+		r.Status = StatusAccepted
+		for i := range resp.Invalid {
+			inv := &resp.Invalid[i]
+			if inv.TxID != r.TxID {
+				continue
+			}
+			// Transactions already in the mempool are known, whichever way the node says so.
+			if err := inv.Err(); errors.Is(err, models.ErrTxAlreadyKnown) {
+				r.Status = StatusKnown
+			} else {
+				r.Status = StatusInvalid
+				r.Invalid = inv
+				r.Err = err
+			}
+		}
+	}
+
+	for i := range resp.Unconfirmed {
+		if u := &resp.Unconfirmed[i]; u.TxID == r.TxID {
+			r.Unconfirmed = u
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package submit_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/submit"
+	"github.com/bsv-blockchain/go-bn/testing/util"
+)
+
+// node returns a client recording the txids of each chunk sent, replying with the response
+// built by fn.
+func node(t *testing.T, sent *[][]string,
+	fn func(txIDs []string) (*models.SendRawTransactionsResponse, error),
+) *mocks.TransactionClientMock {
+	return &mocks.TransactionClientMock{
+		SendRawTransactionsFunc: func(_ context.Context,
+			params ...models.ParamsSendRawTransactions,
+		) (*models.SendRawTransactionsResponse, error) {
+			txIDs := make([]string, 0, len(params))
+			for _, p := range params {
+				tx, err := bt.NewTxFromString(p.Hex)
+				require.NoError(t, err)
+				txIDs = append(txIDs, tx.TxID())
+			}
+			*sent = append(*sent, txIDs)
+			return fn(txIDs)
+		},
+	}
+}
+
+func accept([]string) (*models.SendRawTransactionsResponse, error) {
+	return &models.SendRawTransactionsResponse{}, nil
+}
+
+func TestSubmitter_Submit(t *testing.T) {
+	t.Parallel()
+
+	p := util.ChainedTx(t, 1000)
+	c1 := util.ChainedTx(t, 900, p)
+	c2 := util.ChainedTx(t, 800, c1)
+	x := util.ChainedTx(t, 500)
+	g := util.ChainedTx(t, 400, c2, x)
+
+	t.Run("parents are sent first", func(t *testing.T) {
+		t.Parallel()
+
+		var sent [][]string
+		s := submit.NewSubmitter(node(t, &sent, accept))
+		rr, err := s.Submit(context.TODO(), g, c2, x, p, c1)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{{p.TxID(), c1.TxID(), c2.TxID(), x.TxID(), g.TxID()}}, sent)
+		require.Len(t, rr, 5)
+		for i, tx := range []*bt.Tx{g, c2, x, p, c1} {
+			assert.Equal(t, tx.TxID(), rr[i].TxID)
+			assert.Equal(t, submit.StatusAccepted, rr[i].Status)
+			assert.True(t, rr[i].OK())
+		}
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			opts []submit.SubmitterOptFunc
+			exp  [][]string
+		}{
+			"max txs": {
+				opts: []submit.SubmitterOptFunc{submit.WithMaxTxs(2)},
+				exp: [][]string{
+					{p.TxID(), c1.TxID()},
+					{c2.TxID(), x.TxID()},
+					{g.TxID()},
+				},
+			},
+			"max size": {
+				opts: []submit.SubmitterOptFunc{submit.WithMaxSize(p.Size() * 3)},
+				exp: [][]string{
+					{p.TxID(), c1.TxID(), c2.TxID()},
+					{x.TxID(), g.TxID()},
+				},
+			},
+			"oversized txs are sent alone": {
+				opts: []submit.SubmitterOptFunc{submit.WithMaxSize(1)},
+				exp: [][]string{
+					{p.TxID()},
+					{c1.TxID()},
+					{c2.TxID()},
+					{x.TxID()},
+					{g.TxID()},
+				},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				var sent [][]string
+				rr, err := submit.NewSubmitter(node(t, &sent, accept), test.opts...).
+					Submit(context.TODO(), p, c1, c2, x, g)
+				require.NoError(t, err)
+				assert.Equal(t, test.exp, sent)
+				assert.Len(t, rr, 5)
+			})
+		}
+	})
+
+	t.Run("results", func(t *testing.T) {
+		t.Parallel()
+
+		var params []models.ParamsSendRawTransactions
+		client := &mocks.TransactionClientMock{
+			SendRawTransactionsFunc: func(_ context.Context,
+				pp ...models.ParamsSendRawTransactions,
+			) (*models.SendRawTransactionsResponse, error) {
+				params = pp
+				return &models.SendRawTransactionsResponse{
+					Known:   []string{p.TxID()},
+					Evicted: []string{c2.TxID()},
+					Invalid: []models.SendRawTransactionsInvalid{{
+						TxID:         x.TxID(),
+						RejectCode:   258,
+						RejectReason: "txn-mempool-conflict",
+					}, {
+						TxID:         g.TxID(),
+						RejectCode:   257,
+						RejectReason: "txn-already-known",
+					}},
+					Unconfirmed: []models.SendRawTransactionsUnconfirmed{{
+						TxID: c1.TxID(),
+						Ancestors: []models.SendRawTransactionsAncestor{{
+							TxID: p.TxID(),
+							Vin:  []models.SendRawTransactionsInput{{TxID: strings.Repeat("ab", 32)}},
+						}},
+					}},
+				}, nil
+			},
+		}
+
+		s := submit.NewSubmitter(client,
+			submit.WithAllowHighFees(true),
+			submit.WithListUnconfirmedAncestors(true),
+		)
+		rr, err := s.Submit(context.TODO(), p, c1, c2, x, g, p)
+		require.NoError(t, err)
+		require.Len(t, params, 5)
+		assert.True(t, params[0].AllowHighFees)
+		assert.True(t, params[0].ListUnconfirmedAncestors)
+		assert.False(t, params[0].DontCheckFee)
+
+		require.Len(t, rr, 6)
+		assert.Same(t, rr[0], rr[5])
+		assert.Equal(t, submit.StatusKnown, rr[0].Status)
+		assert.NoError(t, rr[0].Err)
+
+		assert.Equal(t, submit.StatusAccepted, rr[1].Status)
+		require.NotNil(t, rr[1].Unconfirmed)
+		assert.Equal(t, p.TxID(), rr[1].Unconfirmed.Ancestors[0].TxID)
+
+		assert.Equal(t, submit.StatusEvicted, rr[2].Status)
+		require.ErrorIs(t, rr[2].Err, submit.ErrEvicted)
+		assert.False(t, rr[2].OK())
+
+		assert.Equal(t, submit.StatusInvalid, rr[3].Status)
+		require.ErrorIs(t, rr[3].Err, models.ErrTxConflict)
+		require.NotNil(t, rr[3].Invalid)
+		assert.Equal(t, 258, rr[3].Invalid.RejectCode)
+
+		assert.Equal(t, submit.StatusKnown, rr[4].Status)
+		assert.Nil(t, rr[4].Invalid)
+	})
+
+	t.Run("failed chunk", func(t *testing.T) {
+		t.Parallel()
+
+		var sent [][]string
+		s := submit.NewSubmitter(node(t, &sent, func(txIDs []string) (*models.SendRawTransactionsResponse, error) {
+			if len(txIDs) == 1 {
+				return nil, errors.New("connection refused")
+			}
+			return &models.SendRawTransactionsResponse{}, nil
+		}), submit.WithMaxTxs(4))
+
+		rr, err := s.Submit(context.TODO(), p, c1, c2, x, g)
+		require.Error(t, err)
+		require.Len(t, rr, 5)
+		for _, r := range rr[:4] {
+			assert.Equal(t, submit.StatusAccepted, r.Status)
+		}
+		assert.Equal(t, submit.StatusNotSent, rr[4].Status)
+		assert.Equal(t, "NotSent", rr[4].Status.String())
+	})
+}
@@ -0,0 +1,64 @@
+package submit
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Status the outcome of submitting a transaction.
+type Status int
+
+// Statuses.
+const (
+	// StatusNotSent the transaction was not submitted, as submitting an earlier chunk failed.
+	StatusNotSent Status = iota
+	// StatusAccepted the transaction was accepted into the mempool.
+	StatusAccepted
+	// StatusKnown the node already knew of the transaction.
+	StatusKnown
+	// StatusEvicted the transaction was accepted, then evicted from the mempool.
+	StatusEvicted
+	// StatusInvalid the transaction was rejected.
+	StatusInvalid
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusNotSent:
+		return "NotSent"
+	case StatusAccepted:
+		return "Accepted"
+	case StatusKnown:
+		return "Known"
+	case StatusEvicted:
+		return "Evicted"
+	case StatusInvalid:
+		return "Invalid"
+	}
+	return "invalid"
+}
+
+// Result the outcome of submitting a transaction of a package.
+type Result struct {
+	TxID   string
+	Tx     *bt.Tx
+	Status Status
+
+	// Invalid the rejection of the transaction, set when it is invalid.
+	Invalid *models.SendRawTransactionsInvalid
+	// Unconfirmed the unconfirmed ancestors of the transaction, set when they are listed
+	// WithListUnconfirmedAncestors.
+	Unconfirmed *models.SendRawTransactionsUnconfirmed
+	// Err why the transaction did not make it into the mempool. It is ErrEvicted for
+	// evicted transactions, and a *models.Error holding the reject code and reason for
+	// invalid ones, so can be checked with errors.Is against the errors of the models
+	// package.
+	Err error
+}
+
+// OK reports whether the transaction is in the mempool, or was already known to the node.
+func (r *Result) OK() bool {
+	return r.Status == StatusAccepted || r.Status == StatusKnown
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/stretchr/testify/require"
+)
+
+// ChainedTx returns a transaction paying sats to an OP_TRUE output, spending the first output
+// of each parent, or a coin outside the chain when there are none.
+func ChainedTx(t *testing.T, sats uint64, parents ...*bt.Tx) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	if len(parents) == 0 {
+		require.NoError(t, tx.From(strings.Repeat("ab", 32), 0, "51", sats+1))
+	}
+	for _, p := range parents {
+		require.NoError(t, tx.From(p.TxID(), 0, "51", p.Outputs[0].Satoshis))
+	}
+	s, err := bscript.NewFromHexString("51")
+	require.NoError(t, err)
+	tx.AddOutput(&bt.Output{Satoshis: sats, LockingScript: s})
+	return tx
+}
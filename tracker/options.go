@@ -0,0 +1,57 @@
+package tracker
+
+import (
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+)
+
+// DefaultPollInterval the default interval at which the tracker polls the node, between
+// `hashblock` notifications.
+const DefaultPollInterval = poll.DefaultInterval
+
+// DefaultConfirmations the default number of confirmations a mined transaction is tracked
+// until.
+const DefaultConfirmations = 6
+
+type trackerCfg struct {
+	pollInterval  time.Duration
+	confirmations uint32
+	statusFn      StatusFunc
+	errorFn       ErrorFunc
+}
+
+// TrackerOptFunc option func.
+type TrackerOptFunc func(c *trackerCfg)
+
+// WithPollInterval set the interval at which Run refreshes the tracked transactions when no
+// `hashblock` notification is received. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) TrackerOptFunc {
+	return func(c *trackerCfg) {
+		c.pollInterval = d
+	}
+}
+
+// WithConfirmations set the number of confirmations at which a mined transaction is
+// reported as confirmed and no longer tracked. Defaults to DefaultConfirmations.
+func WithConfirmations(n uint32) TrackerOptFunc {
+	return func(c *trackerCfg) {
+		c.confirmations = n
+	}
+}
+
+// WithStatusHandler set a handler called with each change to the status of a tracked
+// transaction.
+func WithStatusHandler(fn StatusFunc) TrackerOptFunc {
+	return func(c *trackerCfg) {
+		c.statusFn = fn
+	}
+}
+
+// WithErrorHandler set a handler for errors raised refreshing transactions in the
+// background. They are discarded by default.
+func WithErrorHandler(fn ErrorFunc) TrackerOptFunc {
+	return func(c *trackerCfg) {
+		c.errorFn = fn
+	}
+}
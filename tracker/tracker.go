@@ -0,0 +1,346 @@
+// Package tracker follows submitted transactions through the mempool of a bitcoin node until
+// they are mined and confirmed, or are evicted or double spent, reporting each change of
+// status.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/internal/poll"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// Reasons given by the node for removing a transaction from the mempool.
+const (
+	reasonIncluded  = "included-in-block"
+	reasonCollision = "collision-in-block-tx"
+)
+
+// maxRecentBlocks the number of blocks announced by `hashblock` searched for transactions
+// which have left the mempool.
+const maxRecentBlocks = 16
+
+// Tracker follows the status of transactions.
+type Tracker interface {
+	Track(txIDs ...string)
+	Untrack(txIDs ...string)
+	Status(txID string) (*TxStatus, bool)
+	Refresh(ctx context.Context) error
+	Run(ctx context.Context) error
+	Subscribe(z zmq.NodeMQ) error
+	OnHashBlock(ctx context.Context, hash string)
+	OnRemoved(ctx context.Context, discard *zmq.MempoolDiscard)
+}
+
+type tracker struct {
+	client bn.BlockChainClient
+	cfg    *trackerCfg
+	poller *poll.Poller
+
+	// opMu serialises changes of status, so they are passed to the StatusFunc in order.
+	opMu     sync.Mutex
+	mu       sync.RWMutex
+	txs      map[string]*TxStatus
+	included map[string]struct{}
+	blocks   []string
+}
+
+// NewTracker returns a tracker following transactions with the node behind client,
+// configured via the provided opt funcs. Transactions are refreshed by Refresh or Run, and
+// notifications passed to the handlers registered by Subscribe.
+func NewTracker(client bn.BlockChainClient, oo ...TrackerOptFunc) Tracker {
+	cfg := &trackerCfg{
+		pollInterval:  DefaultPollInterval,
+		confirmations: DefaultConfirmations,
+		statusFn:      func(context.Context, *TxStatus, Status) {},
+		errorFn:       func(context.Context, error) {},
+	}
+	for _, o := range oo {
+		o(cfg)
+	}
+
+	return &tracker{
+		client:   client,
+		cfg:      cfg,
+		poller:   poll.New(),
+		txs:      make(map[string]*TxStatus),
+		included: make(map[string]struct{}),
+	}
+}
+
+// Track starts tracking the transactions, which are unknown until the next refresh.
+// Transactions already tracked are left as they are, unless they were evicted or conflicted,
+// in which case they are tracked afresh so they can be followed once resubmitted.
+func (t *tracker) Track(txIDs ...string) {
+	t.mu.Lock()
+	for _, txID := range txIDs {
+		if s, ok := t.txs[txID]; ok && s.Status != StatusEvicted && s.Status != StatusConflicted {
+			continue
+		}
+		t.txs[txID] = &TxStatus{TxID: txID}
+		delete(t.included, txID)
+	}
+	t.mu.Unlock()
+
+	t.poller.Wake()
+}
+
+// Untrack stops tracking the transactions.
+func (t *tracker) Untrack(txIDs ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, txID := range txIDs {
+		delete(t.txs, txID)
+		delete(t.included, txID)
+	}
+}
+
+// Status returns the status of the transaction, false if it is not tracked.
+func (t *tracker) Status(txID string) (*TxStatus, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.txs[txID]
+	if !ok {
+		return nil, false
+	}
+	cp := *s
+	return &cp, true
+}
+
+// Refresh checks each tracked transaction which is not yet confirmed with the node. Those
+// which have left the mempool are looked for in the blocks recently announced by `hashblock`,
+// then via the transaction index of the node, if it has one. The confirmations of mined
+// transactions are updated, and those whose block left the best chain are looked for again.
+func (t *tracker) Refresh(ctx context.Context) error {
+	t.opMu.Lock()
+	defer t.opMu.Unlock()
+
+	t.mu.RLock()
+	txs := maps.Clone(t.txs)
+	blocks := slices.Clone(t.blocks)
+	t.mu.RUnlock()
+	slices.Reverse(blocks)
+
+	var errs []error
+	for _, txID := range slices.Sorted(maps.Keys(txs)) {
+		s := txs[txID]
+		next, err := t.refresh(ctx, s, blocks)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh %s: %w", txID, err))
+			continue
+		}
+		if next != nil {
+			t.apply(ctx, s, next)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run refreshes the tracked transactions on each `hashblock` notification, and every poll
+// interval between them, until ctx is done. Failures are passed to the error handler.
+func (t *tracker) Run(ctx context.Context) error {
+	return t.poller.Run(ctx, t.cfg.pollInterval, t.Refresh, t.cfg.errorFn)
+}
+
+// Subscribe registers the handlers of the tracker for the `hashblock`,
+// `removedfrommempoolblock` and `discardfrommempool` topics. Should any fail, those already
+// registered are unsubscribed. The topics are shared via zmq.Share, so may also be subscribed
+// to by others, such as bn.SubscribeCacheInvalidation or a mempool.Mirror, and unsubscribing
+// leaves their handlers in place.
+func (t *tracker) Subscribe(z zmq.NodeMQ) error {
+	z = zmq.Share(z)
+	if err := z.SubscribeHashBlock(t.OnHashBlock); err != nil {
+		return err
+	}
+	if err := z.SubscribeRemovedFromMempoolBlock(t.OnRemoved); err != nil {
+		return errors.Join(err, z.Unsubscribe(zmq.TopicHashBlock))
+	}
+	if err := z.SubscribeDiscardFromMempool(t.OnRemoved); err != nil {
+		return errors.Join(err,
+			z.Unsubscribe(zmq.TopicHashBlock),
+			z.Unsubscribe(zmq.TopicRemovedFromMempoolBlock),
+		)
+	}
+
+	return nil
+}
+
+// OnHashBlock records the block to be searched for transactions leaving the mempool, and
+// wakes Run to refresh the tracked transactions.
+func (t *tracker) OnHashBlock(_ context.Context, hash string) {
+	t.mu.Lock()
+	t.blocks = append(t.blocks, hash)
+	if len(t.blocks) > maxRecentBlocks {
+		t.blocks = slices.Delete(t.blocks, 0, len(t.blocks)-maxRecentBlocks)
+	}
+	t.mu.Unlock()
+
+	t.poller.Wake()
+}
+
+// OnRemoved updates a tracked transaction removed from the mempool. Those removed as they
+// were included in a block are left in the mempool until the block is found by a refresh.
+// Any other removal evicts the transaction, or, when it collided with a transaction in a
+// block, marks it conflicted.
+func (t *tracker) OnRemoved(ctx context.Context, discard *zmq.MempoolDiscard) {
+	t.opMu.Lock()
+	defer t.opMu.Unlock()
+
+	t.mu.Lock()
+	s, ok := t.txs[discard.TxID]
+	if !ok || (s.Status != StatusUnknown && s.Status != StatusMempool) {
+		t.mu.Unlock()
+		return
+	}
+	if discard.Reason == reasonIncluded {
+		t.included[discard.TxID] = struct{}{}
+		t.mu.Unlock()
+		return
+	}
+	t.mu.Unlock()
+
+	next := &TxStatus{TxID: discard.TxID, Status: StatusEvicted, Reason: discard.Reason}
+	if discard.Reason == reasonCollision {
+		next.Status = StatusConflicted
+		next.BlockHash = discard.BlockHash
+		next.CollidedWith = discard.CollidedWith.TxID
+	}
+	t.apply(ctx, s, next)
+}
+
+// refresh returns the status the transaction has moved to, nil if it is unchanged.
+func (t *tracker) refresh(ctx context.Context, s *TxStatus, blocks []string) (*TxStatus, error) {
+	switch s.Status {
+	case StatusUnknown, StatusMempool:
+		return t.locate(ctx, s, blocks)
+	case StatusMined:
+		h, err := t.client.BlockHeader(ctx, s.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		// The block left the best chain in a reorg.
+		if h.Confirmations == 0 {
+			return t.locate(ctx, s, blocks)
+		}
+
+		next := *s
+		next.Confirmations = uint32(h.Confirmations) //nolint:gosec // G115: confirmations fit in 32 bits
+		if next.Confirmations >= t.cfg.confirmations {
+			next.Status = StatusConfirmed
+		}
+		return &next, nil
+	}
+
+	return nil, nil
+}
+
+// locate looks for the transaction in the mempool, then in the blocks and via the
+// transaction index of the node, returning nil if it is not found and was included in a
+// block yet to be announced.
+func (t *tracker) locate(ctx context.Context, s *TxStatus, blocks []string) (*TxStatus, error) {
+	_, err := t.client.MempoolEntry(ctx, s.TxID)
+	if err == nil {
+		return &TxStatus{TxID: s.TxID, Status: StatusMempool}, nil
+	}
+	if !errors.Is(err, models.ErrTxNotFound) {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	_, included := t.included[s.TxID]
+	t.mu.RUnlock()
+
+	// Transactions never seen cannot be in the blocks announced since tracking began, unless
+	// they were tracked late, in which case the transaction index finds them.
+	var hashes []string
+	if s.Status != StatusUnknown || included {
+		hashes = blocks
+	}
+	for _, hash := range append(slices.Clone(hashes), "") {
+		mined, err := t.mined(ctx, s.TxID, hash)
+		if err != nil {
+			return nil, err
+		}
+		if mined != nil {
+			return mined, nil
+		}
+	}
+
+	if included {
+		return nil, nil
+	}
+	return &TxStatus{TxID: s.TxID, Status: StatusUnknown}, nil
+}
+
+// mined returns the status of the transaction mined in the block, nil if the block does not
+// hold it or is not on the best chain. An empty hash looks the transaction up via the
+// transaction index of the node.
+func (t *tracker) mined(ctx context.Context, txID, hash string) (*TxStatus, error) {
+	proof, err := t.client.MerkleProof(ctx, hash, txID, nil)
+	if errors.Is(err, models.ErrTxNotFound) || errors.Is(err, models.ErrBlockNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		hash = proof.Target
+	}
+
+	h, err := t.client.BlockHeader(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if h.Confirmations == 0 {
+		return nil, nil
+	}
+
+	s := &TxStatus{
+		TxID:          txID,
+		Status:        StatusMined,
+		BlockHash:     hash,
+		BlockHeight:   uint32(h.Height),        //nolint:gosec // G115: block heights fit in 32 bits
+		Confirmations: uint32(h.Confirmations), //nolint:gosec // G115: confirmations fit in 32 bits
+		Proof:         proof,
+	}
+	if s.Confirmations >= t.cfg.confirmations {
+		s.Status = StatusConfirmed
+	}
+	return s, nil
+}
+
+// apply moves the transaction from the prev status to next, unless it has since been
+// untracked or tracked afresh, passing any change of status to the StatusFunc. Confirmed
+// transactions are no longer tracked.
+func (t *tracker) apply(ctx context.Context, prev, next *TxStatus) {
+	t.mu.Lock()
+	if t.txs[prev.TxID] != prev {
+		t.mu.Unlock()
+		return
+	}
+	if next.Status == StatusConfirmed {
+		delete(t.txs, next.TxID)
+	} else {
+		t.txs[next.TxID] = next
+	}
+	if next.Status != StatusMempool {
+		delete(t.included, next.TxID)
+	}
+	t.mu.Unlock()
+
+	if next.Status != prev.Status {
+		cp := *next
+		t.cfg.statusFn(ctx, &cp, prev.Status)
+	}
+}
@@ -0,0 +1,347 @@
+package tracker_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/chain"
+	"github.com/bsv-blockchain/go-bn/headers"
+	"github.com/bsv-blockchain/go-bn/mempool"
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/tracker"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+// node a fake node, holding a mempool and a chain of blocks, with stale blocks left aside.
+type node struct {
+	mu      sync.Mutex
+	mempool map[string]bool
+	chain   []string
+	blocks  map[string][]string
+	stale   map[string]uint32
+	txIndex bool
+}
+
+func newNode() *node {
+	return &node{
+		mempool: make(map[string]bool),
+		blocks:  make(map[string][]string),
+		stale:   make(map[string]uint32),
+	}
+}
+
+// mine connects a block holding the txs, removing them from the mempool.
+func (n *node) mine(hash string, txIDs ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.chain = append(n.chain, hash)
+	n.blocks[hash] = txIDs
+	for _, txID := range txIDs {
+		delete(n.mempool, txID)
+	}
+}
+
+// disconnect the tip, returning its txs to the mempool.
+func (n *node) disconnect() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	hash := n.chain[len(n.chain)-1]
+	n.chain = n.chain[:len(n.chain)-1]
+	n.stale[hash] = uint32(len(n.chain))
+	for _, txID := range n.blocks[hash] {
+		n.mempool[txID] = true
+	}
+}
+
+func (n *node) add(txIDs ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, txID := range txIDs {
+		n.mempool[txID] = true
+	}
+}
+
+func (n *node) remove(txIDs ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, txID := range txIDs {
+		delete(n.mempool, txID)
+	}
+}
+
+func (n *node) client() *mocks.BlockChainClientMock {
+	return &mocks.BlockChainClientMock{
+		MempoolEntryFunc: func(_ context.Context, txID string) (*models.MempoolEntry, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if !n.mempool[txID] {
+				return nil, &models.Error{Code: -5, Message: "Transaction not in mempool"}
+			}
+			return &models.MempoolEntry{}, nil
+		},
+		MerkleProofFunc: func(_ context.Context, hash, txID string, _ *models.OptsMerkleProof) (*bc.MerkleProof, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if hash == "" {
+				if !n.txIndex {
+					return nil, &models.Error{Code: -5, Message: "Transaction not yet in block"}
+				}
+				for _, h := range n.chain {
+					if slices.Contains(n.blocks[h], txID) {
+						hash = h
+					}
+				}
+			}
+			txIDs, ok := n.blocks[hash]
+			if !ok {
+				return nil, &models.Error{Code: -5, Message: "Block not found"}
+			}
+			i := slices.Index(txIDs, txID)
+			if i < 0 {
+				return nil, &models.Error{Code: -5, Message: "Transaction not found in provided block"}
+			}
+			return &bc.MerkleProof{Index: uint64(i), TxOrID: txID, Target: hash}, nil
+		},
+		BlockHeaderFunc: func(_ context.Context, hash string) (*models.BlockHeader, error) {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			if h, ok := n.stale[hash]; ok {
+				return &models.BlockHeader{Hash: hash, Height: uint64(h)}, nil
+			}
+			h := slices.Index(n.chain, hash)
+			if h < 0 {
+				return nil, &models.Error{Code: -5, Message: "Block not found"}
+			}
+			return &models.BlockHeader{
+				Hash:          hash,
+				Height:        uint64(h),
+				Confirmations: uint64(len(n.chain) - h),
+			}, nil
+		},
+	}
+}
+
+type change struct {
+	TxID   string
+	Status tracker.Status
+	Prev   tracker.Status
+}
+
+// recorder returns a status handler recording each change.
+func recorder(changes *[]change) tracker.TrackerOptFunc {
+	var mu sync.Mutex
+	return tracker.WithStatusHandler(func(_ context.Context, s *tracker.TxStatus, prev tracker.Status) {
+		mu.Lock()
+		defer mu.Unlock()
+		*changes = append(*changes, change{TxID: s.TxID, Status: s.Status, Prev: prev})
+	})
+}
+
+func TestTracker_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	n := newNode()
+	n.txIndex = true
+	n.mine("genesis")
+	n.mine("b1", "old")
+	n.mine("b2")
+	n.add("a", "b", "d")
+
+	var changes []change
+	tr := tracker.NewTracker(n.client(), tracker.WithConfirmations(2), recorder(&changes))
+	tr.Track("a", "b", "c", "d", "old")
+	require.NoError(t, tr.Refresh(context.TODO()))
+	assert.Equal(t, []change{
+		{TxID: "a", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+		{TxID: "b", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+		{TxID: "d", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+		{TxID: "old", Status: tracker.StatusConfirmed, Prev: tracker.StatusUnknown},
+	}, changes)
+
+	s, ok := tr.Status("c")
+	require.True(t, ok)
+	assert.Equal(t, tracker.StatusUnknown, s.Status)
+	_, ok = tr.Status("old")
+	assert.False(t, ok)
+
+	// A mined transaction is reported with its block and proof, then confirmed.
+	changes = nil
+	n.mine("b3", "x", "a")
+	tr.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "a", Reason: "included-in-block"})
+	tr.OnHashBlock(context.TODO(), "b3")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	s, ok = tr.Status("a")
+	require.True(t, ok)
+	assert.Equal(t, tracker.StatusMined, s.Status)
+	assert.Equal(t, "b3", s.BlockHash)
+	assert.Equal(t, uint32(3), s.BlockHeight)
+	assert.Equal(t, uint32(1), s.Confirmations)
+	require.NotNil(t, s.Proof)
+	assert.Equal(t, uint64(1), s.Proof.Index)
+	assert.Equal(t, "b3", s.Proof.Target)
+
+	n.mine("b4")
+	tr.OnHashBlock(context.TODO(), "b4")
+	require.NoError(t, tr.Refresh(context.TODO()))
+	_, ok = tr.Status("a")
+	assert.False(t, ok)
+
+	// Evictions and conflicts are reported as the node announces them.
+	n.remove("b", "d")
+	tr.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "b", Reason: "expired"})
+	discard := &zmq.MempoolDiscard{TxID: "d", Reason: "collision-in-block-tx", BlockHash: "b4"}
+	discard.CollidedWith.TxID = "e"
+	tr.OnRemoved(context.TODO(), discard)
+	tr.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "untracked", Reason: "expired"})
+
+	s, ok = tr.Status("b")
+	require.True(t, ok)
+	assert.Equal(t, "expired", s.Reason)
+	s, ok = tr.Status("d")
+	require.True(t, ok)
+	assert.Equal(t, "e", s.CollidedWith)
+	assert.Equal(t, "b4", s.BlockHash)
+
+	// Evicted transactions are followed again once tracked afresh.
+	n.add("b")
+	tr.Track("b")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	assert.Equal(t, []change{
+		{TxID: "a", Status: tracker.StatusMined, Prev: tracker.StatusMempool},
+		{TxID: "a", Status: tracker.StatusConfirmed, Prev: tracker.StatusMined},
+		{TxID: "b", Status: tracker.StatusEvicted, Prev: tracker.StatusMempool},
+		{TxID: "d", Status: tracker.StatusConflicted, Prev: tracker.StatusMempool},
+		{TxID: "b", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+	}, changes)
+}
+
+func TestTracker_Reorg(t *testing.T) {
+	t.Parallel()
+
+	n := newNode()
+	n.mine("genesis")
+	n.add("a", "b")
+
+	var changes []change
+	tr := tracker.NewTracker(n.client(), recorder(&changes))
+	tr.Track("a", "b")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	n.mine("x1", "a")
+	tr.OnHashBlock(context.TODO(), "x1")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	// The block is disconnected, returning the transaction to the mempool.
+	n.disconnect()
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	// It is mined again on the new branch.
+	n.mine("y1")
+	n.mine("y2", "a")
+	tr.OnHashBlock(context.TODO(), "y1")
+	tr.OnHashBlock(context.TODO(), "y2")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	s, ok := tr.Status("a")
+	require.True(t, ok)
+	assert.Equal(t, tracker.StatusMined, s.Status)
+	assert.Equal(t, "y2", s.BlockHash)
+	assert.Equal(t, uint32(2), s.BlockHeight)
+
+	// Without a transaction index, a transaction leaving the mempool unannounced is unknown.
+	n.remove("b")
+	require.NoError(t, tr.Refresh(context.TODO()))
+
+	assert.Equal(t, []change{
+		{TxID: "a", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+		{TxID: "b", Status: tracker.StatusMempool, Prev: tracker.StatusUnknown},
+		{TxID: "a", Status: tracker.StatusMined, Prev: tracker.StatusMempool},
+		{TxID: "a", Status: tracker.StatusMempool, Prev: tracker.StatusMined},
+		{TxID: "a", Status: tracker.StatusMined, Prev: tracker.StatusMempool},
+		{TxID: "b", Status: tracker.StatusUnknown, Prev: tracker.StatusMempool},
+	}, changes)
+}
+
+func TestTracker_Run(t *testing.T) {
+	t.Parallel()
+
+	n := newNode()
+	n.mine("genesis")
+	n.add("a")
+
+	statuses := make(chan tracker.Status, 2)
+	tr := tracker.NewTracker(n.client(),
+		tracker.WithPollInterval(time.Hour),
+		tracker.WithStatusHandler(func(_ context.Context, s *tracker.TxStatus, _ tracker.Status) {
+			statuses <- s.Status
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- tr.Run(ctx)
+	}()
+
+	tr.Track("a")
+	assert.Equal(t, tracker.StatusMempool, <-statuses)
+
+	n.mine("b1", "a")
+	tr.OnRemoved(context.TODO(), &zmq.MempoolDiscard{TxID: "a", Reason: "included-in-block"})
+	tr.OnHashBlock(context.TODO(), "b1")
+	assert.Equal(t, tracker.StatusMined, <-statuses)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestTracker_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	z := zmq.NewNodeMQ(zmq.WithHost("tcp://localhost:28332"))
+	require.NoError(t, z.SubscribeRemovedFromMempoolBlock(func(context.Context, *zmq.MempoolDiscard) {}))
+
+	tr := tracker.NewTracker(newNode().client())
+	require.ErrorIs(t, tr.Subscribe(z), zmq.ErrAlreadySubscribed)
+
+	// The topics registered before the failure are released.
+	require.NoError(t, z.SubscribeHashBlock(func(context.Context, string) {}))
+	require.NoError(t, z.Close(context.TODO()))
+}
+
+func TestTracker_Subscribe_Shared(t *testing.T) {
+	t.Parallel()
+
+	client := newNode().client()
+	z := zmq.NewNodeMQ(zmq.WithHost("tcp://localhost:28332"))
+
+	// Each claims some of the topics of the tracker, all sharing them on one connection.
+	require.NoError(t, bn.SubscribeCacheInvalidation(z, bn.NewNodeClient(bn.WithCache())))
+	require.NoError(t, mempool.NewMirror(client).Subscribe(z))
+	require.NoError(t, chain.NewFollower(client, func(context.Context, *chain.Event) error {
+		return nil
+	}).Subscribe(z))
+	require.NoError(t, headers.NewStore(client).Subscribe(z))
+	require.NoError(t, tracker.NewTracker(client).Subscribe(z))
+
+	require.ErrorIs(t, z.SubscribeHashBlock(func(context.Context, string) {}), zmq.ErrAlreadySubscribed)
+	require.NoError(t, z.Close(context.TODO()))
+}
@@ -0,0 +1,74 @@
+package tracker
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bc"
+)
+
+// Status the state of a tracked transaction.
+type Status int
+
+// Statuses.
+const (
+	// StatusUnknown the transaction has not been seen by the node, or left the chain in a
+	// reorg and is not back in the mempool.
+	StatusUnknown Status = iota
+	// StatusMempool the transaction is in the mempool of the node.
+	StatusMempool
+	// StatusMined the transaction is in a block of the best chain.
+	StatusMined
+	// StatusConfirmed the transaction is mined with the confirmations required, after which
+	// it is no longer tracked.
+	StatusConfirmed
+	// StatusEvicted the transaction was removed from the mempool without being mined, such
+	// as by expiry or the mempool size limit.
+	StatusEvicted
+	// StatusConflicted the transaction was removed from the mempool as it conflicts with a
+	// transaction mined in a block.
+	StatusConflicted
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusUnknown:
+		return "Unknown"
+	case StatusMempool:
+		return "Mempool"
+	case StatusMined:
+		return "Mined"
+	case StatusConfirmed:
+		return "Confirmed"
+	case StatusEvicted:
+		return "Evicted"
+	case StatusConflicted:
+		return "Conflicted"
+	}
+	return "invalid"
+}
+
+// TxStatus the status of a tracked transaction.
+type TxStatus struct {
+	TxID   string
+	Status Status
+
+	// BlockHash, BlockHeight, Confirmations and Proof are set once the transaction is mined.
+	// BlockHash is also set for conflicts, to the block holding the conflicting transaction.
+	BlockHash     string
+	BlockHeight   uint32
+	Confirmations uint32
+	Proof         *bc.MerkleProof
+
+	// Reason the reason given by the node for removing the transaction from the mempool.
+	Reason string
+	// CollidedWith the ID of the transaction a conflicted transaction was double spent by.
+	CollidedWith string
+}
+
+// StatusFunc a func in which changes to the status of tracked transactions are passed to,
+// with the status they changed from. Changes are passed in the order they happen.
+type StatusFunc func(ctx context.Context, s *TxStatus, prev Status)
+
+// ErrorFunc a func in which errors following transactions in the background are passed to.
+type ErrorFunc func(ctx context.Context, err error)
@@ -0,0 +1,10 @@
+package txbuilder
+
+import "errors"
+
+// Standard errors.
+var (
+	ErrNoKeys            = errors.New("no keys to spend with")
+	ErrInsufficientFunds = errors.New("insufficient spendable outputs")
+	ErrUnknownScript     = errors.New("no key for locking script")
+)
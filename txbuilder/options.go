@@ -0,0 +1,48 @@
+package txbuilder
+
+import (
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+type builderCfg struct {
+	feeQuote         *bt.FeeQuote
+	changeScript     *bscript.Script
+	minConfirmations uint32
+	sendOpts         *models.OptsSendRawTransaction
+}
+
+// BuilderOptFunc option func.
+type BuilderOptFunc func(c *builderCfg)
+
+// WithFeeQuote set the fee rates transactions are built to pay. Defaults to bt.NewFeeQuote.
+func WithFeeQuote(fq *bt.FeeQuote) BuilderOptFunc {
+	return func(c *builderCfg) {
+		c.feeQuote = fq
+	}
+}
+
+// WithChangeScript set the locking script change is paid to. Defaults to P2PKH of the first
+// key.
+func WithChangeScript(s *bscript.Script) BuilderOptFunc {
+	return func(c *builderCfg) {
+		c.changeScript = s
+	}
+}
+
+// WithMinConfirmations set the number of confirmations an output needs before it is spent.
+// Defaults to 0, spending outputs still in the mempool.
+func WithMinConfirmations(n uint32) BuilderOptFunc {
+	return func(c *builderCfg) {
+		c.minConfirmations = n
+	}
+}
+
+// WithSendOptions set the options transactions are submitted with by Send.
+func WithSendOptions(opts *models.OptsSendRawTransaction) BuilderOptFunc {
+	return func(c *builderCfg) {
+		c.sendOpts = opts
+	}
+}
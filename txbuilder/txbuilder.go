@@ -0,0 +1,294 @@
+// Package txbuilder builds, signs and submits transactions with keys held by the caller,
+// using the node only to check which outputs are unspent and to broadcast, never its wallet.
+package txbuilder
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-bt/v2/unlocker"
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// coinbaseMaturity the number of confirmations before a coinbase output can be spent.
+const coinbaseMaturity = 100
+
+// Builder funds transactions from candidate outpoints locked to its keys.
+type Builder interface {
+	AddOutpoints(outpoints ...Outpoint)
+	Outpoints() []Outpoint
+	Spendable(ctx context.Context) ([]*bt.UTXO, error)
+	Build(ctx context.Context, outputs ...*bt.Output) (*bt.Tx, error)
+	Send(ctx context.Context, outputs ...*bt.Output) (*bt.Tx, error)
+}
+
+type builder struct {
+	client Client
+	cfg    *builderCfg
+	keys   map[string]*primitives.PrivateKey
+
+	mu        sync.Mutex
+	outpoints map[Outpoint]struct{}
+}
+
+// NewBuilder returns a builder spending P2PKH outputs locked to the keys, looking them up
+// and submitting transactions via the node behind client. It is configured via the provided
+// opt funcs.
+func NewBuilder(client Client, keys []*primitives.PrivateKey, oo ...BuilderOptFunc) Builder {
+	b := &builder{
+		client:    client,
+		cfg:       &builderCfg{},
+		keys:      make(map[string]*primitives.PrivateKey, len(keys)),
+		outpoints: make(map[Outpoint]struct{}),
+	}
+	for _, k := range keys {
+		s, err := bscript.NewP2PKHFromPubKeyBytes(k.PubKey().Compressed())
+		if err != nil {
+			continue
+		}
+		b.keys[s.String()] = k
+		if b.cfg.changeScript == nil {
+			b.cfg.changeScript = s
+		}
+	}
+	for _, o := range oo {
+		o(b.cfg)
+	}
+	if b.cfg.feeQuote == nil {
+		b.cfg.feeQuote = bt.NewFeeQuote()
+	}
+
+	return b
+}
+
+// AddOutpoints adds candidate outpoints to fund transactions from. They are checked with the
+// node before being spent, so need not be unspent, nor locked to the keys of the builder.
+func (b *builder) AddOutpoints(outpoints ...Outpoint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, o := range outpoints {
+		b.outpoints[o] = struct{}{}
+	}
+}
+
+// Outpoints returns the candidate outpoints, ordered by txid and vout.
+func (b *builder) Outpoints() []Outpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return slices.SortedFunc(maps.Keys(b.outpoints), func(a, c Outpoint) int {
+		return cmp.Or(cmp.Compare(a.TxID, c.TxID), cmp.Compare(a.Vout, c.Vout))
+	})
+}
+
+// Spendable checks the candidate outpoints with the node, returning those which can be spent,
+// largest first. Candidates which are spent, including by a transaction in the mempool, or
+// not locked to a key of the builder are dropped. Those with too few confirmations are kept
+// for later.
+func (b *builder) Spendable(ctx context.Context) ([]*bt.UTXO, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.spendable(ctx)
+}
+
+// Build returns a signed transaction paying the outputs, funded by spendable outputs and
+// paying any change to the change script. It is not submitted, so the outputs it spends
+// remain candidates.
+func (b *builder) Build(ctx context.Context, outputs ...*bt.Output) (*bt.Tx, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.build(ctx, outputs)
+}
+
+// Send builds a transaction paying the outputs and submits it to the node. The outputs it
+// spends are dropped from the candidates, and those it creates which are locked to a key of
+// the builder, such as change, are added.
+func (b *builder) Send(ctx context.Context, outputs ...*bt.Output) (*bt.Tx, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tx, err := b.build(ctx, outputs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = b.client.SendRawTransaction(ctx, tx, b.cfg.sendOpts); err != nil {
+		return nil, err
+	}
+
+	for _, in := range tx.Inputs {
+		delete(b.outpoints, Outpoint{TxID: in.PreviousTxIDStr(), Vout: in.PreviousTxOutIndex})
+	}
+	for i, out := range tx.Outputs {
+		if _, ok := b.keys[out.LockingScript.String()]; ok {
+			b.outpoints[Outpoint{TxID: tx.TxID(), Vout: uint32(i)}] = struct{}{}
+		}
+	}
+
+	return tx, nil
+}
+
+func (b *builder) build(ctx context.Context, outputs []*bt.Output) (*bt.Tx, error) {
+	if len(b.keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	utxos, err := b.spendable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := bt.NewTx()
+	for _, o := range outputs {
+		tx.AddOutput(o)
+	}
+	if err = tx.Fund(ctx, b.cfg.feeQuote, selector(utxos)); err != nil {
+		if errors.Is(err, bt.ErrInsufficientFunds) {
+			return nil, fmt.Errorf("%w: %d outputs can pay at most %d satoshis", ErrInsufficientFunds,
+				len(utxos), total(utxos))
+		}
+		return nil, err
+	}
+	if err = tx.Change(b.cfg.changeScript, b.cfg.feeQuote); err != nil {
+		return nil, err
+	}
+	if err = tx.FillAllInputs(ctx, b); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Unlocker returns an unlocker signing with the key the locking script pays to.
+func (b *builder) Unlocker(_ context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	k, ok := b.keys[lockingScript.String()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownScript, lockingScript)
+	}
+	return &unlocker.Simple{PrivateKey: k}, nil
+}
+
+func (b *builder) spendable(ctx context.Context) ([]*bt.UTXO, error) {
+	outpoints := slices.Collect(maps.Keys(b.outpoints))
+	outs, err := b.lookup(ctx, outpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]*bt.UTXO, 0, len(outs))
+	for i, out := range outs {
+		o := outpoints[i]
+
+		// The node replies null for spent outputs.
+		if out.BestBlock == "" || out.LockingScript == nil {
+			delete(b.outpoints, o)
+			continue
+		}
+		if _, ok := b.keys[out.LockingScript.String()]; !ok {
+			delete(b.outpoints, o)
+			continue
+		}
+		if out.Confirmations < b.cfg.minConfirmations || (out.Coinbase && out.Confirmations < coinbaseMaturity) {
+			continue
+		}
+
+		txID, err := chainhash.NewHashFromStr(o.TxID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, &bt.UTXO{
+			TxIDHash:      txID,
+			Vout:          o.Vout,
+			LockingScript: out.LockingScript,
+			Satoshis:      out.Satoshis,
+		})
+	}
+
+	slices.SortFunc(utxos, func(a, c *bt.UTXO) int {
+		return cmp.Or(cmp.Compare(c.Satoshis, a.Satoshis), cmp.Compare(a.TxIDStr(), c.TxIDStr()),
+			cmp.Compare(a.Vout, c.Vout))
+	})
+	return utxos, nil
+}
+
+// lookup the outpoints with the node, including the mempool. They are batched when the client
+// supports it.
+func (b *builder) lookup(ctx context.Context, outpoints []Outpoint) ([]*models.Output, error) {
+	opts := &models.OptsOutput{IncludeMempool: true}
+	outs := make([]*models.Output, 0, len(outpoints))
+
+	if c, ok := b.client.(bn.BatchClient); ok && len(outpoints) > 0 {
+		batch := c.Batch()
+		rr := make([]*bn.BatchResult[*models.Output], 0, len(outpoints))
+		for _, o := range outpoints {
+			rr = append(rr, batch.Output(o.TxID, int(o.Vout), opts))
+		}
+		if err := batch.Send(ctx); err != nil {
+			return nil, err
+		}
+
+		for _, r := range rr {
+			out, err := r.Result()
+			if err != nil {
+				return nil, err
+			}
+			outs = append(outs, out)
+		}
+		return outs, nil
+	}
+
+	for _, o := range outpoints {
+		out, err := b.client.Output(ctx, o.TxID, int(o.Vout), opts)
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, out)
+	}
+	return outs, nil
+}
+
+// selector returns a bt.UTXOGetterFunc choosing from the outputs, which are ordered largest
+// first, the smallest covering the deficit, or else the largest. Few inputs are spent and
+// little change made.
+func selector(utxos []*bt.UTXO) bt.UTXOGetterFunc {
+	utxos = slices.Clone(utxos)
+
+	return func(_ context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		if len(utxos) == 0 {
+			return nil, bt.ErrNoUTXO
+		}
+
+		i, _ := slices.BinarySearchFunc(utxos, deficit, func(u *bt.UTXO, d uint64) int {
+			if u.Satoshis >= d {
+				return -1
+			}
+			return 1
+		})
+		i = max(i-1, 0)
+
+		u := utxos[i]
+		utxos = slices.Delete(utxos, i, i+1)
+		return []*bt.UTXO{u}, nil
+	}
+}
+
+func total(utxos []*bt.UTXO) uint64 {
+	var n uint64
+	for _, u := range utxos {
+		n += u.Satoshis
+	}
+	return n
+}
@@ -0,0 +1,183 @@
+package txbuilder_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2"
+	"github.com/bsv-blockchain/go-bt/v2/bscript"
+	"github.com/bsv-blockchain/go-bt/v2/bscript/interpreter"
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bn/txbuilder"
+)
+
+type client struct {
+	*mocks.BlockChainClientMock
+	*mocks.TransactionClientMock
+}
+
+func key(t *testing.T) (*primitives.PrivateKey, *bscript.Script) {
+	t.Helper()
+
+	k, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+	s, err := bscript.NewP2PKHFromPubKeyBytes(k.PubKey().Compressed())
+	require.NoError(t, err)
+	return k, s
+}
+
+func txID(c string) string {
+	return strings.Repeat(c, 64)
+}
+
+// node returns a client serving the outputs, recording transactions sent to it.
+func node(outputs map[txbuilder.Outpoint]*models.Output, sent *[]*bt.Tx) *client {
+	return &client{
+		BlockChainClientMock: &mocks.BlockChainClientMock{
+			OutputFunc: func(_ context.Context, txID string, n int, opts *models.OptsOutput) (*models.Output, error) {
+				if !opts.IncludeMempool {
+					panic("mempool not included")
+				}
+				if out, ok := outputs[txbuilder.Outpoint{TxID: txID, Vout: uint32(n)}]; ok {
+					return out, nil
+				}
+				return &models.Output{Output: &bt.Output{}}, nil
+			},
+		},
+		TransactionClientMock: &mocks.TransactionClientMock{
+			SendRawTransactionFunc: func(_ context.Context, tx *bt.Tx, _ *models.OptsSendRawTransaction) (string, error) {
+				*sent = append(*sent, tx)
+				return tx.TxID(), nil
+			},
+		},
+	}
+}
+
+func output(s *bscript.Script, sats uint64, confs uint32) *models.Output {
+	return &models.Output{
+		Output:        &bt.Output{LockingScript: s, Satoshis: sats},
+		BestBlock:     txID("f"),
+		Confirmations: confs,
+	}
+}
+
+// verify executes the unlocking script of each input against the output it spends.
+func verify(t *testing.T, tx *bt.Tx, outputs map[txbuilder.Outpoint]*models.Output) {
+	t.Helper()
+
+	for i, in := range tx.Inputs {
+		prev := outputs[txbuilder.Outpoint{TxID: in.PreviousTxIDStr(), Vout: in.PreviousTxOutIndex}]
+		require.NotNil(t, prev)
+		require.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, i, prev.Output),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	k1, s1 := key(t)
+	k2, s2 := key(t)
+	_, foreign := key(t)
+
+	coinbase := output(s1, 5000000, 10)
+	coinbase.Coinbase = true
+	outputs := map[txbuilder.Outpoint]*models.Output{
+		{TxID: txID("a"), Vout: 0}: output(s1, 50000, 3),
+		{TxID: txID("a"), Vout: 1}: output(s2, 20000, 0),
+		{TxID: txID("b"), Vout: 0}: output(foreign, 90000, 3),
+		{TxID: txID("c"), Vout: 0}: coinbase,
+		{TxID: txID("d"), Vout: 2}: output(s1, 10000, 1),
+	}
+	var sent []*bt.Tx
+	b := txbuilder.NewBuilder(node(outputs, &sent), []*primitives.PrivateKey{k1, k2})
+	b.AddOutpoints(
+		txbuilder.Outpoint{TxID: txID("a"), Vout: 0},
+		txbuilder.Outpoint{TxID: txID("a"), Vout: 1},
+		txbuilder.Outpoint{TxID: txID("a"), Vout: 2},
+		txbuilder.Outpoint{TxID: txID("b"), Vout: 0},
+		txbuilder.Outpoint{TxID: txID("c"), Vout: 0},
+		txbuilder.Outpoint{TxID: txID("d"), Vout: 2},
+	)
+
+	// Spent and foreign outputs are dropped, while the immature coinbase waits.
+	utxos, err := b.Spendable(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, utxos, 3)
+	assert.Equal(t, []uint64{50000, 20000, 10000}, []uint64{utxos[0].Satoshis, utxos[1].Satoshis, utxos[2].Satoshis})
+	assert.Equal(t, []txbuilder.Outpoint{
+		{TxID: txID("a"), Vout: 0},
+		{TxID: txID("a"), Vout: 1},
+		{TxID: txID("c"), Vout: 0},
+		{TxID: txID("d"), Vout: 2},
+	}, b.Outpoints())
+
+	// The smallest output covering the payment is spent, with change to the first key.
+	tx, err := b.Build(context.TODO(), &bt.Output{LockingScript: foreign, Satoshis: 15000})
+	require.NoError(t, err)
+	require.Len(t, tx.Inputs, 1)
+	assert.Equal(t, txID("a"), tx.Inputs[0].PreviousTxIDStr())
+	assert.Equal(t, uint32(1), tx.Inputs[0].PreviousTxOutIndex)
+	require.Len(t, tx.Outputs, 2)
+	assert.Equal(t, s1.String(), tx.Outputs[1].LockingScript.String())
+	assert.Greater(t, tx.TotalInputSatoshis(), tx.TotalOutputSatoshis())
+	ok, err := tx.IsFeePaidEnough(bt.NewFeeQuote())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	verify(t, tx, outputs)
+	assert.Empty(t, sent)
+
+	// Sending spends several outputs, replacing them with the change.
+	tx, err = b.Send(context.TODO(), &bt.Output{LockingScript: foreign, Satoshis: 60000})
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	assert.Equal(t, tx.TxID(), sent[0].TxID())
+	require.Len(t, tx.Inputs, 2)
+	verify(t, tx, outputs)
+
+	assert.ElementsMatch(t, []txbuilder.Outpoint{
+		{TxID: txID("c"), Vout: 0},
+		{TxID: txID("d"), Vout: 2},
+		{TxID: tx.TxID(), Vout: 1},
+	}, b.Outpoints())
+
+	_, err = b.Build(context.TODO(), &bt.Output{LockingScript: foreign, Satoshis: 1000000})
+	require.ErrorIs(t, err, txbuilder.ErrInsufficientFunds)
+}
+
+func TestBuilder_Options(t *testing.T) {
+	t.Parallel()
+
+	k, s := key(t)
+	_, change := key(t)
+	outputs := map[txbuilder.Outpoint]*models.Output{
+		{TxID: txID("a"), Vout: 0}: output(s, 50000, 0),
+		{TxID: txID("a"), Vout: 1}: output(s, 40000, 6),
+	}
+	var sent []*bt.Tx
+
+	_, err := txbuilder.NewBuilder(node(outputs, &sent), nil).Build(context.TODO())
+	require.ErrorIs(t, err, txbuilder.ErrNoKeys)
+
+	b := txbuilder.NewBuilder(node(outputs, &sent), []*primitives.PrivateKey{k},
+		txbuilder.WithMinConfirmations(1),
+		txbuilder.WithChangeScript(change),
+	)
+	b.AddOutpoints(txbuilder.Outpoint{TxID: txID("a"), Vout: 0}, txbuilder.Outpoint{TxID: txID("a"), Vout: 1})
+
+	tx, err := b.Build(context.TODO(), &bt.Output{LockingScript: s, Satoshis: 1000})
+	require.NoError(t, err)
+	require.Len(t, tx.Inputs, 1)
+	assert.Equal(t, uint32(1), tx.Inputs[0].PreviousTxOutIndex)
+	assert.Equal(t, change.String(), tx.Outputs[1].LockingScript.String())
+	verify(t, tx, outputs)
+}
@@ -0,0 +1,25 @@
+package txbuilder
+
+import (
+	"strconv"
+
+	"github.com/bsv-blockchain/go-bn"
+)
+
+// Client the node commands the builder uses to look up outputs and submit transactions.
+type Client interface {
+	bn.BlockChainClient
+	bn.TransactionClient
+}
+
+// Outpoint a reference to a transaction output which may be spendable by the keys of the
+// builder.
+type Outpoint struct {
+	TxID string
+	Vout uint32
+}
+
+// String returns the outpoint as `txid:vout`.
+func (o Outpoint) String() string {
+	return o.TxID + ":" + strconv.FormatUint(uint64(o.Vout), 10)
+}
@@ -1,10 +1,36 @@
 package zmq
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Standard errors.
 var (
 	ErrInvalidTopic      = errors.New("invalid topic")
 	ErrAlreadySubscribed = errors.New("already subscribed to topic")
 	ErrHostEmpty         = errors.New("host cannot be empty")
+	ErrStale             = errors.New("connection is stale")
+	ErrSequenceGap       = errors.New("sequence gap")
+	ErrClosed            = errors.New("connection closed")
+	ErrInvalidBufferSize = errors.New("buffer size must be at least 1")
 )
+
+// SequenceGap a gap in the sequence numbers of a topic, meaning messages were missed. Reported
+// to the GapFunc set WithGapHandler, or else to the error handler. Messages missed while the
+// node restarts are also reported, as its sequence numbers restart from zero.
+type SequenceGap struct {
+	Topic    Topic
+	Expected uint32
+	Received uint32
+}
+
+// Error returns the gap as a string.
+func (g *SequenceGap) Error() string {
+	return fmt.Sprintf("%s: %s expected %d, received %d", ErrSequenceGap, g.Topic, g.Expected, g.Received)
+}
+
+// Is reports whether target is ErrSequenceGap.
+func (g *SequenceGap) Is(target error) bool {
+	return target == ErrSequenceGap
+}
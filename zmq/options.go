@@ -2,8 +2,13 @@ package zmq
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-zeromq/zmq4"
+
+	"github.com/bsv-blockchain/go-bn/models"
 )
 
 type nodeMqCfg struct {
@@ -15,13 +20,37 @@ type nodeMqCfg struct {
 	errorFn        ErrorFunc
 	ctx            context.Context //nolint:containedctx // context required for long-lived ZMQ socket lifecycle
 	zmqSocket      zmq4.Socket
+	socketFn       func() zmq4.Socket
+
+	reconnect          bool
+	reconnectBaseDelay time.Duration
+	reconnectMaxDelay  time.Duration
+	staleTopic         Topic
+	staleAfter         time.Duration
+	onConnect          ConnectFunc
+	onDisconnect       DisconnectFunc
+	gapFn              GapFunc
+	endpoints          map[Topic]string
+	ordered            bool
+	bufferSize         int
+	backpressure       Backpressure
 }
 
 func (c *nodeMqCfg) validate() error {
-	if c.host == "" {
+	if c.host == "" && len(c.endpoints) == 0 {
 		return ErrHostEmpty
 	}
 
+	return c.validateBuffer()
+}
+
+// validateBuffer checks the buffer size of topics delivered in order, as a full buffer must
+// hold a message for BackpressureDropOldest to drop.
+func (c *nodeMqCfg) validateBuffer() error {
+	if c.bufferSize < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidBufferSize, c.bufferSize)
+	}
+
 	return nil
 }
 
@@ -29,15 +58,19 @@ func (c *nodeMqCfg) validate() error {
 type NodeMQOptFunc func(o *nodeMqCfg)
 
 // WithRaw listen and allow subscribing to `rawtx` and `rawblock` messages.
+//
+// Deprecated: all topics may be subscribed to, with only the messages of subscribed topics
+// received on the socket.
 func WithRaw() NodeMQOptFunc {
 	return func(o *nodeMqCfg) {
-		o.topics[TopicRawBlock] = true
-		o.topics[TopicRawTx] = true
 		o.raw = true
 	}
 }
 
-// WithSubscribeOptionValue set the option value.
+// WithSubscribeOptionValue set an additional message prefix subscribed to on the socket.
+//
+// Deprecated: each topic is subscribed to on the socket as it is subscribed to, and
+// unsubscribed from on Unsubscribe.
 func WithSubscribeOptionValue(ov string) NodeMQOptFunc {
 	return func(o *nodeMqCfg) {
 		o.optionValue = ov
@@ -80,9 +113,122 @@ func WithContext(ctx context.Context) NodeMQOptFunc {
 	}
 }
 
-// WithCustomZMQSocket set a custom zmq4.Socket. If unset, a default will be used.
+// WithCustomZMQSocket set a custom zmq4.Socket, used for the first connection. Reconnects,
+// and any further endpoints, use a socket built by the factory set WithSocketFactory. If
+// unset, a default will be used.
 func WithCustomZMQSocket(z zmq4.Socket) NodeMQOptFunc {
 	return func(o *nodeMqCfg) {
 		o.zmqSocket = z
 	}
 }
+
+// WithSocketFactory set a func building the zmq4.Socket used for each connection, called again
+// for every endpoint and reconnect, so must return a new socket each call. If unset, a new
+// SUB socket will be used.
+func WithSocketFactory(fn func() zmq4.Socket) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.socketFn = fn
+	}
+}
+
+// WithReconnect re-establish the connection when it fails, such as when the node restarts,
+// rather than returning from Connect. The delay between attempts starts at baseDelay and
+// doubles up to maxDelay. Topic subscriptions are re-applied to the new connection.
+func WithReconnect(baseDelay, maxDelay time.Duration) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.reconnect = true
+		o.reconnectBaseDelay = baseDelay
+		o.reconnectMaxDelay = maxDelay
+	}
+}
+
+// WithStalenessTimeout report ErrStale to the error handler when no message has been received
+// on topic within timeout, such as no `hashblock` for 30 minutes. An empty topic watches all
+// messages. When WithReconnect is set, a stale connection is also re-established.
+func WithStalenessTimeout(topic Topic, timeout time.Duration) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.staleTopic = topic
+		o.staleAfter = timeout
+	}
+}
+
+// WithOnConnect set a func called each time a connection is established.
+func WithOnConnect(fn ConnectFunc) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.onConnect = fn
+	}
+}
+
+// WithOnDisconnect set a func called each time the connection is lost, when WithReconnect is set.
+func WithOnDisconnect(fn DisconnectFunc) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.onDisconnect = fn
+	}
+}
+
+// WithGapHandler set a func called when a gap in the sequence numbers of a topic shows
+// messages were missed, such as to resync over RPC. If unset, gaps are reported to the
+// error handler as a *SequenceGap.
+func WithGapHandler(fn GapFunc) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.gapFn = fn
+	}
+}
+
+// WithTopicHost set the host a topic is published on, where it differs from the host set
+// WithHost. Expects the following format:
+// tcp://hostname:port
+func WithTopicHost(topic Topic, host string) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		if o.endpoints == nil {
+			o.endpoints = make(map[Topic]string)
+		}
+		o.endpoints[topic] = host
+	}
+}
+
+// WithNotifications set the host each topic is published on from the notifications active on
+// the node, as returned by bn.ControlClient's ActiveZMQNotifications:
+//
+//	nn, err := c.ActiveZMQNotifications(ctx)
+//	if err != nil {}
+//	z := zmq.NewNodeMQ(zmq.WithHost("tcp://node:28332"), zmq.WithNotifications(nn))
+//
+// Addresses bound to all interfaces, such as tcp://0.0.0.0:28332, are dialled on the hostname
+// set WithHost.
+func WithNotifications(nn []*models.ZMQNotification) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		for _, n := range nn {
+			topic := Topic(strings.TrimPrefix(n.Notification, "pub"))
+			if _, ok := o.topics[topic]; ok {
+				WithTopicHost(topic, n.Address)(o)
+			}
+		}
+	}
+}
+
+// WithOrderedDelivery call the handlers of each topic one message at a time, in the order
+// received, rather than each in its own goroutine. Messages are buffered per topic as set
+// WithBufferSize and WithBackpressure. Handlers subscribed this way end when Connect returns.
+func WithOrderedDelivery() NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.ordered = true
+	}
+}
+
+// WithBufferSize set the number of messages buffered per topic for ordered delivery, through
+// WithOrderedDelivery, Channel or Messages. Defaults to DefaultBufferSize. A size below 1 is
+// rejected with ErrInvalidBufferSize by Connect, and by subscriptions delivered in order.
+func WithBufferSize(n int) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.bufferSize = n
+	}
+}
+
+// WithBackpressure set the policy applied when the buffer of a topic delivered in order is
+// full. Defaults to BackpressureBlock. Dropped messages are counted by Dropped.
+func WithBackpressure(p Backpressure) NodeMQOptFunc {
+	return func(o *nodeMqCfg) {
+		o.backpressure = p
+	}
+}
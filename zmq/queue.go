@@ -0,0 +1,113 @@
+package zmq
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBufferSize the default number of messages buffered per topic for ordered delivery.
+const DefaultBufferSize = 1024
+
+// Backpressure the policy applied when the buffer of a topic delivered in order is full.
+type Backpressure int
+
+// Backpressure policies.
+const (
+	// BackpressureBlock wait for the buffer to drain, holding up receipt of further messages.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDropOldest drop the oldest buffered message to make room.
+	BackpressureDropOldest
+	// BackpressureDropNewest drop the message received.
+	BackpressureDropNewest
+)
+
+// Message a message received on a topic, as delivered by Channel and Messages.
+type Message struct {
+	Topic  Topic
+	Frames [][]byte
+	// Sequence the sequence number of the message, zero when HasSequence is false.
+	Sequence    uint32
+	HasSequence bool
+
+	ctx context.Context //nolint:containedctx // carries the sequence number to handlers
+}
+
+// Context returns the context handlers of the message are passed, from which
+// SequenceFromContext reads the sequence number.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// queue a bounded, ordered buffer of the messages of a topic.
+type queue struct {
+	mu      sync.RWMutex
+	once    sync.Once
+	ch      chan *Message
+	done    chan struct{}
+	closed  bool
+	policy  Backpressure
+	dropped *atomic.Uint64
+}
+
+func newQueue(size int, policy Backpressure, dropped *atomic.Uint64) *queue {
+	return &queue{
+		ch:      make(chan *Message, size),
+		done:    make(chan struct{}),
+		policy:  policy,
+		dropped: dropped,
+	}
+}
+
+// push a message onto the queue, applying the backpressure policy when full.
+func (q *queue) push(m *Message) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return
+	}
+
+	switch q.policy {
+	case BackpressureDropNewest:
+		select {
+		case q.ch <- m:
+		default:
+			q.dropped.Add(1)
+		}
+	case BackpressureDropOldest:
+		for {
+			select {
+			case q.ch <- m:
+				return
+			default:
+			}
+
+			select {
+			case <-q.ch:
+				q.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case q.ch <- m:
+		case <-q.done:
+		}
+	}
+}
+
+// close the queue, unblocking any push waiting on a full buffer. Buffered messages
+// remain to be read.
+func (q *queue) close() {
+	q.once.Do(func() {
+		close(q.done)
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.closed = true
+		close(q.ch)
+	})
+}
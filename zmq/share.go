@@ -0,0 +1,154 @@
+package zmq
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// shared the handlers of topics subscribed to through the NodeMQs returned by Share, each
+// topic being subscribed to once on the NodeMQ they are shared from.
+type shared struct {
+	mu       sync.Mutex
+	n        *nodeMq
+	handlers map[Topic][]*sharedHandler
+}
+
+// sharedHandler a handler of a topic, along with the shared NodeMQ it was subscribed through.
+type sharedHandler struct {
+	owner *sharedMq
+	fn    MessageFunc
+}
+
+// sharedMq a NodeMQ returned by Share, subscribing to topics alongside the others shared from
+// the same NodeMQ.
+type sharedMq struct {
+	*nodeMq
+	s *shared
+}
+
+// Share returns a NodeMQ subscribing to the topics of z alongside any other NodeMQ shared from
+// it, so packages each claiming the same topics, such as bn.SubscribeCacheInvalidation and the
+// Subscribe of a chain.Follower, headers.Store, mempool.Mirror or tracker.Tracker, can be used
+// together on one connection. Each topic is subscribed to on z once, by the first handler of
+// it, with its messages passed to every handler in the order they were subscribed. Unsubscribe
+// removes only the handler subscribed through the returned NodeMQ, the topic being unsubscribed
+// from on z once it has no handlers left.
+//
+// A topic is still subscribed to once per shared NodeMQ, as on z itself, while handlers
+// subscribed directly on z are not shared, and claim their topics from those which are.
+// Connect, Channel, Messages, Dropped and Close act on z. NodeMQs other than those returned by
+// NewNodeMQ and Share, such as mocks, are returned as they are.
+func Share(z NodeMQ) NodeMQ {
+	switch z := z.(type) {
+	case *nodeMq:
+		return &sharedMq{nodeMq: z, s: z.shared}
+	case *sharedMq:
+		return &sharedMq{nodeMq: z.nodeMq, s: z.s}
+	}
+	return z
+}
+
+// Subscribe to a topic alongside the other handlers of it shared from the same NodeMQ.
+func (m *sharedMq) Subscribe(topic Topic, fn MessageFunc) error {
+	return m.s.subscribe(m, topic, fn)
+}
+
+// SubscribeHashTx subscribe to `hashtx` and receive its messages parsed.
+func (m *sharedMq) SubscribeHashTx(fn HashFunc) error {
+	return m.Subscribe(TopicHashTx, hashMessage(fn))
+}
+
+// SubscribeHashBlock subscribe to `hashblock` and receive its messages parsed.
+func (m *sharedMq) SubscribeHashBlock(fn HashFunc) error {
+	return m.Subscribe(TopicHashBlock, hashMessage(fn))
+}
+
+// SubscribeDiscardFromMempool subscribe to `discardfrommempool` and receive its messages parsed.
+func (m *sharedMq) SubscribeDiscardFromMempool(fn DiscardFunc) error {
+	return m.Subscribe(TopicDiscardFromMempool, discardMessage(fn, m.onErrFn))
+}
+
+// SubscribeRemovedFromMempoolBlock subscribe to `removedfrommempoolblock` and receive its messages parsed.
+func (m *sharedMq) SubscribeRemovedFromMempoolBlock(fn DiscardFunc) error {
+	return m.Subscribe(TopicRemovedFromMempoolBlock, discardMessage(fn, m.onErrFn))
+}
+
+// SubscribeInvalidTx subscribe to `invalidtx` and receive its messages parsed.
+func (m *sharedMq) SubscribeInvalidTx(fn InvalidTxFunc) error {
+	return m.Subscribe(TopicInvalidTx, invalidTxMessage(fn, m.onErrFn))
+}
+
+// SubscribeRawTx subscribe to `rawtx` and receive its messages parsed.
+func (m *sharedMq) SubscribeRawTx(fn RawTxFunc) error {
+	return m.Subscribe(TopicRawTx, rawTxMessage(fn, m.onErrFn))
+}
+
+// SubscribeRawBlock subscribe to `rawblock` and receive its messages parsed.
+func (m *sharedMq) SubscribeRawBlock(fn RawBlockFunc) error {
+	return m.Subscribe(TopicRawBlock, rawBlockMessage(fn, m.onErrFn))
+}
+
+// Unsubscribe removes the handler of a topic subscribed through this NodeMQ, leaving those of
+// the others shared from the same NodeMQ.
+func (m *sharedMq) Unsubscribe(topic Topic) error {
+	return m.s.unsubscribe(m, topic)
+}
+
+// subscribe adds the handler of the owner for the topic, subscribing to the topic on the
+// NodeMQ when it is the first.
+func (s *shared) subscribe(owner *sharedMq, topic Topic, fn MessageFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hh := s.handlers[topic]
+	if slices.ContainsFunc(hh, func(h *sharedHandler) bool { return h.owner == owner }) {
+		return fmt.Errorf("%w: %s", ErrAlreadySubscribed, topic)
+	}
+	if len(hh) == 0 {
+		if err := s.n.Subscribe(topic, func(ctx context.Context, frames [][]byte) {
+			s.dispatch(ctx, topic, frames)
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.handlers[topic] = append(hh, &sharedHandler{owner: owner, fn: fn})
+	return nil
+}
+
+// unsubscribe removes the handler of the owner for the topic, unsubscribing from the topic on
+// the NodeMQ when it was the last.
+func (s *shared) unsubscribe(owner *sharedMq, topic Topic) error {
+	if ok := s.n.cfg.topics[topic]; !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidTopic, topic)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hh := s.handlers[topic]
+	i := slices.IndexFunc(hh, func(h *sharedHandler) bool { return h.owner == owner })
+	if i < 0 {
+		return nil
+	}
+	if len(hh) > 1 {
+		s.handlers[topic] = slices.Delete(slices.Clone(hh), i, i+1)
+		return nil
+	}
+
+	delete(s.handlers, topic)
+	return s.n.Unsubscribe(topic)
+}
+
+// dispatch a message to each handler of its topic.
+func (s *shared) dispatch(ctx context.Context, topic Topic, frames [][]byte) {
+	s.mu.Lock()
+	hh := s.handlers[topic]
+	s.mu.Unlock()
+
+	for _, h := range hh {
+		h.fn(ctx, frames)
+	}
+}
@@ -0,0 +1,102 @@
+package zmq_test
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"github.com/go-zeromq/zmq4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/zmq"
+)
+
+func TestShare(t *testing.T) {
+	t.Parallel()
+
+	frames := func(topic zmq.Topic, hash string) zmq4.Msg {
+		body, err := hex.DecodeString(hash)
+		require.NoError(t, err)
+		return zmq4.Msg{Frames: [][]byte{[]byte(topic), body}}
+	}
+	messages := []zmq4.Msg{
+		frames(zmq.TopicHashBlock, "000000000000000001cd535a5b3ad0fb3ec22d153e845508666818ab29eb27af"),
+		frames(zmq.TopicHashTx, "b9037b78403cc4e76a06060b4e9d1e1cdf7c85ce7cb6f074e1f9ed2fb6aa10a6"),
+		frames(zmq.TopicHashBlock, "000000000000000002d8a4faad6d8c026526bf1a8a2abe2074a42e71f95ebb64"),
+	}
+
+	var mu sync.Mutex
+	var options []string
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(name string, v interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			options = append(options, name+" "+v.(string))
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			if len(messages) == 0 {
+				return zmq4.Msg{}, context.Canceled
+			}
+			defer func() { messages = messages[1:] }()
+			return messages[0], nil
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(5)
+	received := map[string][]string{}
+	handler := func(name string) zmq.HashFunc {
+		return func(_ context.Context, hash string) {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			received[name] = append(received[name], hash[len(hash)-4:])
+		}
+	}
+
+	a, b := zmq.Share(z), zmq.Share(z)
+	require.NoError(t, a.SubscribeHashBlock(handler("a")))
+	require.NoError(t, b.SubscribeHashBlock(handler("b")))
+	require.NoError(t, b.SubscribeHashTx(handler("b")))
+
+	// Topics are claimed once per shared NodeMQ, and from z itself.
+	require.ErrorIs(t, a.SubscribeHashBlock(handler("a")), zmq.ErrAlreadySubscribed)
+	require.ErrorIs(t, z.SubscribeHashBlock(handler("z")), zmq.ErrAlreadySubscribed)
+
+	// Unsubscribing leaves the handlers of others in place.
+	c := zmq.Share(a)
+	require.NoError(t, c.SubscribeHashBlock(handler("c")))
+	require.NoError(t, c.Unsubscribe(zmq.TopicHashBlock))
+
+	require.NoError(t, z.Connect())
+	wg.Wait()
+
+	assert.Len(t, received, 2)
+	assert.ElementsMatch(t, []string{"27af", "bb64"}, received["a"])
+	assert.ElementsMatch(t, []string{"27af", "10a6", "bb64"}, received["b"])
+	assert.ElementsMatch(t, []string{
+		zmq4.OptionSubscribe + " " + string(zmq.TopicHashBlock),
+		zmq4.OptionSubscribe + " " + string(zmq.TopicHashTx),
+	}, options)
+
+	// The topic is released once its last handler is unsubscribed.
+	require.NoError(t, a.Unsubscribe(zmq.TopicHashBlock))
+	require.ErrorIs(t, z.SubscribeHashBlock(handler("z")), zmq.ErrAlreadySubscribed)
+	require.NoError(t, b.Unsubscribe(zmq.TopicHashBlock))
+	require.NoError(t, z.SubscribeHashBlock(handler("z")))
+	require.ErrorIs(t, b.Unsubscribe("oh hello there"), zmq.ErrInvalidTopic)
+}
@@ -13,18 +13,39 @@ type MessageFunc func(ctx context.Context, bb [][]byte)
 // ErrorFunc a func in which an error is passed to.
 type ErrorFunc func(ctx context.Context, err error)
 
+// ConnectFunc a func called when a connection to host is established.
+type ConnectFunc func(ctx context.Context, host string)
+
+// DisconnectFunc a func called when the connection to host is lost, along with the cause.
+type DisconnectFunc func(ctx context.Context, host string, err error)
+
+// GapFunc a func called when messages on a topic have been missed.
+type GapFunc func(ctx context.Context, gap *SequenceGap)
+
 // HashFunc a func in which `hashtx` and `hashblock` results are passed to.
 type HashFunc func(ctx context.Context, hash string)
 
 // DiscardFunc a func in which `hashtx` and `hashblock` results are passed to.
 type DiscardFunc func(ctx context.Context, discard *MempoolDiscard)
 
+// InvalidTxFunc a func in which `invalidtx` results are parsed and passed to.
+type InvalidTxFunc func(ctx context.Context, tx *InvalidTx)
+
 // RawTxFunc a func in which `rawtx` results are parsed and passed to.
 type RawTxFunc func(ctx context.Context, tx *bt.Tx)
 
 // RawBlockFunc a func in which `rawblock` results are parsed and passed to.
 type RawBlockFunc func(ctx context.Context, blk *bc.Block)
 
+type sequenceKey struct{}
+
+// SequenceFromContext returns the sequence number of the message being handled, from the
+// context passed to a subscription handler.
+func SequenceFromContext(ctx context.Context) (uint32, bool) {
+	seq, ok := ctx.Value(sequenceKey{}).(uint32)
+	return seq, ok
+}
+
 // MempoolDiscard a JSON representation of `discardfrommempool` and `removedfrommempoolblock`
 // messages.
 type MempoolDiscard struct {
@@ -37,3 +58,40 @@ type MempoolDiscard struct {
 		Tx   *bt.Tx `json:"tx"`
 	} `json:"collidedWith"`
 }
+
+// InvalidTx a JSON representation of `invalidtx` messages, published when the node rejects
+// a transaction. Hex is omitted by the node for transactions over its size limit, in which
+// case Tx is nil.
+type InvalidTx struct {
+	FromBlock                   bool                 `json:"fromBlock"`
+	TxID                        string               `json:"txid"`
+	Hex                         string               `json:"hex"`
+	Tx                          *bt.Tx               `json:"-"`
+	BlockHash                   string               `json:"blockhash"`
+	BlockHeight                 int64                `json:"blockheight"`
+	BlockTime                   int64                `json:"blocktime"`
+	Source                      string               `json:"source"`
+	Address                     string               `json:"address"`
+	NodeID                      int64                `json:"nodeId"`
+	Size                        int64                `json:"size"`
+	IsInvalid                   bool                 `json:"isInvalid"`
+	IsValidationError           bool                 `json:"isValidationError"`
+	IsMissingInputs             bool                 `json:"isMissingInputs"`
+	IsDoubleSpendDetected       bool                 `json:"isDoubleSpendDetected"`
+	IsMempoolConflictDetected   bool                 `json:"isMempoolConflictDetected"`
+	IsNonFinal                  bool                 `json:"isNonFinal"`
+	IsValidationTimeoutExceeded bool                 `json:"isValidationTimeoutExceeded"`
+	IsStandardTx                bool                 `json:"isStandardTx"`
+	RejectionCode               int                  `json:"rejectionCode"`
+	RejectionReason             string               `json:"rejectionReason"`
+	CollidedWith                []InvalidTxCollision `json:"collidedWith"`
+	RejectionTime               string               `json:"rejectionTime"`
+}
+
+// InvalidTxCollision a transaction an `invalidtx` transaction collided with, spending
+// the same inputs.
+type InvalidTxCollision struct {
+	TxID string `json:"txid"`
+	Size int64  `json:"size"`
+	Hex  string `json:"hex"`
+}
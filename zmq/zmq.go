@@ -2,12 +2,20 @@ package zmq
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bsv-blockchain/go-bc"
 	"github.com/bsv-blockchain/go-bt/v2"
@@ -31,11 +39,55 @@ const (
 
 type nodeMq struct {
 	mu            sync.RWMutex
-	conn          zmq4.Socket
-	connected     bool
+	seqMu         sync.Mutex
+	connMu        sync.Mutex
+	stopping      atomic.Bool
+	closed        bool
+	quit          chan struct{}
+	running       chan struct{}
+	active        []*endpoint
+	initial       zmq4.Socket
+	handlers      sync.WaitGroup
 	onErrFn       ErrorFunc
 	cfg           *nodeMqCfg
-	subscriptions map[Topic]MessageFunc
+	subscriptions map[Topic]*subscription
+	seqs          map[Topic]uint32
+	dropped       map[Topic]*atomic.Uint64
+	shared        *shared
+}
+
+// subscription the handler of a topic, either called for each message or, when
+// delivering in order, fed by a queue.
+type subscription struct {
+	fn MessageFunc
+	q  *queue
+}
+
+// endpoint a single address the node publishes on.
+type endpoint struct {
+	mu        sync.Mutex
+	host      string
+	conn      zmq4.Socket
+	connected bool
+	closed    bool
+}
+
+// socket returns the current socket of the endpoint.
+func (e *endpoint) socket() zmq4.Socket {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.conn
+}
+
+// close the current socket of the endpoint, unless already closed.
+func (e *endpoint) close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.conn.Close()
 }
 
 // NodeMQ interfaces connecting and subscribing to a bitcoin node NodeMQ connection.
@@ -46,195 +98,776 @@ type NodeMQ interface {
 	SubscribeHashBlock(fn HashFunc) error
 	SubscribeDiscardFromMempool(fn DiscardFunc) error
 	SubscribeRemovedFromMempoolBlock(fn DiscardFunc) error
+	SubscribeInvalidTx(fn InvalidTxFunc) error
 	SubscribeRawTx(fn RawTxFunc) error
 	SubscribeRawBlock(fn RawBlockFunc) error
+	Channel(topic Topic) (<-chan *Message, error)
+	Messages(topic Topic) (iter.Seq[*Message], error)
+	Dropped(topic Topic) uint64
 	Unsubscribe(topic Topic) error
+	Close(ctx context.Context) error
 }
 
 // NewNodeMQ build and return a new zmq.ZMQ configured via the provided opt funcs.
 func NewNodeMQ(oo ...NodeMQOptFunc) NodeMQ {
 	cfg := &nodeMqCfg{
-		optionValue: "hash",
-		errorFn:     defaultOnError,
+		errorFn:      defaultOnError,
+		bufferSize:   DefaultBufferSize,
+		backpressure: BackpressureBlock,
 		topics: map[Topic]bool{
 			TopicHashBlock:               true,
 			TopicHashTx:                  true,
 			TopicDiscardFromMempool:      true,
 			TopicRemovedFromMempoolBlock: true,
 			TopicInvalidTx:               true,
-
-			TopicRawTx:    false,
-			TopicRawBlock: false,
+			TopicRawTx:                   true,
+			TopicRawBlock:                true,
 		},
 	}
 	for _, o := range oo {
 		o(cfg)
 	}
 
+	if cfg.ctx == nil {
+		cfg.ctx = context.Background()
+	}
+	if cfg.socketFn == nil {
+		cfg.socketFn = func() zmq4.Socket {
+			return zmq4.NewSub(cfg.ctx, zmq4.WithID(zmq4.SocketIdentity("sub")))
+		}
+	}
 	if cfg.zmqSocket == nil {
-		cfg.zmqSocket = zmq4.NewSub(cfg.ctx, zmq4.WithID(zmq4.SocketIdentity("sub")))
+		cfg.zmqSocket = cfg.socketFn()
 	}
 
-	return &nodeMq{
+	n := &nodeMq{
 		cfg:           cfg,
-		subscriptions: make(map[Topic]MessageFunc),
+		initial:       cfg.zmqSocket,
+		subscriptions: make(map[Topic]*subscription),
+		seqs:          make(map[Topic]uint32),
+		dropped:       make(map[Topic]*atomic.Uint64),
+		quit:          make(chan struct{}),
 		onErrFn:       cfg.errorFn,
-		conn:          cfg.zmqSocket,
 	}
+	n.shared = &shared{
+		n:        n,
+		handlers: make(map[Topic][]*sharedHandler),
+	}
+
+	return n
 }
 
-// Connect to the bitcoin node 0MQ.
+// Connect to the bitcoin node 0MQ, receiving messages until the context set WithContext
+// is cancelled. When WithReconnect is set, a failed connection is re-established with
+// backoff rather than returned. When topics are published on several endpoints, each is
+// connected to concurrently, the first error stopping them all.
 func (n *nodeMq) Connect() error {
 	if err := n.cfg.validate(); err != nil {
 		return err
 	}
 
-	if err := n.conn.Dial(n.cfg.host); err != nil {
+	var endpoints []*endpoint
+	running := make(chan struct{})
+	if err := func() error {
+		n.connMu.Lock()
+		defer n.connMu.Unlock()
+		if n.closed {
+			return ErrClosed
+		}
+		endpoints = n.endpoints()
+		n.stopping.Store(false)
+		n.active = endpoints
+		n.running = running
+		return nil
+	}(); err != nil {
 		return err
 	}
 
+	defer close(running)
+	defer n.closeQueues()
+	defer n.release(endpoints)
+
+	if len(endpoints) == 1 {
+		return n.run(endpoints[0])
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, e := range endpoints {
+		wg.Go(func() {
+			err := n.run(e)
+			if err == nil {
+				return
+			}
+
+			once.Do(func() {
+				firstErr = err
+				n.stop(endpoints, false)
+			})
+		})
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// endpoints returns an endpoint for each distinct address, the host set WithHost serving
+// all topics without an endpoint of their own. Each endpoint has a socket of its own, the
+// first using the socket set WithCustomZMQSocket on the first connect, and the rest a new
+// socket from the factory.
+func (n *nodeMq) endpoints() []*endpoint {
+	hosts := make([]string, 0, len(n.cfg.endpoints)+1)
+	if n.cfg.host != "" {
+		hosts = append(hosts, n.cfg.host)
+	}
+	for _, topic := range slices.Sorted(maps.Keys(n.cfg.endpoints)) {
+		if !n.cfg.topics[topic] {
+			continue
+		}
+		if host := n.endpoint(topic); !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	endpoints := make([]*endpoint, len(hosts))
+	for i, host := range hosts {
+		endpoints[i] = &endpoint{host: host}
+		if i == 0 && n.initial != nil {
+			endpoints[i].conn, n.initial = n.initial, nil
+		} else {
+			endpoints[i].conn = n.cfg.socketFn()
+		}
+	}
+
+	return endpoints
+}
+
+// endpoint returns the address topic is published on.
+func (n *nodeMq) endpoint(topic Topic) string {
+	host, ok := n.cfg.endpoints[topic]
+	if !ok {
+		return n.cfg.host
+	}
+
+	// Nodes bound to all interfaces report a wildcard address, so dial the node's host instead.
+	u, err := url.Parse(host)
+	if err != nil || n.cfg.host == "" {
+		return host
+	}
+	switch u.Hostname() {
+	case "0.0.0.0", "*", "::":
+		if h, err := url.Parse(n.cfg.host); err == nil {
+			u.Host = net.JoinHostPort(h.Hostname(), u.Port())
+			return u.String()
+		}
+	}
+
+	return host
+}
+
+// release closes whatever sockets of the endpoints remain open once Connect returns, leaving
+// nothing for Close to stop.
+func (n *nodeMq) release(endpoints []*endpoint) {
+	n.connMu.Lock()
+	defer n.connMu.Unlock()
+
+	n.stopping.Store(true)
+	for _, e := range endpoints {
+		if err := e.close(); err != nil {
+			n.onErrFn(context.Background(), err)
+		}
+	}
+	n.active = nil
+}
+
+// run connects to the endpoint, reconnecting on failure when WithReconnect is set. Errors
+// arising once stopped, such as dialling a socket closed under it, are a clean exit.
+func (n *nodeMq) run(e *endpoint) error {
+	for attempt := 1; ; attempt++ {
+		established, err := n.connect(e)
+		if n.stopping.Load() {
+			return nil
+		}
+		if err == nil || !n.cfg.reconnect {
+			return err
+		}
+		if established {
+			attempt = 1
+		}
+
+		n.onErrFn(n.cfg.ctx, err)
+		if n.cfg.onDisconnect != nil {
+			n.cfg.onDisconnect(n.cfg.ctx, e.host, err)
+		}
+
+		if err = n.wait(attempt); err != nil {
+			return nil //nolint:nilerr // a cancelled context is a clean shutdown
+		}
+
+		e.mu.Lock()
+		if n.stopping.Load() {
+			e.mu.Unlock()
+			return nil
+		}
+		e.conn = n.cfg.socketFn()
+		e.closed = false
+		e.mu.Unlock()
+	}
+}
+
+// stop receiving on the endpoints, closing those sockets still open. When unsubscribe is set
+// the subscriptions are first cleared on each socket.
+func (n *nodeMq) stop(endpoints []*endpoint, unsubscribe bool) {
+	n.stopping.Store(true)
+	for _, e := range endpoints {
+		e.mu.Lock()
+		if !e.closed {
+			if unsubscribe {
+				for _, prefix := range n.prefixes(e) {
+					_ = e.conn.SetOption(zmq4.OptionUnsubscribe, prefix)
+				}
+			}
+			if err := e.conn.Close(); err != nil {
+				n.onErrFn(context.Background(), err)
+			}
+			e.closed = true
+		}
+		e.mu.Unlock()
+	}
+}
+
+// prefixes returns the message prefixes to subscribe to on the socket of the endpoint,
+// being each subscribed topic it serves.
+func (n *nodeMq) prefixes(e *endpoint) []string {
+	var prefixes []string
+	if n.cfg.optionValue != "" {
+		prefixes = append(prefixes, n.cfg.optionValue)
+	}
+
+	n.mu.RLock()
+	topics := slices.Sorted(maps.Keys(n.subscriptions))
+	n.mu.RUnlock()
+	for _, topic := range topics {
+		if n.endpoint(topic) == e.host {
+			prefixes = append(prefixes, string(topic))
+		}
+	}
+
+	return prefixes
+}
+
+// setOption sets a socket option for the topic on the endpoint serving it, if connected.
+func (n *nodeMq) setOption(name string, topic Topic) error {
+	n.connMu.Lock()
+	defer n.connMu.Unlock()
+
+	if n.running == nil || n.stopping.Load() {
+		return nil
+	}
+	select {
+	case <-n.running:
+		return nil
+	default:
+	}
+
+	host := n.endpoint(topic)
+	for _, e := range n.active {
+		if e.host != host {
+			continue
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.closed {
+			return nil
+		}
+		return e.conn.SetOption(name, string(topic))
+	}
+
+	return nil
+}
+
+// Close stops receiving messages, clearing the subscriptions of each socket and closing it,
+// making Connect return without error. It then waits for handlers still running, and for
+// subscriptions delivered in order to drain, until ctx is done. Sockets are only closed once,
+// so Close after Connect has returned only waits on the handlers. Once closed, Connect
+// returns ErrClosed.
+func (n *nodeMq) Close(ctx context.Context) error {
+	n.connMu.Lock()
+	if n.closed {
+		n.connMu.Unlock()
+		return nil
+	}
+	n.closed = true
+	close(n.quit)
+	running := n.running
+	if n.initial != nil {
+		if err := n.initial.Close(); err != nil {
+			n.onErrFn(context.Background(), err)
+		}
+		n.initial = nil
+	}
+	n.stop(n.active, true)
+	n.connMu.Unlock()
+
+	if running != nil {
+		select {
+		case <-running:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	n.closeQueues()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		n.handlers.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// connect dials the endpoint and receives messages until the context is cancelled or, when
+// reconnecting, the connection fails. It reports whether the socket was dialled and subscribed.
+func (n *nodeMq) connect(e *endpoint) (bool, error) {
+	conn := e.socket()
+	if err := conn.Dial(e.host); err != nil {
+		// The socket is replaced on reconnect, so is closed rather than left dialling.
+		if n.cfg.reconnect {
+			_ = e.close()
+		}
+		return false, err
+	}
+
 	defer func() {
-		if !n.connected {
+		if (!e.connected && !n.cfg.reconnect) || n.stopping.Load() {
 			return
 		}
 
-		if err := n.conn.Close(); err != nil {
+		if err := e.close(); err != nil {
 			n.onErrFn(context.Background(), err)
 		}
-		n.connected = false
+		e.connected = false
 	}()
 
-	if err := n.conn.SetOption(zmq4.OptionSubscribe, n.cfg.optionValue); err != nil {
-		return err
+	for _, prefix := range n.prefixes(e) {
+		if err := conn.SetOption(zmq4.OptionSubscribe, prefix); err != nil {
+			return false, err
+		}
 	}
 
-	if n.cfg.raw {
-		if err := n.conn.SetOption(zmq4.OptionSubscribe, "raw"); err != nil {
-			return err
-		}
+	if n.cfg.onConnect != nil {
+		n.cfg.onConnect(n.cfg.ctx, e.host)
+	}
+
+	var lastSeen atomic.Int64
+	lastSeen.Store(time.Now().UnixNano())
+	if n.cfg.staleAfter > 0 && (n.cfg.staleTopic == "" || n.endpoint(n.cfg.staleTopic) == e.host) {
+		done := make(chan struct{})
+		defer close(done)
+		go n.watch(e, &lastSeen, done)
 	}
 
 	for {
-		msg, err := n.conn.Recv()
+		msg, err := conn.Recv()
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil
+			if errors.Is(err, context.Canceled) || n.cfg.ctx.Err() != nil || n.stopping.Load() {
+				return true, nil
+			}
+			if n.cfg.reconnect {
+				return true, err
 			}
 
 			n.onErrFn(context.Background(), err)
 			continue
 		}
-		n.connected = true
-		func() {
-			n.mu.RLock()
-			defer n.mu.RUnlock()
+		e.connected = true
+		if n.cfg.staleTopic == "" || Topic(msg.Frames[0]) == n.cfg.staleTopic {
+			lastSeen.Store(time.Now().UnixNano())
+		}
+		n.dispatch(n.sequence(msg.Frames), msg.Frames)
+	}
+}
+
+// sequence tracks the sequence number carried in the third frame of a message, reporting
+// a gap when it does not follow on from the last message of its topic. The returned context
+// carries the sequence number for SequenceFromContext.
+func (n *nodeMq) sequence(frames [][]byte) context.Context {
+	ctx := context.Background()
+	if len(frames) < 3 || len(frames[2]) != 4 {
+		return ctx
+	}
 
-			if fn, ok := n.subscriptions[Topic(msg.Frames[0])]; ok {
-				go fn(context.Background(), msg.Frames)
+	topic := Topic(frames[0])
+	seq := binary.LittleEndian.Uint32(frames[2])
+
+	n.seqMu.Lock()
+	last, ok := n.seqs[topic]
+	n.seqs[topic] = seq
+	n.seqMu.Unlock()
+
+	if ok && seq != last+1 {
+		gap := &SequenceGap{
+			Topic:    topic,
+			Expected: last + 1,
+			Received: seq,
+		}
+		if n.cfg.gapFn != nil {
+			n.cfg.gapFn(ctx, gap)
+		} else {
+			n.onErrFn(ctx, gap)
+		}
+	}
+
+	return context.WithValue(ctx, sequenceKey{}, seq)
+}
+
+// watch reports ErrStale when no message has been seen within the staleness timeout, closing
+// the socket to force a reconnect when WithReconnect is set.
+func (n *nodeMq) watch(e *endpoint, lastSeen *atomic.Int64, done <-chan struct{}) {
+	t := time.NewTicker(max(n.cfg.staleAfter/2, time.Millisecond))
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if time.Since(time.Unix(0, lastSeen.Load())) < n.cfg.staleAfter {
+				continue
 			}
-		}()
+
+			n.onErrFn(n.cfg.ctx, fmt.Errorf("%w: nothing received for %s", ErrStale, n.cfg.staleAfter))
+			if n.cfg.reconnect {
+				_ = e.close()
+				return
+			}
+			lastSeen.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// wait sleeps for the backoff delay of the given reconnect attempt, or until the context is done.
+func (n *nodeMq) wait(attempt int) error {
+	delay := n.cfg.reconnectBaseDelay << (attempt - 1)
+	if delay <= 0 || (n.cfg.reconnectMaxDelay > 0 && delay > n.cfg.reconnectMaxDelay) {
+		delay = n.cfg.reconnectMaxDelay
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-n.cfg.ctx.Done():
+		return n.cfg.ctx.Err()
+	case <-n.quit:
+		return ErrClosed
+	case <-t.C:
+		return nil
 	}
 }
 
-// Subscribe to a topic on a bitcoin node 0MQ.
+// Subscribe to a topic on a bitcoin node 0MQ. Only messages of subscribed topics are
+// received on the socket.
 func (n *nodeMq) Subscribe(topic Topic, fn MessageFunc) error {
-	if ok := n.cfg.topics[topic]; !ok {
-		return fmt.Errorf("%w: %s", ErrInvalidTopic, topic)
+	if !n.cfg.ordered {
+		return n.subscribe(topic, &subscription{fn: fn})
+	}
+
+	q, err := n.newQueue(topic)
+	if err != nil {
+		return err
+	}
+	if err := n.subscribe(topic, &subscription{q: q}); err != nil {
+		return err
+	}
+
+	n.handlers.Go(func() {
+		for m := range q.ch {
+			fn(m.Context(), m.Frames)
+		}
+	})
+
+	return nil
+}
+
+// Channel subscribe to a topic, receiving its messages in order on the returned channel.
+// The channel is buffered as set WithBufferSize, with WithBackpressure deciding what happens
+// when it is full, and is closed on Unsubscribe or when Connect returns.
+func (n *nodeMq) Channel(topic Topic) (<-chan *Message, error) {
+	q, err := n.newQueue(topic)
+	if err != nil {
+		return nil, err
 	}
+	if err := n.subscribe(topic, &subscription{q: q}); err != nil {
+		return nil, err
+	}
+
+	return q.ch, nil
+}
 
-	if !n.cfg.allowOverwrite {
-		if err := func() error {
-			n.mu.RLock()
-			defer n.mu.RUnlock()
-			if _, ok := n.subscriptions[topic]; ok {
-				return fmt.Errorf("%w: %s", ErrAlreadySubscribed, topic)
+// Messages subscribe to a topic, receiving its messages in order as an iterator. It is
+// buffered in the same way as Channel, breaking from the loop unsubscribing from the topic:
+//
+//	msgs, err := z.Messages(zmq.TopicHashBlock)
+//	if err != nil {}
+//	for msg := range msgs {
+//		log.Println("block", hex.EncodeToString(msg.Frames[1]))
+//	}
+func (n *nodeMq) Messages(topic Topic) (iter.Seq[*Message], error) {
+	ch, err := n.Channel(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(*Message) bool) {
+		for m := range ch {
+			if !yield(m) {
+				_ = n.Unsubscribe(topic)
+				return
 			}
-			return nil
-		}(); err != nil {
-			return err
 		}
+	}, nil
+}
+
+// Dropped returns the number of messages on a topic dropped under the backpressure policy.
+func (n *nodeMq) Dropped(topic Topic) uint64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if dropped, ok := n.dropped[topic]; ok {
+		return dropped.Load()
+	}
+	return 0
+}
+
+func (n *nodeMq) newQueue(topic Topic) (*queue, error) {
+	if err := n.cfg.validateBuffer(); err != nil {
+		return nil, err
 	}
 
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	n.subscriptions[topic] = fn
+	dropped, ok := n.dropped[topic]
+	if !ok {
+		dropped = &atomic.Uint64{}
+		n.dropped[topic] = dropped
+	}
+
+	return newQueue(n.cfg.bufferSize, n.cfg.backpressure, dropped), nil
+}
+
+// subscribe registers the subscription for the topic, subscribing to it on the socket
+// when connected.
+func (n *nodeMq) subscribe(topic Topic, sub *subscription) error {
+	if ok := n.cfg.topics[topic]; !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidTopic, topic)
+	}
+
+	if err := func() error {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		prev, ok := n.subscriptions[topic]
+		if ok && !n.cfg.allowOverwrite {
+			return fmt.Errorf("%w: %s", ErrAlreadySubscribed, topic)
+		}
+		if ok && prev.q != nil {
+			prev.q.close()
+		}
+
+		n.subscriptions[topic] = sub
+		return nil
+	}(); err != nil {
+		return err
+	}
+
+	if err := n.setOption(zmq4.OptionSubscribe, topic); err != nil {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subscriptions, topic)
+		if sub.q != nil {
+			sub.q.close()
+		}
+		return err
+	}
+
 	return nil
 }
 
+// dispatch a message to the subscription of its topic.
+func (n *nodeMq) dispatch(ctx context.Context, frames [][]byte) {
+	topic := Topic(frames[0])
+
+	n.mu.RLock()
+	sub, ok := n.subscriptions[topic]
+	n.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if sub.q == nil {
+		n.handlers.Go(func() {
+			sub.fn(ctx, frames)
+		})
+		return
+	}
+
+	seq, hasSeq := SequenceFromContext(ctx)
+	sub.q.push(&Message{
+		Topic:       topic,
+		Frames:      frames,
+		Sequence:    seq,
+		HasSequence: hasSeq,
+		ctx:         ctx,
+	})
+}
+
+// closeQueues closes the queues of subscriptions delivered in order, ending them.
+func (n *nodeMq) closeQueues() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for topic, sub := range n.subscriptions {
+		if sub.q != nil {
+			sub.q.close()
+			delete(n.subscriptions, topic)
+		}
+	}
+}
+
 // SubscribeHashTx subscribe to `hashtx` and receive its messages parsed.
 func (n *nodeMq) SubscribeHashTx(fn HashFunc) error {
-	return n.Subscribe(TopicHashTx, func(ctx context.Context, bb [][]byte) {
-		fn(ctx, hex.EncodeToString(bb[1]))
-	})
+	return n.Subscribe(TopicHashTx, hashMessage(fn))
 }
 
 // SubscribeHashBlock subscribe to `hashblock` and receive its messages parsed.
 func (n *nodeMq) SubscribeHashBlock(fn HashFunc) error {
-	return n.Subscribe(TopicHashBlock, func(ctx context.Context, bb [][]byte) {
-		fn(ctx, hex.EncodeToString(bb[1]))
-	})
+	return n.Subscribe(TopicHashBlock, hashMessage(fn))
 }
 
 // SubscribeDiscardFromMempool subscribe to `discardfrommempool` and receive its messages parsed.
 func (n *nodeMq) SubscribeDiscardFromMempool(fn DiscardFunc) error {
-	return n.Subscribe(TopicDiscardFromMempool, func(ctx context.Context, bb [][]byte) {
-		var d MempoolDiscard
-		if err := json.Unmarshal(bb[1], &d); err != nil {
-			n.onErrFn(ctx, err)
-			return
-		}
-		fn(ctx, &d)
-	})
+	return n.Subscribe(TopicDiscardFromMempool, discardMessage(fn, n.onErrFn))
 }
 
 // SubscribeRemovedFromMempoolBlock subscribe to `removedfrommempoolblock` and receive its messages parsed.
 func (n *nodeMq) SubscribeRemovedFromMempoolBlock(fn DiscardFunc) error {
-	return n.Subscribe(TopicRemovedFromMempoolBlock, func(ctx context.Context, bb [][]byte) {
+	return n.Subscribe(TopicRemovedFromMempoolBlock, discardMessage(fn, n.onErrFn))
+}
+
+// SubscribeInvalidTx subscribe to `invalidtx` and receive its messages parsed.
+func (n *nodeMq) SubscribeInvalidTx(fn InvalidTxFunc) error {
+	return n.Subscribe(TopicInvalidTx, invalidTxMessage(fn, n.onErrFn))
+}
+
+// SubscribeRawTx subscribe to `rawtx` and receive its messages parsed.
+func (n *nodeMq) SubscribeRawTx(fn RawTxFunc) error {
+	return n.Subscribe(TopicRawTx, rawTxMessage(fn, n.onErrFn))
+}
+
+// SubscribeRawBlock subscribe to `rawblock` and receive its messages parsed.
+func (n *nodeMq) SubscribeRawBlock(fn RawBlockFunc) error {
+	return n.Subscribe(TopicRawBlock, rawBlockMessage(fn, n.onErrFn))
+}
+
+// hashMessage parses messages of the `hashtx` and `hashblock` topics for fn.
+func hashMessage(fn HashFunc) MessageFunc {
+	return func(ctx context.Context, bb [][]byte) {
+		fn(ctx, hex.EncodeToString(bb[1]))
+	}
+}
+
+// discardMessage parses messages of the `discardfrommempool` and `removedfrommempoolblock`
+// topics for fn, passing those which cannot be parsed to errFn.
+func discardMessage(fn DiscardFunc, errFn ErrorFunc) MessageFunc {
+	return func(ctx context.Context, bb [][]byte) {
 		var d MempoolDiscard
 		if err := json.Unmarshal(bb[1], &d); err != nil {
-			n.onErrFn(ctx, err)
+			errFn(ctx, err)
 			return
 		}
 		fn(ctx, &d)
-	})
+	}
 }
 
-// SubscribeRawTx subscribe to `rawtx` and receive its messages parsed.
-func (n *nodeMq) SubscribeRawTx(fn RawTxFunc) error {
-	return n.Subscribe(TopicRawTx, func(ctx context.Context, bb [][]byte) {
+// invalidTxMessage parses messages of the `invalidtx` topic for fn, passing those which
+// cannot be parsed to errFn.
+func invalidTxMessage(fn InvalidTxFunc, errFn ErrorFunc) MessageFunc {
+	return func(ctx context.Context, bb [][]byte) {
+		var tx InvalidTx
+		if err := json.Unmarshal(bb[1], &tx); err != nil {
+			errFn(ctx, err)
+			return
+		}
+		if tx.Hex != "" {
+			var err error
+			if tx.Tx, err = bt.NewTxFromString(tx.Hex); err != nil {
+				errFn(ctx, err)
+				return
+			}
+		}
+		fn(ctx, &tx)
+	}
+}
+
+// rawTxMessage parses messages of the `rawtx` topic for fn, passing those which cannot be
+// parsed to errFn.
+func rawTxMessage(fn RawTxFunc, errFn ErrorFunc) MessageFunc {
+	return func(ctx context.Context, bb [][]byte) {
 		tx, err := bt.NewTxFromBytes(bb[1])
 		if err != nil {
-			n.onErrFn(ctx, err)
+			errFn(ctx, err)
 			return
 		}
 		fn(ctx, tx)
-	})
+	}
 }
 
-// SubscribeRawBlock subscribe to `rawblock` and receive its messages parsed.
-func (n *nodeMq) SubscribeRawBlock(fn RawBlockFunc) error {
-	return n.Subscribe(TopicRawBlock, func(ctx context.Context, bb [][]byte) {
+// rawBlockMessage parses messages of the `rawblock` topic for fn, passing those which cannot
+// be parsed to errFn.
+func rawBlockMessage(fn RawBlockFunc, errFn ErrorFunc) MessageFunc {
+	return func(ctx context.Context, bb [][]byte) {
 		blk, err := bc.NewBlockFromBytes(bb[1])
 		if err != nil {
-			n.onErrFn(ctx, err)
+			errFn(ctx, err)
 			return
 		}
 		fn(ctx, blk)
-	})
+	}
 }
 
-// Unsubscribe from a topic on the bitcoin node 0MQ.
+// Unsubscribe from a topic on the bitcoin node 0MQ, no longer receiving its messages on
+// the socket.
 func (n *nodeMq) Unsubscribe(topic Topic) error {
 	if ok := n.cfg.topics[topic]; !ok {
 		return fmt.Errorf("%w: %s", ErrInvalidTopic, topic)
 	}
 
-	n.mu.Lock()
-	defer n.mu.Unlock()
+	sub, ok := func() (*subscription, bool) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
 
-	delete(n.subscriptions, topic)
-	return nil
+		sub, ok := n.subscriptions[topic]
+		delete(n.subscriptions, topic)
+		return sub, ok
+	}()
+	if !ok {
+		return nil
+	}
+	if sub.q != nil {
+		sub.q.close()
+	}
+
+	return n.setOption(zmq4.OptionUnsubscribe, topic)
 }
 
 func defaultOnError(_ context.Context, err error) {
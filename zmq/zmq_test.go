@@ -2,10 +2,13 @@ package zmq_test
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"net"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/bsv-blockchain/go-bc"
 	"github.com/bsv-blockchain/go-bt/v2"
@@ -14,13 +17,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/bsv-blockchain/go-bn/mocks"
+	"github.com/bsv-blockchain/go-bn/models"
 	"github.com/bsv-blockchain/go-bn/zmq"
 )
 
 var (
 	errAlreadySubscribed = errors.New("already subscribed to topic: hashtx")
 	errInvalidTopic      = errors.New("invalid topic: oh hello there")
-	errInvalidTopicRawTx = errors.New("invalid topic: rawtx")
 	errHostEmpty         = errors.New("host cannot be empty")
 	errYikes             = errors.New("YIKES")
 	errNoOptions         = errors.New("no options 4 u")
@@ -59,17 +62,13 @@ func TestNodeMQ_Subscribe(t *testing.T) {
 				zmq.TopicRemovedFromMempoolBlock,
 			},
 		},
-		"error subscribing to rawtx without being enabled": {
+		"successful subscription rawtx": {
 			topics: []zmq.Topic{zmq.TopicRawTx},
-			//nolint:err113 // test expectation, not production error
-			expErr: errors.New("invalid topic: rawtx"),
 		},
-		"error subscribing to rawblock without being enabled": {
+		"successful subscription rawblock": {
 			topics: []zmq.Topic{zmq.TopicRawBlock},
-			//nolint:err113 // test expectation, not production error
-			expErr: errors.New("invalid topic: rawblock"),
 		},
-		"error subscribing to all topics without raw enabled": {
+		"successful subscription to all topics": {
 			topics: []zmq.Topic{
 				zmq.TopicHashTx,
 				zmq.TopicHashBlock,
@@ -79,8 +78,6 @@ func TestNodeMQ_Subscribe(t *testing.T) {
 				zmq.TopicRawTx,
 				zmq.TopicRawBlock,
 			},
-			//nolint:err113 // test expectation, not production error
-			expErr: errors.New("invalid topic: rawtx"),
 		},
 		"successful subscription rawtx when enabled": {
 			topics: []zmq.Topic{zmq.TopicRawTx},
@@ -162,10 +159,9 @@ func TestNodeMQ_Unsubscribe(t *testing.T) {
 			subscribedTo:    []zmq.Topic{zmq.TopicHashTx},
 			unsubscribeFrom: []zmq.Topic{zmq.TopicInvalidTx},
 		},
-		"error when unsubscribing from raw topic when not enabled": {
-			subscribedTo:    []zmq.Topic{zmq.TopicHashTx},
+		"successful unsubscribe from raw topic": {
+			subscribedTo:    []zmq.Topic{zmq.TopicRawTx},
 			unsubscribeFrom: []zmq.Topic{zmq.TopicRawTx},
-			expErr:          errInvalidTopicRawTx,
 		},
 		"successful unsubscribe from raw topic when enabled": {
 			subscribedTo:    []zmq.Topic{zmq.TopicRawTx},
@@ -245,7 +241,8 @@ func TestNodeMQ_Connect(t *testing.T) {
 			expConnectError: errYikes,
 		},
 		"error setting option is returned": {
-			host: "tcp://localhost:12345",
+			host:   "tcp://localhost:12345",
+			topics: []zmq.Topic{zmq.TopicHashTx},
 			socketDialFn: func(s string) error {
 				return nil
 			},
@@ -254,7 +251,7 @@ func TestNodeMQ_Connect(t *testing.T) {
 			},
 			expOptions: []option{{
 				name:  "SUBSCRIBE",
-				value: "hash",
+				value: "hashtx",
 			}},
 			expCounts:       map[zmq.Topic]int{},
 			expConnectError: errNoOptions,
@@ -282,10 +279,6 @@ func TestNodeMQ_Connect(t *testing.T) {
 			closeFunc: func() error {
 				return errOhNo
 			},
-			expOptions: []option{{
-				name:  "SUBSCRIBE",
-				value: "hash",
-			}},
 			expCounts:             map[zmq.Topic]int{},
 			expErrorHandlerErrors: []error{errOhNo},
 		},
@@ -321,10 +314,6 @@ func TestNodeMQ_Connect(t *testing.T) {
 			closeFunc: func() error {
 				return nil
 			},
-			expOptions: []option{{
-				name:  "SUBSCRIBE",
-				value: "hash",
-			}},
 			expCounts: map[zmq.Topic]int{},
 			expErrorHandlerErrors: []error{
 				//nolint:err113 // test expectation, not production error
@@ -335,9 +324,9 @@ func TestNodeMQ_Connect(t *testing.T) {
 				errors.New("third error"),
 			},
 		},
-		"raw option present when WithRaw is called": {
-			host: "tcp://localhost:12345",
-			opts: []zmq.NodeMQOptFunc{zmq.WithRaw()},
+		"each subscribed topic is set as an option": {
+			host:   "tcp://localhost:12345",
+			topics: []zmq.Topic{zmq.TopicRawTx, zmq.TopicRawBlock},
 			socketDialFn: func(s string) error {
 				return nil
 			},
@@ -352,10 +341,10 @@ func TestNodeMQ_Connect(t *testing.T) {
 			},
 			expOptions: []option{{
 				name:  "SUBSCRIBE",
-				value: "hash",
+				value: "rawblock",
 			}, {
 				name:  "SUBSCRIBE",
-				value: "raw",
+				value: "rawtx",
 			}},
 			expCounts: map[zmq.Topic]int{},
 		},
@@ -420,7 +409,10 @@ func TestNodeMQ_Connect(t *testing.T) {
 			},
 			expOptions: []option{{
 				name:  "SUBSCRIBE",
-				value: "hash",
+				value: "hashtx",
+			}, {
+				name:  "SUBSCRIBE",
+				value: "invalidtx",
 			}},
 			expCounts: map[zmq.Topic]int{
 				zmq.TopicHashTx:    2,
@@ -454,6 +446,11 @@ func TestNodeMQ_Connect(t *testing.T) {
 				},
 				CloseFunc: test.closeFunc,
 			}
+			if socket.CloseFunc == nil {
+				socket.CloseFunc = func() error {
+					return nil
+				}
+			}
 
 			var errHandlerErrs []error
 
@@ -858,7 +855,6 @@ func TestNodeMQ_SubscribeRawTx(t *testing.T) {
 			var errs []string
 			c := zmq.NewNodeMQ(
 				zmq.WithHost("tcp://localhost:12345"),
-				zmq.WithRaw(),
 				zmq.WithCustomZMQSocket(socket),
 				zmq.WithErrorHandler(func(ctx context.Context, err error) {
 					defer wg.Done()
@@ -1013,7 +1009,6 @@ func TestNodeMQ_SubscribeRawBlock(t *testing.T) {
 			var errs []string
 			c := zmq.NewNodeMQ(
 				zmq.WithHost("tcp://localhost:12345"),
-				zmq.WithRaw(),
 				zmq.WithCustomZMQSocket(socket),
 				zmq.WithErrorHandler(func(ctx context.Context, err error) {
 					defer wg.Done()
@@ -1161,7 +1156,6 @@ func TestNodeMQ_SubscribeDiscardFromMempool(t *testing.T) {
 			var errs []string
 			c := zmq.NewNodeMQ(
 				zmq.WithHost("tcp://localhost:12345"),
-				zmq.WithRaw(),
 				zmq.WithCustomZMQSocket(socket),
 				zmq.WithErrorHandler(func(ctx context.Context, err error) {
 					defer wg.Done()
@@ -1309,7 +1303,6 @@ func TestNodeMQ_SubscribeRemovedFromMempoolBlock(t *testing.T) {
 			var errs []string
 			c := zmq.NewNodeMQ(
 				zmq.WithHost("tcp://localhost:12345"),
-				zmq.WithRaw(),
 				zmq.WithCustomZMQSocket(socket),
 				zmq.WithErrorHandler(func(ctx context.Context, err error) {
 					defer wg.Done()
@@ -1337,3 +1330,1051 @@ func TestNodeMQ_SubscribeRemovedFromMempoolBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeMQ_Connect_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	hashTx := zmq4.Msg{Frames: [][]byte{[]byte("hashtx"), {0xab}}}
+
+	var mu sync.Mutex
+	var options []string
+	var closed int
+	newSocket := func(recv ...func() (zmq4.Msg, error)) *mocks.SocketMock {
+		return &mocks.SocketMock{
+			DialFunc: func(string) error {
+				return nil
+			},
+			SetOptionFunc: func(opt string, v interface{}) error {
+				mu.Lock()
+				defer mu.Unlock()
+				options = append(options, opt+" "+v.(string))
+				return nil
+			},
+			RecvFunc: func() (zmq4.Msg, error) {
+				if len(recv) == 0 {
+					return zmq4.Msg{}, context.Canceled
+				}
+				defer func() { recv = recv[1:] }()
+				return recv[0]()
+			},
+			CloseFunc: func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				closed++
+				return nil
+			},
+		}
+	}
+
+	sockets := []*mocks.SocketMock{
+		newSocket(func() (zmq4.Msg, error) {
+			return hashTx, nil
+		}, func() (zmq4.Msg, error) {
+			return zmq4.Msg{}, errOhNo
+		}),
+		newSocket(func() (zmq4.Msg, error) {
+			return hashTx, nil
+		}),
+	}
+
+	var connects, disconnects []string
+	var errs []error
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithSocketFactory(func() zmq4.Socket {
+			defer func() { sockets = sockets[1:] }()
+			return sockets[0]
+		}),
+		zmq.WithReconnect(time.Millisecond, 5*time.Millisecond),
+		zmq.WithOnConnect(func(_ context.Context, host string) {
+			connects = append(connects, host)
+		}),
+		zmq.WithOnDisconnect(func(_ context.Context, host string, err error) {
+			disconnects = append(disconnects, host)
+			assert.Equal(t, errOhNo, err)
+		}),
+		zmq.WithErrorHandler(func(_ context.Context, err error) {
+			errs = append(errs, err)
+		}),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	require.NoError(t, z.SubscribeHashTx(func(_ context.Context, hash string) {
+		defer wg.Done()
+		assert.Equal(t, "ab", hash)
+	}))
+
+	require.NoError(t, z.Connect())
+	wg.Wait()
+
+	assert.Equal(t, []string{"tcp://localhost:12345", "tcp://localhost:12345"}, connects)
+	assert.Equal(t, []string{"tcp://localhost:12345"}, disconnects)
+	assert.Equal(t, []error{errOhNo}, errs)
+	assert.Equal(t, []string{"SUBSCRIBE hashtx", "SUBSCRIBE hashtx"}, options)
+	assert.Equal(t, 2, closed)
+}
+
+func TestNodeMQ_Connect_Stale(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newSocket := func() *mocks.SocketMock {
+		done := make(chan struct{})
+		return &mocks.SocketMock{
+			DialFunc: func(string) error {
+				return nil
+			},
+			SetOptionFunc: func(string, interface{}) error {
+				return nil
+			},
+			RecvFunc: func() (zmq4.Msg, error) {
+				select {
+				case <-done:
+					return zmq4.Msg{}, errOhNo
+				case <-ctx.Done():
+					return zmq4.Msg{}, context.Canceled
+				}
+			},
+			CloseFunc: func() error {
+				select {
+				case <-done:
+				default:
+					close(done)
+				}
+				return nil
+			},
+		}
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	var connects int
+	z := zmq.NewNodeMQ(
+		zmq.WithContext(ctx),
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithSocketFactory(func() zmq4.Socket {
+			return newSocket()
+		}),
+		zmq.WithReconnect(time.Millisecond, time.Millisecond),
+		zmq.WithStalenessTimeout(zmq.TopicHashBlock, 20*time.Millisecond),
+		zmq.WithOnConnect(func(context.Context, string) {
+			mu.Lock()
+			defer mu.Unlock()
+			connects++
+			if connects == 2 {
+				cancel()
+			}
+		}),
+		zmq.WithErrorHandler(func(_ context.Context, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+
+	require.NoError(t, z.Connect())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, connects)
+	require.Len(t, errs, 2)
+	require.ErrorIs(t, errs[0], zmq.ErrStale)
+	assert.Equal(t, errOhNo, errs[1])
+}
+
+func TestNodeMQ_Connect_SequenceGap(t *testing.T) {
+	t.Parallel()
+
+	msg := func(topic zmq.Topic, seq uint32) zmq4.Msg {
+		return zmq4.Msg{Frames: [][]byte{[]byte(topic), {0xab}, binary.LittleEndian.AppendUint32(nil, seq)}}
+	}
+
+	tests := map[string]struct {
+		msgs    []zmq4.Msg
+		gapFn   bool
+		expSeqs []uint32
+		expGaps []zmq.SequenceGap
+		expErrs int
+	}{
+		"no gaps": {
+			msgs:    []zmq4.Msg{msg(zmq.TopicHashTx, 7), msg(zmq.TopicHashBlock, 0), msg(zmq.TopicHashTx, 8)},
+			gapFn:   true,
+			expSeqs: []uint32{7, 8},
+		},
+		"gap is reported": {
+			msgs:    []zmq4.Msg{msg(zmq.TopicHashTx, 1), msg(zmq.TopicHashTx, 2), msg(zmq.TopicHashTx, 5)},
+			gapFn:   true,
+			expSeqs: []uint32{1, 2, 5},
+			expGaps: []zmq.SequenceGap{{Topic: zmq.TopicHashTx, Expected: 3, Received: 5}},
+		},
+		"restart is reported": {
+			msgs:    []zmq4.Msg{msg(zmq.TopicHashTx, 41), msg(zmq.TopicHashTx, 0)},
+			gapFn:   true,
+			expSeqs: []uint32{41, 0},
+			expGaps: []zmq.SequenceGap{{Topic: zmq.TopicHashTx, Expected: 42, Received: 0}},
+		},
+		"overflow is not a gap": {
+			msgs:    []zmq4.Msg{msg(zmq.TopicHashTx, 0xffffffff), msg(zmq.TopicHashTx, 0)},
+			gapFn:   true,
+			expSeqs: []uint32{0xffffffff, 0},
+		},
+		"gap is reported as error without handler": {
+			msgs:    []zmq4.Msg{msg(zmq.TopicHashTx, 1), msg(zmq.TopicHashTx, 3)},
+			expSeqs: []uint32{1, 3},
+			expErrs: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			msgs := test.msgs
+			socket := &mocks.SocketMock{
+				DialFunc: func(string) error {
+					return nil
+				},
+				SetOptionFunc: func(string, interface{}) error {
+					return nil
+				},
+				RecvFunc: func() (zmq4.Msg, error) {
+					if len(msgs) == 0 {
+						return zmq4.Msg{}, context.Canceled
+					}
+					defer func() { msgs = msgs[1:] }()
+					return msgs[0], nil
+				},
+				CloseFunc: func() error {
+					return nil
+				},
+			}
+
+			var gaps []zmq.SequenceGap
+			var errs []error
+			opts := []zmq.NodeMQOptFunc{
+				zmq.WithHost("tcp://localhost:12345"),
+				zmq.WithCustomZMQSocket(socket),
+				zmq.WithErrorHandler(func(_ context.Context, err error) {
+					require.ErrorIs(t, err, zmq.ErrSequenceGap)
+					errs = append(errs, err)
+				}),
+			}
+			if test.gapFn {
+				opts = append(opts, zmq.WithGapHandler(func(_ context.Context, gap *zmq.SequenceGap) {
+					gaps = append(gaps, *gap)
+				}))
+			}
+			z := zmq.NewNodeMQ(opts...)
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			seqs := map[uint32]bool{}
+			wg.Add(len(test.expSeqs))
+			require.NoError(t, z.Subscribe(zmq.TopicHashTx, func(ctx context.Context, _ [][]byte) {
+				defer wg.Done()
+				seq, ok := zmq.SequenceFromContext(ctx)
+				assert.True(t, ok)
+				mu.Lock()
+				defer mu.Unlock()
+				seqs[seq] = true
+			}))
+
+			require.NoError(t, z.Connect())
+			wg.Wait()
+
+			for _, seq := range test.expSeqs {
+				assert.True(t, seqs[seq], "missing sequence %d", seq)
+			}
+			assert.Equal(t, test.expGaps, gaps)
+			assert.Len(t, errs, test.expErrs)
+		})
+	}
+}
+
+func TestNodeMQ_Connect_Endpoints(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	dialled := map[string]bool{}
+	newSocket := func(topic zmq.Topic) *mocks.SocketMock {
+		sent := false
+		subscribed := false
+		return &mocks.SocketMock{
+			DialFunc: func(addr string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				dialled[addr] = true
+				return nil
+			},
+			SetOptionFunc: func(opt string, v interface{}) error {
+				subscribed = subscribed || (opt == zmq4.OptionSubscribe && v == string(topic))
+				return nil
+			},
+			RecvFunc: func() (zmq4.Msg, error) {
+				if sent || !subscribed {
+					return zmq4.Msg{}, context.Canceled
+				}
+				sent = true
+				return zmq4.Msg{Frames: [][]byte{[]byte(topic), {0xab}}}, nil
+			},
+			CloseFunc: func() error {
+				return nil
+			},
+		}
+	}
+
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://node:28332"),
+		zmq.WithCustomZMQSocket(newSocket(zmq.TopicHashBlock)),
+		zmq.WithSocketFactory(func() zmq4.Socket {
+			return newSocket(zmq.TopicHashTx)
+		}),
+		zmq.WithNotifications([]*models.ZMQNotification{{
+			Notification: "pubhashblock",
+			Address:      "tcp://0.0.0.0:28332",
+		}, {
+			Notification: "pubhashtx",
+			Address:      "tcp://127.0.0.1:28333",
+		}, {
+			Notification: "pubrawtx",
+			Address:      "tcp://127.0.0.1:28334",
+		}}),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	require.NoError(t, z.SubscribeHashBlock(func(context.Context, string) {
+		wg.Done()
+	}))
+	require.NoError(t, z.SubscribeHashTx(func(context.Context, string) {
+		wg.Done()
+	}))
+
+	require.NoError(t, z.Connect())
+	wg.Wait()
+
+	assert.Equal(t, map[string]bool{
+		"tcp://node:28332":      true,
+		"tcp://127.0.0.1:28333": true,
+		"tcp://127.0.0.1:28334": true,
+	}, dialled)
+}
+
+func TestNodeMQ_Channel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts       []zmq.NodeMQOptFunc
+		expSeqs    []uint32
+		expDropped uint64
+	}{
+		"all messages delivered in order": {
+			opts:    []zmq.NodeMQOptFunc{zmq.WithBufferSize(5)},
+			expSeqs: []uint32{0, 1, 2, 3, 4},
+		},
+		"newest dropped when full": {
+			opts:       []zmq.NodeMQOptFunc{zmq.WithBufferSize(2), zmq.WithBackpressure(zmq.BackpressureDropNewest)},
+			expSeqs:    []uint32{0, 1},
+			expDropped: 3,
+		},
+		"oldest dropped when full": {
+			opts:       []zmq.NodeMQOptFunc{zmq.WithBufferSize(2), zmq.WithBackpressure(zmq.BackpressureDropOldest)},
+			expSeqs:    []uint32{3, 4},
+			expDropped: 3,
+		},
+		"oldest dropped from the smallest buffer": {
+			opts:       []zmq.NodeMQOptFunc{zmq.WithBufferSize(1), zmq.WithBackpressure(zmq.BackpressureDropOldest)},
+			expSeqs:    []uint32{4},
+			expDropped: 4,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var seq uint32
+			socket := &mocks.SocketMock{
+				DialFunc: func(string) error {
+					return nil
+				},
+				SetOptionFunc: func(string, interface{}) error {
+					return nil
+				},
+				RecvFunc: func() (zmq4.Msg, error) {
+					if seq == 5 {
+						return zmq4.Msg{}, context.Canceled
+					}
+					defer func() { seq++ }()
+					return zmq4.Msg{Frames: [][]byte{
+						[]byte(zmq.TopicHashTx), {0xab}, binary.LittleEndian.AppendUint32(nil, seq),
+					}}, nil
+				},
+				CloseFunc: func() error {
+					return nil
+				},
+			}
+
+			z := zmq.NewNodeMQ(append(test.opts,
+				zmq.WithHost("tcp://localhost:12345"),
+				zmq.WithCustomZMQSocket(socket),
+			)...)
+
+			ch, err := z.Channel(zmq.TopicHashTx)
+			require.NoError(t, err)
+			_, err = z.Channel(zmq.TopicHashTx)
+			require.ErrorIs(t, err, zmq.ErrAlreadySubscribed)
+
+			require.NoError(t, z.Connect())
+
+			var seqs []uint32
+			for msg := range ch {
+				assert.True(t, msg.HasSequence)
+				assert.Equal(t, zmq.TopicHashTx, msg.Topic)
+				seqs = append(seqs, msg.Sequence)
+			}
+			assert.Equal(t, test.expSeqs, seqs)
+			assert.Equal(t, test.expDropped, z.Dropped(zmq.TopicHashTx))
+		})
+	}
+}
+
+func TestNodeMQ_Channel_InvalidBufferSize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		size         int
+		backpressure zmq.Backpressure
+	}{
+		"negative": {
+			size: -1,
+		},
+		"zero dropping oldest": {
+			size:         0,
+			backpressure: zmq.BackpressureDropOldest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			z := zmq.NewNodeMQ(
+				zmq.WithHost("tcp://localhost:12345"),
+				zmq.WithCustomZMQSocket(&mocks.SocketMock{}),
+				zmq.WithOrderedDelivery(),
+				zmq.WithBufferSize(test.size),
+				zmq.WithBackpressure(test.backpressure),
+			)
+
+			_, err := z.Channel(zmq.TopicHashTx)
+			require.ErrorIs(t, err, zmq.ErrInvalidBufferSize)
+			_, err = z.Messages(zmq.TopicHashTx)
+			require.ErrorIs(t, err, zmq.ErrInvalidBufferSize)
+			require.ErrorIs(t, z.Subscribe(zmq.TopicHashTx, func(context.Context, [][]byte) {}),
+				zmq.ErrInvalidBufferSize)
+			require.ErrorIs(t, z.Connect(), zmq.ErrInvalidBufferSize)
+		})
+	}
+}
+
+func TestNodeMQ_Messages(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var seq uint32
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(string, interface{}) error {
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			if ctx.Err() != nil {
+				return zmq4.Msg{}, context.Canceled
+			}
+			defer func() { seq++ }()
+			return zmq4.Msg{Frames: [][]byte{
+				[]byte(zmq.TopicHashBlock), {0xab}, binary.LittleEndian.AppendUint32(nil, seq),
+			}}, nil
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+
+	z := zmq.NewNodeMQ(
+		zmq.WithContext(ctx),
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+		zmq.WithBufferSize(1),
+	)
+
+	msgs, err := z.Messages(zmq.TopicHashBlock)
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- z.Connect()
+	}()
+
+	var seqs []uint32
+	for msg := range msgs {
+		seqs = append(seqs, msg.Sequence)
+		if len(seqs) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []uint32{0, 1, 2}, seqs)
+
+	// Breaking unsubscribes, so the topic is free again.
+	_, err = z.Channel(zmq.TopicHashBlock)
+	require.NoError(t, err)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestNodeMQ_Connect_OrderedDelivery(t *testing.T) {
+	t.Parallel()
+
+	var seq uint32
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(string, interface{}) error {
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			if seq == 100 {
+				return zmq4.Msg{}, context.Canceled
+			}
+			defer func() { seq++ }()
+			return zmq4.Msg{Frames: [][]byte{
+				[]byte(zmq.TopicHashTx), {0xab}, binary.LittleEndian.AppendUint32(nil, seq),
+			}}, nil
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+		zmq.WithOrderedDelivery(),
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(100)
+	var seqs []uint32
+	require.NoError(t, z.Subscribe(zmq.TopicHashTx, func(ctx context.Context, _ [][]byte) {
+		defer wg.Done()
+		seq, ok := zmq.SequenceFromContext(ctx)
+		assert.True(t, ok)
+		seqs = append(seqs, seq)
+	}))
+
+	require.NoError(t, z.Connect())
+	wg.Wait()
+
+	require.Len(t, seqs, 100)
+	for i, seq := range seqs {
+		assert.Equal(t, uint32(i), seq)
+	}
+}
+
+func TestNodeMQ_Close(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handlerDelay time.Duration
+		closeTimeout time.Duration
+		expErr       error
+	}{
+		"waits for in-flight handlers": {
+			handlerDelay: 20 * time.Millisecond,
+			closeTimeout: time.Second,
+		},
+		"gives up on handlers at deadline": {
+			handlerDelay: time.Second,
+			closeTimeout: 20 * time.Millisecond,
+			expErr:       context.DeadlineExceeded,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var options []string
+			closed := make(chan struct{})
+			sent := false
+			socket := &mocks.SocketMock{
+				DialFunc: func(string) error {
+					return nil
+				},
+				SetOptionFunc: func(opt string, v interface{}) error {
+					mu.Lock()
+					defer mu.Unlock()
+					options = append(options, opt+" "+v.(string))
+					return nil
+				},
+				RecvFunc: func() (zmq4.Msg, error) {
+					if !sent {
+						sent = true
+						return zmq4.Msg{Frames: [][]byte{[]byte(zmq.TopicHashTx), {0xab}}}, nil
+					}
+					<-closed
+					return zmq4.Msg{}, errOhNo
+				},
+				CloseFunc: func() error {
+					close(closed)
+					return nil
+				},
+			}
+
+			z := zmq.NewNodeMQ(
+				zmq.WithHost("tcp://localhost:12345"),
+				zmq.WithCustomZMQSocket(socket),
+				zmq.WithErrorHandler(func(_ context.Context, err error) {
+					assert.NoError(t, err)
+				}),
+			)
+
+			started := make(chan struct{})
+			var handled bool
+			require.NoError(t, z.SubscribeHashTx(func(context.Context, string) {
+				close(started)
+				time.Sleep(test.handlerDelay)
+				mu.Lock()
+				defer mu.Unlock()
+				handled = true
+			}))
+
+			done := make(chan error)
+			go func() {
+				done <- z.Connect()
+			}()
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), test.closeTimeout)
+			defer cancel()
+
+			err := z.Close(ctx)
+			require.NoError(t, <-done)
+			mu.Lock()
+			defer mu.Unlock()
+			if test.expErr != nil {
+				require.ErrorIs(t, err, test.expErr)
+				assert.False(t, handled)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, handled)
+			}
+			assert.Equal(t, []string{"SUBSCRIBE hashtx", "UNSUBSCRIBE hashtx"}, options)
+
+			require.ErrorIs(t, z.Connect(), zmq.ErrClosed)
+		})
+	}
+}
+
+func TestNodeMQ_Close_AfterConnect(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var options []string
+	var closes int
+	sent := false
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(opt string, v interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			options = append(options, opt+" "+v.(string))
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			if !sent {
+				sent = true
+				return zmq4.Msg{Frames: [][]byte{[]byte(zmq.TopicHashTx), {0xab}}}, nil
+			}
+			return zmq4.Msg{}, context.Canceled
+		},
+		CloseFunc: func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			closes++
+			return nil
+		},
+	}
+
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+		zmq.WithErrorHandler(func(_ context.Context, err error) {
+			assert.NoError(t, err)
+		}),
+	)
+	require.NoError(t, z.SubscribeHashTx(func(context.Context, string) {}))
+	require.NoError(t, z.Connect())
+
+	// The socket was closed as Connect returned, so is left alone.
+	require.NoError(t, z.Close(context.TODO()))
+	require.NoError(t, z.Close(context.TODO()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, closes)
+	assert.Equal(t, []string{"SUBSCRIBE hashtx"}, options)
+}
+
+func TestNodeMQ_Close_Reconnecting(t *testing.T) {
+	t.Parallel()
+
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(string, interface{}) error {
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			return zmq4.Msg{}, errOhNo
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+
+	// The new socket is closed by Close while dialling, failing the dial.
+	dialling := make(chan struct{})
+	closed := make(chan struct{})
+	reconnected := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			close(dialling)
+			<-closed
+			return errYikes
+		},
+		SetOptionFunc: func(string, interface{}) error {
+			return nil
+		},
+		CloseFunc: func() error {
+			close(closed)
+			return nil
+		},
+	}
+
+	z := zmq.NewNodeMQ(
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+		zmq.WithSocketFactory(func() zmq4.Socket {
+			return reconnected
+		}),
+		zmq.WithReconnect(time.Millisecond, time.Millisecond),
+		zmq.WithErrorHandler(func(context.Context, error) {}),
+	)
+	require.NoError(t, z.SubscribeHashTx(func(context.Context, string) {}))
+
+	done := make(chan error)
+	go func() {
+		done <- z.Connect()
+	}()
+	<-dialling
+
+	require.NoError(t, z.Close(context.TODO()))
+	require.NoError(t, <-done)
+}
+
+func TestNodeMQ_Subscribe_Connected(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var options []string
+	socket := &mocks.SocketMock{
+		DialFunc: func(string) error {
+			return nil
+		},
+		SetOptionFunc: func(opt string, v interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			options = append(options, opt+" "+v.(string))
+			return nil
+		},
+		RecvFunc: func() (zmq4.Msg, error) {
+			<-ctx.Done()
+			return zmq4.Msg{}, context.Canceled
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+
+	connected := make(chan struct{})
+	z := zmq.NewNodeMQ(
+		zmq.WithContext(ctx),
+		zmq.WithHost("tcp://localhost:12345"),
+		zmq.WithCustomZMQSocket(socket),
+		zmq.WithOnConnect(func(context.Context, string) {
+			close(connected)
+		}),
+	)
+	require.NoError(t, z.SubscribeHashBlock(func(context.Context, string) {}))
+
+	done := make(chan error)
+	go func() {
+		done <- z.Connect()
+	}()
+	<-connected
+
+	require.NoError(t, z.SubscribeRawTx(func(context.Context, *bt.Tx) {}))
+	require.NoError(t, z.Unsubscribe(zmq.TopicRawTx))
+	require.NoError(t, z.Unsubscribe(zmq.TopicHashTx))
+
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []string{"SUBSCRIBE hashblock", "SUBSCRIBE rawtx", "UNSUBSCRIBE rawtx"}, options)
+}
+
+func TestNodeMQ_SubscribeInvalidTx(t *testing.T) {
+	t.Parallel()
+
+	const txHex = "0200000001fbb877c83aaf682f74611628b0088254c8094fff9cf6328ed969c587112b8fc90000000000feffffff025e2e1a1e010000001976a91401becd83278806a62cd87bed129faa72af38a0d588ac00e1f505000000001976a91467e701e630adaee761583a894b53d4356028ca0b88ac00000000"
+
+	tests := map[string]struct {
+		body    string
+		expTx   *zmq.InvalidTx
+		expErrs []string
+	}{
+		"double spend": {
+			body: `{"fromBlock":false,"txid":"abc123","hex":"` + txHex + `","source":"p2p","address":"10.0.0.1:8333",` +
+				`"nodeId":7,"size":120,"isInvalid":true,"isDoubleSpendDetected":true,"isStandardTx":true,` +
+				`"rejectionCode":18,"rejectionReason":"txn-double-spend-detected",` +
+				`"collidedWith":[{"txid":"def456","size":191,"hex":"0100"}],"rejectionTime":"2021-06-01T10:00:00Z"}`,
+			expTx: &zmq.InvalidTx{
+				TxID:                  "abc123",
+				Hex:                   txHex,
+				Source:                "p2p",
+				Address:               "10.0.0.1:8333",
+				NodeID:                7,
+				Size:                  120,
+				IsInvalid:             true,
+				IsDoubleSpendDetected: true,
+				IsStandardTx:          true,
+				RejectionCode:         18,
+				RejectionReason:       "txn-double-spend-detected",
+				CollidedWith:          []zmq.InvalidTxCollision{{TxID: "def456", Size: 191, Hex: "0100"}},
+				RejectionTime:         "2021-06-01T10:00:00Z",
+			},
+		},
+		"from block without hex": {
+			body: `{"fromBlock":true,"txid":"abc123","blockhash":"00000000000000000f0d","blockheight":700000,` +
+				`"blocktime":1620000000,"size":5000000,"isInvalid":true,"isMissingInputs":true,` +
+				`"rejectionCode":16,"rejectionReason":"bad-txns-inputs-missingorspent"}`,
+			expTx: &zmq.InvalidTx{
+				FromBlock:       true,
+				TxID:            "abc123",
+				BlockHash:       "00000000000000000f0d",
+				BlockHeight:     700000,
+				BlockTime:       1620000000,
+				Size:            5000000,
+				IsInvalid:       true,
+				IsMissingInputs: true,
+				RejectionCode:   16,
+				RejectionReason: "bad-txns-inputs-missingorspent",
+			},
+		},
+		"errors are relayed to the error handler": {
+			body:    `{"txid":"abc123","size"}`,
+			expErrs: []string{"invalid character '}' after object key"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			wg.Add(1)
+
+			sent := false
+			socket := &mocks.SocketMock{
+				DialFunc: func(string) error {
+					return nil
+				},
+				SetOptionFunc: func(string, interface{}) error {
+					return nil
+				},
+				RecvFunc: func() (zmq4.Msg, error) {
+					if sent {
+						return zmq4.Msg{}, context.Canceled
+					}
+					sent = true
+					return zmq4.Msg{Frames: [][]byte{[]byte(zmq.TopicInvalidTx), []byte(test.body)}}, nil
+				},
+				CloseFunc: func() error {
+					return nil
+				},
+			}
+
+			var errs []string
+			c := zmq.NewNodeMQ(
+				zmq.WithHost("tcp://localhost:12345"),
+				zmq.WithCustomZMQSocket(socket),
+				zmq.WithErrorHandler(func(_ context.Context, err error) {
+					defer wg.Done()
+					errs = append(errs, err.Error())
+				}),
+			)
+
+			var tx *zmq.InvalidTx
+			require.NoError(t, c.SubscribeInvalidTx(func(_ context.Context, itx *zmq.InvalidTx) {
+				defer wg.Done()
+				tx = itx
+			}))
+
+			require.NoError(t, c.Connect())
+			wg.Wait()
+
+			assert.Equal(t, test.expErrs, errs)
+			if test.expTx == nil {
+				assert.Nil(t, tx)
+				return
+			}
+
+			require.NotNil(t, tx)
+			if test.expTx.Hex != "" {
+				require.NotNil(t, tx.Tx)
+				assert.Equal(t, test.expTx.Hex, tx.Tx.String())
+				test.expTx.Tx = tx.Tx
+			}
+			assert.Equal(t, test.expTx, tx)
+		})
+	}
+}
+
+// publisher binds a PUB socket on a free local port, returning it with its address.
+func publisher(t *testing.T, addr string) (zmq4.Socket, string) {
+	t.Helper()
+
+	if addr == "" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr = "tcp://" + l.Addr().String()
+		require.NoError(t, l.Close())
+	}
+
+	pub := zmq4.NewPub(context.Background())
+	require.NoError(t, pub.Listen(addr))
+	return pub, addr
+}
+
+// publish sends the message on pub until its hash is received, as subscriptions take a moment
+// to reach the publisher. Messages sent earlier may still arrive, so are skipped.
+func publish(t *testing.T, pub zmq4.Socket, received <-chan string, topic zmq.Topic, body byte) {
+	t.Helper()
+
+	tk := time.NewTicker(10 * time.Millisecond)
+	defer tk.Stop()
+	timeout := time.After(10 * time.Second)
+	for {
+		require.NoError(t, pub.Send(zmq4.NewMsgFrom([]byte(topic), []byte{body})))
+		select {
+		case s := <-received:
+			if s == hex.EncodeToString([]byte{body}) {
+				return
+			}
+		case <-tk.C:
+		case <-timeout:
+			require.FailNow(t, "message not received", topic)
+		}
+	}
+}
+
+func TestNodeMQ_Connect_Reconnect_DefaultSocket(t *testing.T) {
+	t.Parallel()
+
+	pub, addr := publisher(t, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	z := zmq.NewNodeMQ(
+		zmq.WithHost(addr),
+		zmq.WithContext(ctx),
+		zmq.WithReconnect(10*time.Millisecond, 100*time.Millisecond),
+		zmq.WithErrorHandler(func(context.Context, error) {}),
+	)
+
+	received := make(chan string, 16)
+	require.NoError(t, z.SubscribeHashTx(func(_ context.Context, hash string) {
+		received <- hash
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- z.Connect()
+	}()
+
+	publish(t, pub, received, zmq.TopicHashTx, 0xab)
+
+	// The publisher restarts, such as when the node does.
+	require.NoError(t, pub.Close())
+	pub, _ = publisher(t, addr)
+	defer func() { _ = pub.Close() }()
+
+	publish(t, pub, received, zmq.TopicHashTx, 0xcd)
+
+	require.NoError(t, z.Close(context.Background()))
+	require.NoError(t, <-done)
+}
+
+func TestNodeMQ_Connect_Endpoints_DefaultSocket(t *testing.T) {
+	t.Parallel()
+
+	txPub, txAddr := publisher(t, "")
+	defer func() { _ = txPub.Close() }()
+	blockPub, blockAddr := publisher(t, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	z := zmq.NewNodeMQ(
+		zmq.WithHost(txAddr),
+		zmq.WithTopicHost(zmq.TopicHashBlock, blockAddr),
+		zmq.WithContext(ctx),
+		zmq.WithReconnect(10*time.Millisecond, 100*time.Millisecond),
+		zmq.WithErrorHandler(func(context.Context, error) {}),
+	)
+
+	txs, blocks := make(chan string, 16), make(chan string, 16)
+	require.NoError(t, z.SubscribeHashTx(func(_ context.Context, hash string) {
+		txs <- hash
+	}))
+	require.NoError(t, z.SubscribeHashBlock(func(_ context.Context, hash string) {
+		blocks <- hash
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- z.Connect()
+	}()
+
+	publish(t, txPub, txs, zmq.TopicHashTx, 0x01)
+	publish(t, blockPub, blocks, zmq.TopicHashBlock, 0x02)
+
+	// Restarting one publisher leaves the other endpoint connected.
+	require.NoError(t, blockPub.Close())
+	publish(t, txPub, txs, zmq.TopicHashTx, 0x03)
+	blockPub, _ = publisher(t, blockAddr)
+	defer func() { _ = blockPub.Close() }()
+	publish(t, blockPub, blocks, zmq.TopicHashBlock, 0x04)
+	publish(t, txPub, txs, zmq.TopicHashTx, 0x05)
+
+	require.NoError(t, z.Close(context.Background()))
+	require.NoError(t, <-done)
+}